@@ -1,6 +1,8 @@
 package accounts
 
-type account struct {
+import "encoding/json"
+
+type Account struct {
 	Country                     string    `json:"country"`
 	BaseCurrency                *string   `json:"base_currency,omitempty"`
 	BankID                      *string   `json:"bank_id,omitempty"`
@@ -17,9 +19,12 @@ type account struct {
 	JointAccount                *bool     `json:"joint_account,omitempty"`
 	AccountMatchingOptOut       *bool     `json:"account_matching_opt_out,omitempty"`
 	SecondaryIdentification     *string   `json:"secondary_identification,omitempty"`
+
+	// Extra holds the attributes returned by the API that this library does not know about
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
-// AccountBuilder returns a builder for account struct
+// AccountBuilder returns a builder for Account struct
 type AccountBuilder interface {
 	Country(string) AccountBuilder
 	BaseCurrency(string) AccountBuilder
@@ -37,7 +42,8 @@ type AccountBuilder interface {
 	JointAccount(bool) AccountBuilder
 	AccountMatchingOptOut(bool) AccountBuilder
 	SecondaryIdentification(string) AccountBuilder
-	Build() account
+	Extra(map[string]json.RawMessage) AccountBuilder
+	Build() Account
 }
 
 type accountBuilder struct {
@@ -57,6 +63,7 @@ type accountBuilder struct {
 	jointAccount                *bool
 	accountMatchingOptOut       *bool
 	secondaryIdentification     *string
+	extra                       map[string]json.RawMessage
 }
 
 func (ab *accountBuilder) Country(value string) AccountBuilder {
@@ -139,8 +146,13 @@ func (ab *accountBuilder) SecondaryIdentification(value string) AccountBuilder {
 	return ab
 }
 
-func (ab *accountBuilder) Build() account {
-	return account{
+func (ab *accountBuilder) Extra(value map[string]json.RawMessage) AccountBuilder {
+	ab.extra = value
+	return ab
+}
+
+func (ab *accountBuilder) Build() Account {
+	return Account{
 		Country:                     ab.country,
 		BaseCurrency:                ab.baseCurrency,
 		BankID:                      ab.bankID,
@@ -157,6 +169,7 @@ func (ab *accountBuilder) Build() account {
 		JointAccount:                ab.jointAccount,
 		AccountMatchingOptOut:       ab.accountMatchingOptOut,
 		SecondaryIdentification:     ab.secondaryIdentification,
+		Extra:                       ab.extra,
 	}
 }
 
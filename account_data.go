@@ -1,35 +1,50 @@
 package accounts
 
-type accountData struct {
-	ID             string  `json:"id"`
-	OrganisationID string  `json:"organisation_id"`
-	Type           string  `json:"type"`
-	CreatedOn      *string `json:"created_on,omitempty"`
-	ModifiedOn     *string `json:"modified_on,omitempty"`
-	Version        *int    `json:"version,omitempty"`
-	Attributes     account `json:"attributes"`
+import (
+	"encoding/json"
+	"time"
+)
+
+const accountsType = "accounts"
+
+type AccountData struct {
+	ID             string         `json:"id"`
+	OrganisationID string         `json:"organisation_id"`
+	Type           string         `json:"type"`
+	CreatedOn      *time.Time     `json:"created_on,omitempty"`
+	ModifiedOn     *time.Time     `json:"modified_on,omitempty"`
+	Version        *int           `json:"version,omitempty"`
+	Attributes     Account        `json:"attributes"`
+	Relationships  *Relationships `json:"relationships,omitempty"`
+
+	// Extra holds the fields returned by the API that this library does not know about
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
-// AccountDataBuilder returns a builder for accountData struct
+// AccountDataBuilder returns a builder for AccountData struct
 type AccountDataBuilder interface {
 	ID(string) AccountDataBuilder
 	OrganisationID(string) AccountDataBuilder
 	Type(string) AccountDataBuilder
-	CreatedOn(string) AccountDataBuilder
-	ModifiedOn(string) AccountDataBuilder
+	CreatedOn(time.Time) AccountDataBuilder
+	ModifiedOn(time.Time) AccountDataBuilder
 	Version(int) AccountDataBuilder
-	Attributes(account) AccountDataBuilder
-	Build() accountData
+	Attributes(Account) AccountDataBuilder
+	Relationships(Relationships) AccountDataBuilder
+	Extra(map[string]json.RawMessage) AccountDataBuilder
+	Build() AccountData
 }
 
 type accountDataBuilder struct {
 	id              string
 	organisationID  string
 	accountDataType string
-	createdOn       *string
-	modifiedOn      *string
+	createdOn       *time.Time
+	modifiedOn      *time.Time
 	version         *int
-	attributes      account
+	attributes      Account
+	relationships   *Relationships
+	extra           map[string]json.RawMessage
 }
 
 func (ab *accountDataBuilder) ID(value string) AccountDataBuilder {
@@ -47,12 +62,12 @@ func (ab *accountDataBuilder) Type(value string) AccountDataBuilder {
 	return ab
 }
 
-func (ab *accountDataBuilder) CreatedOn(value string) AccountDataBuilder {
+func (ab *accountDataBuilder) CreatedOn(value time.Time) AccountDataBuilder {
 	ab.createdOn = &value
 	return ab
 }
 
-func (ab *accountDataBuilder) ModifiedOn(value string) AccountDataBuilder {
+func (ab *accountDataBuilder) ModifiedOn(value time.Time) AccountDataBuilder {
 	ab.modifiedOn = &value
 	return ab
 }
@@ -62,13 +77,23 @@ func (ab *accountDataBuilder) Version(value int) AccountDataBuilder {
 	return ab
 }
 
-func (ab *accountDataBuilder) Attributes(value account) AccountDataBuilder {
+func (ab *accountDataBuilder) Attributes(value Account) AccountDataBuilder {
 	ab.attributes = value
 	return ab
 }
 
-func (ab *accountDataBuilder) Build() accountData {
-	return accountData{
+func (ab *accountDataBuilder) Relationships(value Relationships) AccountDataBuilder {
+	ab.relationships = &value
+	return ab
+}
+
+func (ab *accountDataBuilder) Extra(value map[string]json.RawMessage) AccountDataBuilder {
+	ab.extra = value
+	return ab
+}
+
+func (ab *accountDataBuilder) Build() AccountData {
+	return AccountData{
 		ID:             ab.id,
 		OrganisationID: ab.organisationID,
 		Type:           ab.accountDataType,
@@ -76,6 +101,8 @@ func (ab *accountDataBuilder) Build() accountData {
 		ModifiedOn:     ab.modifiedOn,
 		Version:        ab.version,
 		Attributes:     ab.attributes,
+		Relationships:  ab.relationships,
+		Extra:          ab.extra,
 	}
 }
 
@@ -1,20 +1,50 @@
 package accounts
 
+import (
+	"encoding/json"
+
+	"github.com/razvanmuscalu/form3-accounts-client/internal/patch"
+)
+
 type accountDataRequest struct {
-	AccountData accountData `json:"data"`
+	AccountData AccountData `json:"data"`
+}
+
+// accountPatchRequest is the payload of an update, whose attributes are encoded so that a partial update
+// leaves the country out rather than sending an empty one
+type accountPatchRequest struct {
+	AccountData AccountData
+}
+
+func (pr accountPatchRequest) MarshalJSON() ([]byte, error) {
+	encoded, err := json.Marshal(pr.AccountData)
+	if err != nil {
+		return nil, err
+	}
+	var data map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &data); err != nil {
+		return nil, err
+	}
+
+	data["attributes"], err = patch.Attributes(pr.AccountData.Attributes, "country")
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(map[string]map[string]json.RawMessage{"data": data})
 }
 
 // AccountDataRequestBuilder returns a builder for accountDataRequest struct
 type AccountDataRequestBuilder interface {
-	AccountData(accountData) AccountDataRequestBuilder
+	AccountData(AccountData) AccountDataRequestBuilder
 	Build() accountDataRequest
 }
 
 type accountDataRequestBuilder struct {
-	accountData accountData
+	accountData AccountData
 }
 
-func (ab *accountDataRequestBuilder) AccountData(value accountData) AccountDataRequestBuilder {
+func (ab *accountDataRequestBuilder) AccountData(value AccountData) AccountDataRequestBuilder {
 	ab.accountData = value
 	return ab
 }
@@ -0,0 +1,196 @@
+package accounts
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const accountRoutingsType = "account_routings"
+
+// AccountGenerator tells who generates the account numbers of a routing
+type AccountGenerator string
+
+// the generators of account numbers
+const (
+	AccountGeneratorForm3        AccountGenerator = "form3"
+	AccountGeneratorOrganisation AccountGenerator = "organisation"
+)
+
+// AccountProvisioner tells who provisions the accounts of a routing
+type AccountProvisioner string
+
+// the provisioners of accounts
+const (
+	AccountProvisionerForm3        AccountProvisioner = "form3"
+	AccountProvisionerOrganisation AccountProvisioner = "organisation"
+)
+
+// MatchType tells how the account numbers of a routing are matched against its Match
+type MatchType string
+
+// the ways account numbers are matched
+const (
+	MatchExact  MatchType = "exact"
+	MatchPrefix MatchType = "prefix"
+)
+
+// AccountRouting routes the account numbers matching it to an organisation
+type AccountRouting struct {
+	AccountGenerator   AccountGenerator   `json:"account_generator,omitempty"`
+	AccountProvisioner AccountProvisioner `json:"account_provisioner,omitempty"`
+	MatchType          MatchType          `json:"match_type,omitempty"`
+	Match              string             `json:"match,omitempty"`
+	Priority           *int               `json:"priority,omitempty"`
+
+	// Extra holds the attributes returned by the API that this library does not know about
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// AccountRoutingBuilder returns a builder for AccountRouting struct
+type AccountRoutingBuilder interface {
+	AccountGenerator(AccountGenerator) AccountRoutingBuilder
+	AccountProvisioner(AccountProvisioner) AccountRoutingBuilder
+	MatchType(MatchType) AccountRoutingBuilder
+	Match(string) AccountRoutingBuilder
+	Priority(int) AccountRoutingBuilder
+	Build() AccountRouting
+}
+
+type accountRoutingBuilder struct {
+	accountGenerator   AccountGenerator
+	accountProvisioner AccountProvisioner
+	matchType          MatchType
+	match              string
+	priority           *int
+}
+
+func (ab *accountRoutingBuilder) AccountGenerator(value AccountGenerator) AccountRoutingBuilder {
+	ab.accountGenerator = value
+	return ab
+}
+
+func (ab *accountRoutingBuilder) AccountProvisioner(value AccountProvisioner) AccountRoutingBuilder {
+	ab.accountProvisioner = value
+	return ab
+}
+
+func (ab *accountRoutingBuilder) MatchType(value MatchType) AccountRoutingBuilder {
+	ab.matchType = value
+	return ab
+}
+
+// Match is the account number, or the start of the account numbers, routed
+func (ab *accountRoutingBuilder) Match(value string) AccountRoutingBuilder {
+	ab.match = value
+	return ab
+}
+
+// Priority orders the routings matching the same account number, the lowest applying first
+func (ab *accountRoutingBuilder) Priority(value int) AccountRoutingBuilder {
+	ab.priority = &value
+	return ab
+}
+
+func (ab *accountRoutingBuilder) Build() AccountRouting {
+	return AccountRouting{
+		AccountGenerator:   ab.accountGenerator,
+		AccountProvisioner: ab.accountProvisioner,
+		MatchType:          ab.matchType,
+		Match:              ab.match,
+		Priority:           ab.priority,
+	}
+}
+
+// NewAccountRouting is used to create an AccountRoutingBuilder
+func NewAccountRouting() AccountRoutingBuilder {
+	return &accountRoutingBuilder{}
+}
+
+type AccountRoutingData struct {
+	ID             string         `json:"id"`
+	OrganisationID string         `json:"organisation_id"`
+	Type           string         `json:"type"`
+	CreatedOn      *time.Time     `json:"created_on,omitempty"`
+	ModifiedOn     *time.Time     `json:"modified_on,omitempty"`
+	Version        *int           `json:"version,omitempty"`
+	Attributes     AccountRouting `json:"attributes"`
+
+	// Extra holds the fields returned by the API that this library does not know about
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// AccountRoutingDataBuilder returns a builder for AccountRoutingData struct
+type AccountRoutingDataBuilder interface {
+	ID(string) AccountRoutingDataBuilder
+	OrganisationID(string) AccountRoutingDataBuilder
+	Type(string) AccountRoutingDataBuilder
+	Version(int) AccountRoutingDataBuilder
+	Attributes(AccountRouting) AccountRoutingDataBuilder
+	Build() AccountRoutingData
+}
+
+type accountRoutingDataBuilder struct {
+	id                     string
+	organisationID         string
+	accountRoutingDataType string
+	version                *int
+	attributes             AccountRouting
+}
+
+func (ab *accountRoutingDataBuilder) ID(value string) AccountRoutingDataBuilder {
+	ab.id = value
+	return ab
+}
+
+func (ab *accountRoutingDataBuilder) OrganisationID(value string) AccountRoutingDataBuilder {
+	ab.organisationID = value
+	return ab
+}
+
+func (ab *accountRoutingDataBuilder) Type(value string) AccountRoutingDataBuilder {
+	ab.accountRoutingDataType = value
+	return ab
+}
+
+func (ab *accountRoutingDataBuilder) Version(value int) AccountRoutingDataBuilder {
+	ab.version = &value
+	return ab
+}
+
+func (ab *accountRoutingDataBuilder) Attributes(value AccountRouting) AccountRoutingDataBuilder {
+	ab.attributes = value
+	return ab
+}
+
+func (ab *accountRoutingDataBuilder) Build() AccountRoutingData {
+	return AccountRoutingData{
+		ID:             ab.id,
+		OrganisationID: ab.organisationID,
+		Type:           ab.accountRoutingDataType,
+		Version:        ab.version,
+		Attributes:     ab.attributes,
+	}
+}
+
+// NewAccountRoutingData is used to create an AccountRoutingDataBuilder
+func NewAccountRoutingData() AccountRoutingDataBuilder {
+	return &accountRoutingDataBuilder{}
+}
+
+// AccountRoutingSingle is the response payload when fetching an individual account routing
+type AccountRoutingSingle struct {
+	AccountRoutingData AccountRoutingData `json:"data"`
+	Links              Links              `json:"links"`
+
+	// Extra holds the members of the response that this library does not know about
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// AccountRoutingList is the response payload when requesting a list of account routings
+type AccountRoutingList struct {
+	AccountRoutingData *[]AccountRoutingData `json:"data,omitempty"`
+	Links              Links                 `json:"links"`
+
+	// Extra holds the members of the response that this library does not know about
+	Extra map[string]json.RawMessage `json:"-"`
+}
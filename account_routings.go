@@ -0,0 +1,90 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/google/uuid"
+)
+
+const accountRoutingsPath = "/v1/organisation/accountroutings"
+
+// AccountRoutingsClient is the client interface to access the account routings of the Form3 API
+//
+// The match type of a created account routing defaults to exact. The WithContext variants abort the request
+// to the API once the context is done
+type AccountRoutingsClient interface {
+	Create(request AccountRoutingData) (AccountRoutingSingle, error)
+	Fetch(id uuid.UUID) (AccountRoutingSingle, error)
+	List(page *Page, filter *Filter) (AccountRoutingList, error)
+	Delete(id uuid.UUID, version int) (bool, error)
+	CreateWithContext(ctx context.Context, request AccountRoutingData) (AccountRoutingSingle, error)
+	FetchWithContext(ctx context.Context, id uuid.UUID) (AccountRoutingSingle, error)
+	ListWithContext(ctx context.Context, page *Page, filter *Filter) (AccountRoutingList, error)
+	DeleteWithContext(ctx context.Context, id uuid.UUID, version int) (bool, error)
+}
+
+type accountRoutingsClient struct {
+	resourceClient[AccountRoutingData, AccountRoutingSingle, AccountRoutingList]
+}
+
+// AccountRoutingsClientBuilder is used to create an AccountRoutingsClient
+type AccountRoutingsClientBuilder interface {
+	URL(string) AccountRoutingsClientBuilder
+	HTTPClient(http.Client) AccountRoutingsClientBuilder
+	StrictDecoding(bool) AccountRoutingsClientBuilder
+	MaxBodySize(int64) AccountRoutingsClientBuilder
+	Retry(RetryPolicy) AccountRoutingsClientBuilder
+	Middleware(...Middleware) AccountRoutingsClientBuilder
+	Build() AccountRoutingsClient
+}
+
+type accountRoutingsClientBuilder struct {
+	transportOptions[AccountRoutingsClientBuilder]
+}
+
+func (ab *accountRoutingsClientBuilder) Build() AccountRoutingsClient {
+	accountRoutings := newResource[AccountRoutingSingle, AccountRoutingList](ab.transport(), accountRoutingsPath, "account routing", "account routings")
+	return &accountRoutingsClient{
+		resourceClient: newResourceClient(accountRoutings, accountRoutingsType, func(data *AccountRoutingData, partial bool) error {
+			if data.Attributes.MatchType == "" {
+				data.Attributes.MatchType = MatchExact
+			}
+			return validateAccountRouting(data.Attributes)
+		}),
+	}
+}
+
+// NewAccountRoutingsClient is used to create an AccountRoutingsClientBuilder
+func NewAccountRoutingsClient() AccountRoutingsClientBuilder {
+	ab := &accountRoutingsClientBuilder{}
+	ab.self = ab
+	return ab
+}
+
+func validateAccountRouting(routing AccountRouting) error {
+	if routing.AccountGenerator != AccountGeneratorForm3 && routing.AccountGenerator != AccountGeneratorOrganisation {
+		return &ValidationError{Field: "AccountGenerator", Message: fmt.Sprintf("Invalid AccountGenerator [%s]", routing.AccountGenerator)}
+	}
+
+	if routing.AccountProvisioner != AccountProvisionerForm3 && routing.AccountProvisioner != AccountProvisionerOrganisation {
+		return &ValidationError{Field: "AccountProvisioner", Message: fmt.Sprintf("Invalid AccountProvisioner [%s]", routing.AccountProvisioner)}
+	}
+
+	if routing.MatchType != MatchExact && routing.MatchType != MatchPrefix {
+		return &ValidationError{Field: "MatchType", Message: fmt.Sprintf("Invalid MatchType [%s]", routing.MatchType)}
+	}
+
+	var validMatch = regexp.MustCompile(`^[A-Z0-9]{1,34}$`)
+	if validMatch.MatchString(routing.Match) == false {
+		return &ValidationError{Field: "Match", Message: fmt.Sprintf("Invalid Match [%s]", routing.Match)}
+	}
+
+	if routing.Priority != nil && *routing.Priority < 0 {
+		return &ValidationError{Field: "Priority", Message: fmt.Sprintf("Invalid Priority [%d]", *routing.Priority)}
+	}
+
+	return nil
+}
@@ -0,0 +1,303 @@
+// Package accountsmock provides a programmable accounts.Client for the tests of services using the library,
+// answering from expectations or from an in-memory store without any HTTP
+package accountsmock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+
+	accounts "github.com/razvanmuscalu/form3-accounts-client"
+)
+
+// Operation names a method of the client, whatever its context variant
+type Operation string
+
+// the operations of the client
+const (
+	Create Operation = "Create"
+	Fetch  Operation = "Fetch"
+	List   Operation = "List"
+	Update Operation = "Update"
+	Delete Operation = "Delete"
+)
+
+// ErrUnexpectedCall is returned for a call no expectation matches when the client is not stateful
+var ErrUnexpectedCall = errors.New("Unexpected call")
+
+// ErrInvalidResult is returned for a call answered by Do with a value the operation cannot return
+var ErrInvalidResult = errors.New("Invalid result")
+
+// Call is a call made to the client, its arguments being given without the context
+type Call struct {
+	Operation Operation
+	Args      []interface{}
+	Result    interface{}
+	Err       error
+}
+
+// Client is an accounts.ContextClient answering calls from expectations, then from its store when stateful
+type Client interface {
+	accounts.ContextClient
+
+	// OnCreate expects Create calls whose account matches
+	OnCreate(account Matcher) *Expectation
+	// OnFetch expects Fetch calls whose ID matches
+	OnFetch(id Matcher) *Expectation
+	// OnList expects List calls whose page and filter match
+	OnList(page Matcher, filter Matcher) *Expectation
+	// OnUpdate expects Update calls whose ID and account match
+	OnUpdate(id Matcher, account Matcher) *Expectation
+	// OnDelete expects Delete calls whose ID and version match
+	OnDelete(id Matcher, version Matcher) *Expectation
+
+	// Add puts accounts in the store of a stateful client
+	Add(data ...accounts.AccountData)
+	// Calls returns the calls made so far, of every operation when none is given
+	Calls(operations ...Operation) []Call
+	// Verify fails when an expectation limited with Times was not called as many times
+	Verify() error
+	// Reset forgets the expectations, the calls and the store
+	Reset()
+}
+
+type client struct {
+	mutex        sync.Mutex
+	stateful     bool
+	expectations []*Expectation
+	calls        []Call
+	store        *store
+}
+
+// check that the mock implements the whole client interface
+var _ accounts.ContextClient = (*client)(nil)
+
+// ClientBuilder is used to create a Client
+type ClientBuilder interface {
+	Stateful(bool) ClientBuilder
+	Build() Client
+}
+
+type clientBuilder struct {
+	stateful bool
+}
+
+// Stateful answers the calls no expectation matches from an in-memory store behaving like the API,
+// instead of failing them with ErrUnexpectedCall
+func (cb *clientBuilder) Stateful(value bool) ClientBuilder {
+	cb.stateful = value
+	return cb
+}
+
+func (cb *clientBuilder) Build() Client {
+	return &client{stateful: cb.stateful, store: newStore()}
+}
+
+// NewClient is used to create a ClientBuilder
+func NewClient() ClientBuilder {
+	return &clientBuilder{}
+}
+
+func (c *client) expect(operation Operation, matchers ...Matcher) *Expectation {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	expectation := &Expectation{operation: operation, matchers: matchers}
+	c.expectations = append(c.expectations, expectation)
+	return expectation
+}
+
+func (c *client) OnCreate(account Matcher) *Expectation {
+	return c.expect(Create, account)
+}
+
+func (c *client) OnFetch(id Matcher) *Expectation {
+	return c.expect(Fetch, id)
+}
+
+func (c *client) OnList(page Matcher, filter Matcher) *Expectation {
+	return c.expect(List, page, filter)
+}
+
+func (c *client) OnUpdate(id Matcher, account Matcher) *Expectation {
+	return c.expect(Update, id, account)
+}
+
+func (c *client) OnDelete(id Matcher, version Matcher) *Expectation {
+	return c.expect(Delete, id, version)
+}
+
+func (c *client) Add(data ...accounts.AccountData) {
+	for _, ad := range data {
+		c.store.add(ad)
+	}
+}
+
+func (c *client) Calls(operations ...Operation) []Call {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var calls []Call
+	for _, call := range c.calls {
+		if len(operations) == 0 || contains(operations, call.Operation) {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+func contains(operations []Operation, operation Operation) bool {
+	for _, o := range operations {
+		if o == operation {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *client) Verify() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var unmet []string
+	for _, expectation := range c.expectations {
+		if expectation.times > 0 && expectation.calls != expectation.times {
+			unmet = append(unmet, fmt.Sprintf("%s expected %d times, called %d times", expectation.operation, expectation.times, expectation.calls))
+		}
+	}
+	if len(unmet) > 0 {
+		return fmt.Errorf("Unmet expectations [%s]", strings.Join(unmet, ", "))
+	}
+	return nil
+}
+
+func (c *client) Reset() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.expectations = nil
+	c.calls = nil
+	c.store.reset()
+}
+
+// call answers a call from the first matching expectation that is not used up, falling back to the store,
+// and records it
+func (c *client) call(ctx context.Context, operation Operation, message string, args []interface{}, fromStore func() (interface{}, error)) (interface{}, error) {
+	if ctx.Err() != nil {
+		err := &accounts.RequestError{Message: message, Err: ctx.Err()}
+		c.record(Call{Operation: operation, Args: args, Err: err})
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	var matched *Expectation
+	for _, expectation := range c.expectations {
+		if expectation.operation == operation && !expectation.usedUp() && expectation.matches(args) {
+			expectation.calls++
+			matched = expectation
+			break
+		}
+	}
+	c.mutex.Unlock()
+
+	var result interface{}
+	var err error
+	switch {
+	case matched != nil:
+		result, err = matched.answer(args)
+	case c.stateful:
+		result, err = fromStore()
+	default:
+		err = fmt.Errorf("%w [%s%s]", ErrUnexpectedCall, operation, formatArgs(args))
+	}
+
+	c.record(Call{Operation: operation, Args: args, Result: result, Err: err})
+	return result, err
+}
+
+// resultOf returns the result of a call as the type its operation returns
+func resultOf[T any](operation Operation, result interface{}, err error) (T, error) {
+	var value T
+	if err != nil {
+		return value, err
+	}
+	value, ok := result.(T)
+	if !ok {
+		return value, fmt.Errorf("%w [%T] for [%s]", ErrInvalidResult, result, operation)
+	}
+	return value, nil
+}
+
+func (c *client) record(call Call) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.calls = append(c.calls, call)
+}
+
+func formatArgs(args []interface{}) string {
+	var formatted []string
+	for _, arg := range args {
+		formatted = append(formatted, fmt.Sprintf("%+v", arg))
+	}
+	return "(" + strings.Join(formatted, ", ") + ")"
+}
+
+func (c *client) Create(request accounts.AccountData) (accounts.Single, error) {
+	return c.CreateWithContext(context.Background(), request)
+}
+
+func (c *client) CreateWithContext(ctx context.Context, request accounts.AccountData) (accounts.Single, error) {
+	result, err := c.call(ctx, Create, "An error has occured while creating account", []interface{}{request}, func() (interface{}, error) {
+		return c.store.create(request)
+	})
+	return resultOf[accounts.Single](Create, result, err)
+}
+
+func (c *client) Fetch(id uuid.UUID) (accounts.Single, error) {
+	return c.FetchWithContext(context.Background(), id)
+}
+
+func (c *client) FetchWithContext(ctx context.Context, id uuid.UUID) (accounts.Single, error) {
+	result, err := c.call(ctx, Fetch, "An error has occured while fetching account", []interface{}{id}, func() (interface{}, error) {
+		return c.store.fetch(id)
+	})
+	return resultOf[accounts.Single](Fetch, result, err)
+}
+
+func (c *client) List(page *accounts.Page, filter *accounts.Filter) (accounts.List, error) {
+	return c.ListWithContext(context.Background(), page, filter)
+}
+
+func (c *client) ListWithContext(ctx context.Context, page *accounts.Page, filter *accounts.Filter) (accounts.List, error) {
+	result, err := c.call(ctx, List, "An error has occured while listing accounts", []interface{}{page, filter}, func() (interface{}, error) {
+		return c.store.list(page, filter), nil
+	})
+	return resultOf[accounts.List](List, result, err)
+}
+
+func (c *client) Update(id uuid.UUID, request accounts.AccountData) (accounts.Single, error) {
+	return c.UpdateWithContext(context.Background(), id, request)
+}
+
+func (c *client) UpdateWithContext(ctx context.Context, id uuid.UUID, request accounts.AccountData) (accounts.Single, error) {
+	result, err := c.call(ctx, Update, "An error has occured while updating account", []interface{}{id, request}, func() (interface{}, error) {
+		return c.store.update(id, request)
+	})
+	return resultOf[accounts.Single](Update, result, err)
+}
+
+func (c *client) Delete(id uuid.UUID, version int) (bool, error) {
+	return c.DeleteWithContext(context.Background(), id, version)
+}
+
+func (c *client) DeleteWithContext(ctx context.Context, id uuid.UUID, version int) (bool, error) {
+	result, err := c.call(ctx, Delete, "An error has occured while deleting account", []interface{}{id, version}, func() (interface{}, error) {
+		return c.store.delete(id, version)
+	})
+	return resultOf[bool](Delete, result, err)
+}
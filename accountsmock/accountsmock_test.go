@@ -0,0 +1,248 @@
+package accountsmock
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+
+	accounts "github.com/razvanmuscalu/form3-accounts-client"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func newAccount(organisationID string) accounts.AccountData {
+	return accounts.NewAccountData().
+		ID(uuid.New().String()).
+		OrganisationID(organisationID).
+		Type("accounts").
+		Attributes(accounts.NewAccount().Country("GB").Build()).
+		Build()
+}
+
+func TestExpectations(t *testing.T) {
+
+	Convey("Given a mock client", t, func() {
+		mock := NewClient().Build()
+		id := uuid.New()
+
+		Convey("When a fetch is expected with a canned response", func() {
+			mock.OnFetch(ID(id.String())).Return(accounts.Single{AccountData: accounts.NewAccountData().ID(id.String()).Build()}).Once()
+			resp, err := mock.Fetch(id)
+
+			Convey("Then the call gets it and is recorded", func() {
+				So(err, ShouldBeNil)
+				So(resp.AccountData.ID, ShouldEqual, id.String())
+				So(mock.Verify(), ShouldBeNil)
+
+				calls := mock.Calls(Fetch)
+				So(len(calls), ShouldEqual, 1)
+				So(calls[0].Args, ShouldResemble, []interface{}{id})
+			})
+
+			Convey("And a second call is unexpected", func() {
+				_, err := mock.Fetch(id)
+				So(errors.Is(err, ErrUnexpectedCall), ShouldBeTrue)
+			})
+		})
+
+		Convey("When a delete is expected to fail", func() {
+			conflict := &accounts.APIError{StatusCode: http.StatusConflict, Message: "invalid version"}
+			mock.OnDelete(Any(), Eq(1)).ReturnError(conflict)
+			mock.OnDelete(Any(), Any()).Return(true)
+
+			_, conflicted := mock.Delete(id, 1)
+			deleted, err := mock.Delete(id, 2)
+
+			Convey("Then only the matching calls fail", func() {
+				So(conflicted, ShouldEqual, conflict)
+				So(err, ShouldBeNil)
+				So(deleted, ShouldBeTrue)
+			})
+		})
+
+		Convey("When a list is answered from the arguments of the call", func() {
+			organisationID := uuid.New().String()
+			mock.OnList(Any(), OrganisationID(organisationID)).Do(func(args []interface{}) (interface{}, error) {
+				data := []accounts.AccountData{newAccount(*args[1].(*accounts.Filter).OrganisationID)}
+				return accounts.List{AccountData: &data}, nil
+			})
+
+			resp, err := mock.List(&accounts.Page{Number: 0, Size: 10}, &accounts.Filter{OrganisationID: &organisationID})
+			_, other := mock.List(nil, nil)
+
+			Convey("Then the call gets what was answered", func() {
+				So(err, ShouldBeNil)
+				So((*resp.AccountData)[0].OrganisationID, ShouldEqual, organisationID)
+				So(errors.Is(other, ErrUnexpectedCall), ShouldBeTrue)
+			})
+		})
+
+		Convey("When an expectation is not called as many times as expected", func() {
+			mock.OnCreate(Any()).Times(2)
+			mock.Create(newAccount(uuid.New().String()))
+
+			Convey("Then it is not verified", func() {
+				So(mock.Verify(), ShouldBeError, "Unmet expectations [Create expected 2 times, called 1 times]")
+			})
+		})
+
+		Convey("When a call is made with a context that is done", func() {
+			mock.OnFetch(Any())
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			_, err := mock.FetchWithContext(ctx, id)
+
+			Convey("Then it fails as the client would", func() {
+				So(err, ShouldBeError, "An error has occured while fetching account")
+				So(errors.Is(err, context.Canceled), ShouldBeTrue)
+			})
+		})
+
+		Convey("When a canned response of the wrong type is given", func() {
+
+			Convey("Then it panics", func() {
+				So(func() { mock.OnDelete(Any(), Any()).Return(accounts.Single{}) }, ShouldPanic)
+			})
+		})
+
+		Convey("When a call is answered with a value of the wrong type", func() {
+			mock.OnDelete(Any(), Any()).Do(func(args []interface{}) (interface{}, error) {
+				return accounts.Single{}, nil
+			})
+			_, err := mock.Delete(id, 0)
+
+			Convey("Then it fails instead of panicking", func() {
+				So(errors.Is(err, ErrInvalidResult), ShouldBeTrue)
+				So(err, ShouldBeError, "Invalid result [accounts.Single] for [Delete]")
+			})
+		})
+	})
+}
+
+func TestStatefulMock(t *testing.T) {
+
+	Convey("Given a stateful mock client", t, func() {
+		mock := NewClient().Stateful(true).Build()
+		organisationID := uuid.New().String()
+		data := newAccount(organisationID)
+
+		Convey("When I create an account", func() {
+			created, err := mock.Create(data)
+
+			Convey("Then it can be fetched", func() {
+				So(err, ShouldBeNil)
+				So(*created.AccountData.Version, ShouldEqual, 0)
+
+				fetched, err := mock.Fetch(uuid.MustParse(data.ID))
+				So(err, ShouldBeNil)
+				So(fetched.AccountData.ID, ShouldEqual, data.ID)
+			})
+
+			Convey("And it cannot be created again", func() {
+				_, err := mock.Create(data)
+				So(err, ShouldBeError, "Account cannot be created as it violates a duplicate constraint")
+			})
+
+			Convey("And it cannot be updated with invalid attributes", func() {
+				patch := accounts.NewAccountData().Version(0).Attributes(accounts.NewAccount().BIC("invalid").Build()).Build()
+				_, err := mock.Update(uuid.MustParse(data.ID), patch)
+				var validationErr *accounts.ValidationError
+				So(errors.As(err, &validationErr), ShouldBeTrue)
+				So(validationErr.Field, ShouldEqual, "BIC")
+			})
+
+			Convey("And it cannot be deleted with another version", func() {
+				_, err := mock.Delete(uuid.MustParse(data.ID), 3)
+				var apiErr *accounts.APIError
+				So(errors.As(err, &apiErr), ShouldBeTrue)
+				So(apiErr.StatusCode, ShouldEqual, http.StatusConflict)
+			})
+
+			Convey("And it can be updated with its version", func() {
+				patch := accounts.NewAccountData().Version(0).Attributes(accounts.NewAccount().BankAccountName("Sam Holder").Build()).Build()
+				updated, err := mock.Update(uuid.MustParse(data.ID), patch)
+				So(err, ShouldBeNil)
+				So(*updated.AccountData.Version, ShouldEqual, 1)
+				So(*updated.AccountData.Attributes.BankAccountName, ShouldEqual, "Sam Holder")
+
+				_, err = mock.Update(uuid.MustParse(data.ID), patch)
+				So(err, ShouldBeError, "invalid version")
+			})
+
+			Convey("And it is gone once deleted", func() {
+				deleted, err := mock.Delete(uuid.MustParse(data.ID), 0)
+				So(err, ShouldBeNil)
+				So(deleted, ShouldBeTrue)
+
+				_, err = mock.Fetch(uuid.MustParse(data.ID))
+				So(err, ShouldBeError, "record "+data.ID+" does not exist")
+			})
+		})
+
+		Convey("When I create an account the client would not send", func() {
+			invalid := newAccount(organisationID)
+			invalid.Attributes.Country = "Great Britain"
+			_, err := mock.Create(invalid)
+
+			Convey("Then it fails validation as with the client", func() {
+				So(err, ShouldBeError, "Invalid Country [Great Britain]")
+				_, fetchErr := mock.Fetch(uuid.MustParse(invalid.ID))
+				So(fetchErr, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When I list the accounts of an organisation without any", func() {
+			resp, err := mock.List(nil, &accounts.Filter{OrganisationID: &organisationID})
+
+			Convey("Then the list holds no data, as with the API", func() {
+				So(err, ShouldBeNil)
+				So(resp.AccountData, ShouldBeNil)
+			})
+		})
+
+		Convey("When an expectation matches a call", func() {
+			mock.Add(data)
+			mock.OnFetch(Any()).ReturnError(&accounts.APIError{StatusCode: http.StatusServiceUnavailable}).Once()
+
+			_, first := mock.Fetch(uuid.MustParse(data.ID))
+			_, second := mock.Fetch(uuid.MustParse(data.ID))
+
+			Convey("Then it takes precedence over the store", func() {
+				So(first, ShouldNotBeNil)
+				So(second, ShouldBeNil)
+			})
+		})
+
+		Convey("When the accounts of an organisation are purged through the mock", func() {
+			for i := 0; i < 5; i++ {
+				mock.Add(newAccount(organisationID))
+			}
+			mock.Add(newAccount(uuid.New().String()))
+
+			summary, err := accounts.NewPurger().Client(mock).PageSize(2).Build().PurgeOrganisation(organisationID)
+
+			Convey("Then only its accounts are deleted", func() {
+				So(err, ShouldBeNil)
+				So(len(summary.Deleted), ShouldEqual, 5)
+				So(len(mock.Calls(Delete)), ShouldEqual, 5)
+
+				remaining, _ := mock.List(nil, nil)
+				So(len(*remaining.AccountData), ShouldEqual, 1)
+			})
+		})
+
+		Convey("When the mock is reset", func() {
+			mock.Add(data)
+			mock.Reset()
+			_, err := mock.Fetch(uuid.MustParse(data.ID))
+
+			Convey("Then the store is empty and the calls are forgotten", func() {
+				So(err, ShouldNotBeNil)
+				So(len(mock.Calls()), ShouldEqual, 1)
+			})
+		})
+	})
+}
@@ -0,0 +1,149 @@
+package accountsmock
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/google/uuid"
+
+	accounts "github.com/razvanmuscalu/form3-accounts-client"
+)
+
+// Matcher tells whether an argument of a call is the expected one
+type Matcher func(arg interface{}) bool
+
+// Any matches every argument
+func Any() Matcher {
+	return func(interface{}) bool {
+		return true
+	}
+}
+
+// Eq matches arguments deeply equal to value, pointers being compared by the values they point to
+func Eq(value interface{}) Matcher {
+	return func(arg interface{}) bool {
+		return reflect.DeepEqual(arg, value)
+	}
+}
+
+// ID matches account data, IDs and strings holding the given account ID
+func ID(id string) Matcher {
+	return func(arg interface{}) bool {
+		switch value := arg.(type) {
+		case accounts.AccountData:
+			return value.ID == id
+		case uuid.UUID:
+			return value.String() == id
+		case string:
+			return value == id
+		}
+		return false
+	}
+}
+
+// OrganisationID matches account data and filters of the given organisation
+func OrganisationID(id string) Matcher {
+	return func(arg interface{}) bool {
+		switch value := arg.(type) {
+		case accounts.AccountData:
+			return value.OrganisationID == id
+		case *accounts.Filter:
+			return value != nil && value.OrganisationID != nil && *value.OrganisationID == id
+		}
+		return false
+	}
+}
+
+// Func matches the arguments for which match returns true
+func Func(match func(arg interface{}) bool) Matcher {
+	return match
+}
+
+// Expectation is an expected call along with how to answer it
+type Expectation struct {
+	operation Operation
+	matchers  []Matcher
+	result    interface{}
+	err       error
+	answerer  func(args []interface{}) (interface{}, error)
+	times     int
+	calls     int
+}
+
+// Return answers matching calls with value, which must be what the operation returns: a Single for
+// Create, Fetch and Update, a List for List and a bool for Delete
+func (e *Expectation) Return(value interface{}) *Expectation {
+	var ok bool
+	switch e.operation {
+	case Create, Fetch, Update:
+		_, ok = value.(accounts.Single)
+	case List:
+		_, ok = value.(accounts.List)
+	case Delete:
+		_, ok = value.(bool)
+	}
+	if !ok {
+		panic(fmt.Sprintf("%s cannot return %T", e.operation, value))
+	}
+
+	e.result = value
+	return e
+}
+
+// ReturnError answers matching calls with err
+func (e *Expectation) ReturnError(err error) *Expectation {
+	e.err = err
+	return e
+}
+
+// Do answers matching calls with what answer returns given the arguments of the call, without the context
+func (e *Expectation) Do(answer func(args []interface{}) (interface{}, error)) *Expectation {
+	e.answerer = answer
+	return e
+}
+
+// Times limits the expectation to n calls, Verify failing when it is not called as many times
+func (e *Expectation) Times(n int) *Expectation {
+	e.times = n
+	return e
+}
+
+// Once limits the expectation to a single call
+func (e *Expectation) Once() *Expectation {
+	return e.Times(1)
+}
+
+func (e *Expectation) usedUp() bool {
+	return e.times > 0 && e.calls >= e.times
+}
+
+func (e *Expectation) matches(args []interface{}) bool {
+	for i, matcher := range e.matchers {
+		if matcher != nil && !matcher(args[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// answer returns the canned response, the zero value of the operation when none was given
+func (e *Expectation) answer(args []interface{}) (interface{}, error) {
+	result, err := e.result, e.err
+	if e.answerer != nil {
+		result, err = e.answerer(args)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if result != nil {
+		return result, nil
+	}
+
+	switch e.operation {
+	case List:
+		return accounts.List{}, nil
+	case Delete:
+		return true, nil
+	}
+	return accounts.Single{}, nil
+}
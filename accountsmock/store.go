@@ -0,0 +1,175 @@
+package accountsmock
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	accounts "github.com/razvanmuscalu/form3-accounts-client"
+	"github.com/razvanmuscalu/form3-accounts-client/internal/paging"
+	"github.com/razvanmuscalu/form3-accounts-client/internal/patch"
+	"github.com/razvanmuscalu/form3-accounts-client/internal/validation"
+)
+
+const accountsPath = "/v1/organisation/accounts"
+
+// store keeps the accounts of a stateful client, answering the way the API does
+type store struct {
+	mutex    sync.Mutex
+	accounts map[string]accounts.AccountData
+	order    []string
+}
+
+func newStore() *store {
+	return &store{accounts: make(map[string]accounts.AccountData)}
+}
+
+func (s *store) reset() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.accounts = make(map[string]accounts.AccountData)
+	s.order = nil
+}
+
+func (s *store) add(data accounts.AccountData) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if data.Version == nil {
+		version := 0
+		data.Version = &version
+	}
+	if _, ok := s.accounts[data.ID]; !ok {
+		s.order = append(s.order, data.ID)
+	}
+	s.accounts[data.ID] = data
+}
+
+func (s *store) create(data accounts.AccountData) (accounts.Single, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := validation.Validate(data.Attributes, false); err != nil {
+		return accounts.Single{}, err
+	}
+	if _, err := uuid.Parse(data.ID); err != nil {
+		return accounts.Single{}, &accounts.APIError{StatusCode: http.StatusBadRequest, Message: fmt.Sprintf("validation failure list:\nid in body must be of type uuid: %q", data.ID)}
+	}
+	if _, ok := s.accounts[data.ID]; ok {
+		return accounts.Single{}, &accounts.APIError{StatusCode: http.StatusConflict, Message: "Account cannot be created as it violates a duplicate constraint"}
+	}
+
+	now := time.Now().UTC()
+	version := 0
+	data.CreatedOn = &now
+	data.ModifiedOn = &now
+	data.Version = &version
+	s.accounts[data.ID] = data
+	s.order = append(s.order, data.ID)
+
+	return accounts.Single{AccountData: data, Links: accounts.Links{Self: accountsPath + "/" + data.ID}}, nil
+}
+
+func (s *store) fetch(id uuid.UUID) (accounts.Single, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	data, ok := s.accounts[id.String()]
+	if !ok {
+		return accounts.Single{}, &accounts.APIError{StatusCode: http.StatusNotFound, Message: fmt.Sprintf("record %s does not exist", id)}
+	}
+
+	return accounts.Single{AccountData: data, Links: accounts.Links{Self: accountsPath + "/" + data.ID}}, nil
+}
+
+// list pages through the accounts in the order they were added, 100 per page when no page is given
+func (s *store) list(page *accounts.Page, filter *accounts.Filter) accounts.List {
+	number, size := 0, paging.DefaultSize
+	if page != nil {
+		number, size = page.Number, page.Size
+	}
+	organisationID := ""
+	if filter != nil && filter.OrganisationID != nil {
+		organisationID = *filter.OrganisationID
+	}
+
+	s.mutex.Lock()
+	var matching []accounts.AccountData
+	for _, id := range s.order {
+		if data := s.accounts[id]; organisationID == "" || data.OrganisationID == organisationID {
+			matching = append(matching, data)
+		}
+	}
+	s.mutex.Unlock()
+
+	start, end, links := paging.Paginate(accountsPath, number, size, len(matching), paging.OrganisationFilter(organisationID))
+	list := accounts.List{Links: links}
+	if start < end {
+		data := append([]accounts.AccountData{}, matching[start:end]...)
+		list.AccountData = &data
+	}
+
+	return list
+}
+
+// update applies the attributes of the request to the account when its version is current, bumping the version
+func (s *store) update(id uuid.UUID, request accounts.AccountData) (accounts.Single, error) {
+	if err := validation.Validate(request.Attributes, true); err != nil {
+		return accounts.Single{}, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	data, ok := s.accounts[id.String()]
+	if !ok {
+		return accounts.Single{}, &accounts.APIError{StatusCode: http.StatusNotFound, Message: fmt.Sprintf("record %s does not exist", id)}
+	}
+	if request.Version == nil || data.Version == nil || *data.Version != *request.Version {
+		return accounts.Single{}, &accounts.APIError{StatusCode: http.StatusConflict, Message: "invalid version"}
+	}
+
+	attributesPatch, err := patch.Attributes(request.Attributes, "country")
+	if err != nil {
+		return accounts.Single{}, &accounts.APIError{StatusCode: http.StatusBadRequest, Message: err.Error()}
+	}
+	attributes, err := patch.Merge(data.Attributes, attributesPatch)
+	if err != nil {
+		return accounts.Single{}, &accounts.APIError{StatusCode: http.StatusBadRequest, Message: err.Error()}
+	}
+	now := time.Now().UTC()
+	version := *data.Version + 1
+	data.Attributes = attributes
+	data.ModifiedOn = &now
+	data.Version = &version
+	s.accounts[data.ID] = data
+
+	return accounts.Single{AccountData: data, Links: accounts.Links{Self: accountsPath + "/" + data.ID}}, nil
+}
+
+func (s *store) delete(id uuid.UUID, version int) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	data, ok := s.accounts[id.String()]
+	if !ok {
+		return false, &accounts.APIError{StatusCode: http.StatusNotFound, Message: fmt.Sprintf("record %s does not exist", id)}
+	}
+	if data.Version == nil || *data.Version != version {
+		return false, &accounts.APIError{StatusCode: http.StatusConflict, Message: "invalid version"}
+	}
+
+	delete(s.accounts, id.String())
+	for i, stored := range s.order {
+		if stored == id.String() {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,176 @@
+package accounts
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// AuditEntry is the change of a record made by an action, the data of the record before and after it
+// being kept as returned, whatever the type of the record
+type AuditEntry struct {
+	ActionTime  *time.Time      `json:"action_time,omitempty"`
+	ActionedBy  string          `json:"actioned_by,omitempty"`
+	Description string          `json:"description,omitempty"`
+	RecordType  string          `json:"record_type"`
+	RecordID    string          `json:"record_id"`
+	BeforeData  json.RawMessage `json:"before_data,omitempty"`
+	AfterData   json.RawMessage `json:"after_data,omitempty"`
+
+	// Extra holds the attributes returned by the API that this library does not know about
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type AuditEntryData struct {
+	ID             string     `json:"id"`
+	OrganisationID string     `json:"organisation_id"`
+	Type           string     `json:"type"`
+	Version        *int       `json:"version,omitempty"`
+	Attributes     AuditEntry `json:"attributes"`
+
+	// Extra holds the fields returned by the API that this library does not know about
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// AuditList is the response payload when requesting a list of audit entries
+type AuditList struct {
+	AuditEntryData *[]AuditEntryData `json:"data,omitempty"`
+	Links          Links             `json:"links"`
+
+	// Extra holds the members of the response that this library does not know about
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// AttributeChange is an attribute of an account changed by an action, its values being given as JSON,
+// empty when the attribute was not set
+type AttributeChange struct {
+	Field  string
+	Before string
+	After  string
+}
+
+// AccountVersion is the state of an account after an action, along with what the action changed
+//
+// Attributes is nil once the account is deleted
+type AccountVersion struct {
+	Version     *int
+	ActionTime  *time.Time
+	ActionedBy  string
+	Description string
+	Attributes  *Account
+	Changes     []AttributeChange
+}
+
+// auditRecord decodes the data of an audit entry, given either as the account itself or wrapped in a data member
+func auditRecord(raw json.RawMessage) (*AccountData, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	var wrapped struct {
+		Data *AccountData `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &wrapped); err == nil && wrapped.Data != nil {
+		return wrapped.Data, nil
+	}
+
+	var data AccountData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// attributeFields returns the attributes of an account as JSON by field name
+func attributeFields(attributes *Account) (map[string]json.RawMessage, error) {
+	fields := make(map[string]json.RawMessage)
+	if attributes == nil {
+		return fields, nil
+	}
+
+	encoded, err := json.Marshal(attributes)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// diffAttributes returns the attributes that differ between before and after, sorted by field
+func diffAttributes(before *Account, after *Account) ([]AttributeChange, error) {
+	beforeFields, err := attributeFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := attributeFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	for name := range beforeFields {
+		names[name] = true
+	}
+	for name := range afterFields {
+		names[name] = true
+	}
+
+	var changes []AttributeChange
+	for name := range names {
+		if string(beforeFields[name]) != string(afterFields[name]) {
+			changes = append(changes, AttributeChange{Field: name, Before: string(beforeFields[name]), After: string(afterFields[name])})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+
+	return changes, nil
+}
+
+// history orders the audit entries of an account by action time and replays them into its versions
+//
+// Entries without an action time are replayed last, in the order they were listed
+func history(entries []AuditEntryData) ([]AccountVersion, error) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		left, right := entries[i].Attributes.ActionTime, entries[j].Attributes.ActionTime
+		if left == nil || right == nil {
+			return left != nil && right == nil
+		}
+		return left.Before(*right)
+	})
+
+	var versions []AccountVersion
+	var previous *Account
+	for i, entry := range entries {
+		before, err := auditRecord(entry.Attributes.BeforeData)
+		if err != nil {
+			return nil, err
+		}
+		after, err := auditRecord(entry.Attributes.AfterData)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 && before != nil {
+			previous = &before.Attributes
+		}
+
+		version := AccountVersion{
+			ActionTime:  entry.Attributes.ActionTime,
+			ActionedBy:  entry.Attributes.ActionedBy,
+			Description: entry.Attributes.Description,
+		}
+		if after != nil {
+			version.Version = after.Version
+			version.Attributes = &after.Attributes
+		}
+		if version.Changes, err = diffAttributes(previous, version.Attributes); err != nil {
+			return nil, err
+		}
+
+		versions = append(versions, version)
+		previous = version.Attributes
+	}
+
+	return versions, nil
+}
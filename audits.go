@@ -0,0 +1,120 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+)
+
+const auditsPath = "/v1/audit/entries"
+
+// AuditsClient is the client interface to access the audit entries of the Form3 API
+//
+// The WithContext variants abort the request to the API once the context is done
+type AuditsClient interface {
+	List(recordType string, recordID string, page *Page) (AuditList, error)
+	History(accountID uuid.UUID) ([]AccountVersion, error)
+	ListWithContext(ctx context.Context, recordType string, recordID string, page *Page) (AuditList, error)
+	HistoryWithContext(ctx context.Context, accountID uuid.UUID) ([]AccountVersion, error)
+}
+
+type auditsClient struct {
+	transport *transport
+	pageSize  int
+}
+
+// AuditsClientBuilder is used to create an AuditsClient
+type AuditsClientBuilder interface {
+	URL(string) AuditsClientBuilder
+	HTTPClient(http.Client) AuditsClientBuilder
+	StrictDecoding(bool) AuditsClientBuilder
+	MaxBodySize(int64) AuditsClientBuilder
+	Retry(RetryPolicy) AuditsClientBuilder
+	Middleware(...Middleware) AuditsClientBuilder
+	PageSize(int) AuditsClientBuilder
+	Build() AuditsClient
+}
+
+type auditsClientBuilder struct {
+	transportOptions[AuditsClientBuilder]
+	pageSize int
+}
+
+// PageSize is the number of entries requested per page by History, 100 when not set
+func (ab *auditsClientBuilder) PageSize(value int) AuditsClientBuilder {
+	ab.pageSize = value
+	return ab
+}
+
+func (ab *auditsClientBuilder) Build() AuditsClient {
+	c := &auditsClient{transport: ab.transport(), pageSize: ab.pageSize}
+	if c.pageSize < 1 {
+		c.pageSize = 100
+	}
+
+	return c
+}
+
+// NewAuditsClient is used to create an AuditsClientBuilder
+func NewAuditsClient() AuditsClientBuilder {
+	ab := &auditsClientBuilder{}
+	ab.self = ab
+	return ab
+}
+
+// entries returns the resource of the audit entries of a record, which are only ever listed
+func (c auditsClient) entries(recordType string, recordID string) resource[AuditEntryData, AuditList] {
+	entriesPath := fmt.Sprintf("%s/%s/%s", auditsPath, url.PathEscape(recordType), url.PathEscape(recordID))
+	return newResource[AuditEntryData, AuditList](c.transport, entriesPath, "audit entry", "audit entries")
+}
+
+// List the audit entries of a record, e.g. of type accounts
+func (c auditsClient) List(recordType string, recordID string, page *Page) (AuditList, error) {
+	return c.ListWithContext(context.Background(), recordType, recordID, page)
+}
+
+// ListWithContext lists the audit entries of a record until the context is done
+func (c auditsClient) ListWithContext(ctx context.Context, recordType string, recordID string, page *Page) (AuditList, error) {
+
+	if recordType == "" {
+		return AuditList{}, &ValidationError{Field: "RecordType", Message: fmt.Sprintf("Invalid RecordType [%s]", recordType)}
+	}
+	if recordID == "" {
+		return AuditList{}, &ValidationError{Field: "RecordID", Message: fmt.Sprintf("Invalid RecordID [%s]", recordID)}
+	}
+
+	return c.entries(recordType, recordID).list(ctx, page, nil)
+}
+
+// History returns the versions of an account from oldest to newest, rebuilt from its audit entries
+func (c auditsClient) History(accountID uuid.UUID) ([]AccountVersion, error) {
+	return c.HistoryWithContext(context.Background(), accountID)
+}
+
+// HistoryWithContext returns the versions of an account until the context is done
+func (c auditsClient) HistoryWithContext(ctx context.Context, accountID uuid.UUID) ([]AccountVersion, error) {
+	var entries []AuditEntryData
+	_, err := eachListedPage(ctx, c.pageSize, func(ctx context.Context, page *Page) ([]AuditEntryData, *string, error) {
+		resp, err := c.ListWithContext(ctx, accountsType, accountID.String(), page)
+		if err != nil || resp.AuditEntryData == nil {
+			return nil, nil, err
+		}
+		return *resp.AuditEntryData, resp.Links.Next, nil
+	}, func(data []AuditEntryData) error {
+		entries = append(entries, data...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := history(entries)
+	if err != nil {
+		return nil, &ResponseError{Message: "An error has occured while decoding audit entries", Err: err}
+	}
+
+	return versions, nil
+}
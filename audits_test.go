@@ -0,0 +1,179 @@
+package accounts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// auditEntries returns the audit entries of the creation, update and deletion of an account, newest first
+func auditEntries(accountID uuid.UUID) []string {
+	created := fmt.Sprintf(`{"data":{"id":"%s","organisation_id":"%s","type":"accounts","version":0,"attributes":{"country":"GB","bank_id":"400300"}}}`, accountID, OrganisationID)
+	updated := fmt.Sprintf(`{"id":"%s","organisation_id":"%s","type":"accounts","version":1,"attributes":{"country":"GB","bank_id":"400302","bic":"NWBKGB22"}}`, accountID, OrganisationID)
+	entry := func(time string, actionedBy string, before string, after string) string {
+		return fmt.Sprintf(`{"id":"%s","organisation_id":"%s","type":"audit_entries","attributes":{"action_time":"%s","actioned_by":"%s","record_type":"accounts","record_id":"%s","before_data":%s,"after_data":%s}}`,
+			uuid.New(), OrganisationID, time, actionedBy, accountID, before, after)
+	}
+
+	return []string{
+		entry("2021-03-03T10:00:00Z", "carol", updated, "null"),
+		entry("2021-03-02T10:00:00Z", "bob", created, updated),
+		entry("2021-03-01T10:00:00Z", "alice", "null", created),
+	}
+}
+
+func TestAudits(t *testing.T) {
+
+	Convey("Given an API holding the audit entries of an account, by pages of two", t, func() {
+		accountID := uuid.New()
+		entries := auditEntries(accountID)
+		var targets []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			targets = append(targets, r.URL.Path+"?"+r.URL.RawQuery)
+			if r.URL.Path != auditsPath+"/accounts/"+accountID.String() {
+				fmt.Fprint(w, `{"data":[],"links":{"self":""}}`)
+				return
+			}
+
+			number, _ := strconv.Atoi(r.URL.Query().Get("page[number]"))
+			size, _ := strconv.Atoi(r.URL.Query().Get("page[size]"))
+			if size == 0 {
+				size = len(entries)
+			}
+			var page []string
+			next := ""
+			for i := number * size; i < len(entries) && i < (number+1)*size; i++ {
+				page = append(page, entries[i])
+			}
+			if (number+1)*size < len(entries) {
+				next = `,"next":"next"`
+			}
+			fmt.Fprintf(w, `{"data":[%s],"links":{"self":""%s}}`, strings.Join(page, ","), next)
+		}))
+		defer server.Close()
+
+		client := NewAuditsClient().HTTPClient(HTTPClient).URL(server.URL).PageSize(2).Build()
+
+		Convey("When I list the entries of the account", func() {
+			resp, err := client.List("accounts", accountID.String(), &Page{Number: 0, Size: 2})
+
+			Convey("Then the first page is returned, linking to the next one", func() {
+				So(err, ShouldBeNil)
+				So(targets[0], ShouldEqual, auditsPath+"/accounts/"+accountID.String()+"?page[number]=0&page[size]=2")
+				So(len(*resp.AuditEntryData), ShouldEqual, 2)
+				So((*resp.AuditEntryData)[0].Attributes.ActionedBy, ShouldEqual, "carol")
+				So(*resp.Links.Next, ShouldEqual, "next")
+			})
+		})
+
+		Convey("When I list the entries of a blank record", func() {
+			_, err := client.List("accounts", "", nil)
+
+			Convey("Then a validation error is returned without calling the API", func() {
+				var validationErr *ValidationError
+				So(errors.As(err, &validationErr), ShouldBeTrue)
+				So(validationErr.Field, ShouldEqual, "RecordID")
+				So(len(targets), ShouldEqual, 0)
+			})
+		})
+
+		Convey("When I request the history of the account", func() {
+			versions, err := client.History(accountID)
+
+			Convey("Then every page is read and the versions are returned from oldest to newest", func() {
+				So(err, ShouldBeNil)
+				So(len(targets), ShouldEqual, 2)
+				So(len(versions), ShouldEqual, 3)
+				So(versions[0].ActionedBy, ShouldEqual, "alice")
+				So(versions[1].ActionedBy, ShouldEqual, "bob")
+				So(versions[2].ActionedBy, ShouldEqual, "carol")
+			})
+
+			Convey("And the creation sets every attribute", func() {
+				So(*versions[0].Version, ShouldEqual, 0)
+				So(*versions[0].Attributes.BankID, ShouldEqual, "400300")
+				So(versions[0].Changes, ShouldResemble, []AttributeChange{
+					{Field: "bank_id", Before: "", After: `"400300"`},
+					{Field: "country", Before: "", After: `"GB"`},
+				})
+			})
+
+			Convey("And the update only holds the attributes it changed", func() {
+				So(*versions[1].Version, ShouldEqual, 1)
+				So(versions[1].Changes, ShouldResemble, []AttributeChange{
+					{Field: "bank_id", Before: `"400300"`, After: `"400302"`},
+					{Field: "bic", Before: "", After: `"NWBKGB22"`},
+				})
+			})
+
+			Convey("And the deletion leaves no attributes", func() {
+				So(versions[2].Attributes, ShouldBeNil)
+				So(len(versions[2].Changes), ShouldEqual, 3)
+				So(versions[2].Changes[0].After, ShouldEqual, "")
+			})
+		})
+
+		Convey("When I request the history of an account without entries", func() {
+			versions, err := client.History(uuid.New())
+
+			Convey("Then there are no versions", func() {
+				So(err, ShouldBeNil)
+				So(versions, ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+func TestHistoryStartingAfterCreation(t *testing.T) {
+
+	Convey("Given the entries of an account whose creation is no longer audited", t, func() {
+		entries := auditEntries(uuid.New())[:2]
+		var data []AuditEntryData
+		So(json.Unmarshal([]byte("["+strings.Join(entries, ",")+"]"), &data), ShouldBeNil)
+
+		Convey("When the history is rebuilt", func() {
+			versions, err := history(data)
+
+			Convey("Then the first change is diffed against the data before it", func() {
+				So(err, ShouldBeNil)
+				So(len(versions), ShouldEqual, 2)
+				So(versions[0].Changes, ShouldResemble, []AttributeChange{
+					{Field: "bank_id", Before: `"400300"`, After: `"400302"`},
+					{Field: "bic", Before: "", After: `"NWBKGB22"`},
+				})
+			})
+		})
+	})
+}
+
+func TestHistoryWithoutActionTime(t *testing.T) {
+
+	Convey("Given the entries of an account, the newest one without an action time", t, func() {
+		entries := auditEntries(uuid.New())
+		entries[0] = strings.Replace(entries[0], `"action_time":"2021-03-03T10:00:00Z",`, "", 1)
+		var data []AuditEntryData
+		So(json.Unmarshal([]byte("["+strings.Join(entries, ",")+"]"), &data), ShouldBeNil)
+
+		Convey("When the history is rebuilt", func() {
+			versions, err := history(data)
+
+			Convey("Then the entry without an action time is replayed last", func() {
+				So(err, ShouldBeNil)
+				So(len(versions), ShouldEqual, 3)
+				So(versions[0].ActionedBy, ShouldEqual, "alice")
+				So(versions[1].ActionedBy, ShouldEqual, "bob")
+				So(versions[2].ActionedBy, ShouldEqual, "carol")
+				So(versions[2].ActionTime, ShouldBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,149 @@
+package accounts
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const bankIDsType = "bankids"
+
+// BankID is a bank identifier, e.g. a sort code, registered with Form3 for an organisation
+type BankID struct {
+	Country    string `json:"country,omitempty"`
+	BankID     string `json:"bank_id,omitempty"`
+	BankIDCode string `json:"bank_id_code,omitempty"`
+
+	// Extra holds the attributes returned by the API that this library does not know about
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// BankIDBuilder returns a builder for BankID struct
+type BankIDBuilder interface {
+	Country(string) BankIDBuilder
+	BankID(string) BankIDBuilder
+	BankIDCode(string) BankIDBuilder
+	Build() BankID
+}
+
+type bankIDBuilder struct {
+	country    string
+	bankID     string
+	bankIDCode string
+}
+
+func (bb *bankIDBuilder) Country(value string) BankIDBuilder {
+	bb.country = value
+	return bb
+}
+
+func (bb *bankIDBuilder) BankID(value string) BankIDBuilder {
+	bb.bankID = value
+	return bb
+}
+
+func (bb *bankIDBuilder) BankIDCode(value string) BankIDBuilder {
+	bb.bankIDCode = value
+	return bb
+}
+
+func (bb *bankIDBuilder) Build() BankID {
+	return BankID{
+		Country:    bb.country,
+		BankID:     bb.bankID,
+		BankIDCode: bb.bankIDCode,
+	}
+}
+
+// NewBankID is used to create a BankIDBuilder
+func NewBankID() BankIDBuilder {
+	return &bankIDBuilder{}
+}
+
+type BankIDData struct {
+	ID             string     `json:"id"`
+	OrganisationID string     `json:"organisation_id"`
+	Type           string     `json:"type"`
+	CreatedOn      *time.Time `json:"created_on,omitempty"`
+	ModifiedOn     *time.Time `json:"modified_on,omitempty"`
+	Version        *int       `json:"version,omitempty"`
+	Attributes     BankID     `json:"attributes"`
+
+	// Extra holds the fields returned by the API that this library does not know about
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// BankIDDataBuilder returns a builder for BankIDData struct
+type BankIDDataBuilder interface {
+	ID(string) BankIDDataBuilder
+	OrganisationID(string) BankIDDataBuilder
+	Type(string) BankIDDataBuilder
+	Version(int) BankIDDataBuilder
+	Attributes(BankID) BankIDDataBuilder
+	Build() BankIDData
+}
+
+type bankIDDataBuilder struct {
+	id             string
+	organisationID string
+	bankIDDataType string
+	version        *int
+	attributes     BankID
+}
+
+func (bb *bankIDDataBuilder) ID(value string) BankIDDataBuilder {
+	bb.id = value
+	return bb
+}
+
+func (bb *bankIDDataBuilder) OrganisationID(value string) BankIDDataBuilder {
+	bb.organisationID = value
+	return bb
+}
+
+func (bb *bankIDDataBuilder) Type(value string) BankIDDataBuilder {
+	bb.bankIDDataType = value
+	return bb
+}
+
+func (bb *bankIDDataBuilder) Version(value int) BankIDDataBuilder {
+	bb.version = &value
+	return bb
+}
+
+func (bb *bankIDDataBuilder) Attributes(value BankID) BankIDDataBuilder {
+	bb.attributes = value
+	return bb
+}
+
+func (bb *bankIDDataBuilder) Build() BankIDData {
+	return BankIDData{
+		ID:             bb.id,
+		OrganisationID: bb.organisationID,
+		Type:           bb.bankIDDataType,
+		Version:        bb.version,
+		Attributes:     bb.attributes,
+	}
+}
+
+// NewBankIDData is used to create a BankIDDataBuilder
+func NewBankIDData() BankIDDataBuilder {
+	return &bankIDDataBuilder{}
+}
+
+// BankIDSingle is the response payload when fetching an individual bank ID
+type BankIDSingle struct {
+	BankIDData BankIDData `json:"data"`
+	Links      Links      `json:"links"`
+
+	// Extra holds the members of the response that this library does not know about
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// BankIDList is the response payload when requesting a list of bank IDs
+type BankIDList struct {
+	BankIDData *[]BankIDData `json:"data,omitempty"`
+	Links      Links         `json:"links"`
+
+	// Extra holds the members of the response that this library does not know about
+	Extra map[string]json.RawMessage `json:"-"`
+}
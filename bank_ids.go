@@ -0,0 +1,80 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/google/uuid"
+)
+
+const bankIDsPath = "/v1/organisation/bankids"
+
+// BankIDsClient is the client interface to access the bank IDs registered with the Form3 API
+//
+// The WithContext variants abort the request to the API once the context is done
+type BankIDsClient interface {
+	Create(request BankIDData) (BankIDSingle, error)
+	Fetch(id uuid.UUID) (BankIDSingle, error)
+	List(page *Page, filter *Filter) (BankIDList, error)
+	Delete(id uuid.UUID, version int) (bool, error)
+	CreateWithContext(ctx context.Context, request BankIDData) (BankIDSingle, error)
+	FetchWithContext(ctx context.Context, id uuid.UUID) (BankIDSingle, error)
+	ListWithContext(ctx context.Context, page *Page, filter *Filter) (BankIDList, error)
+	DeleteWithContext(ctx context.Context, id uuid.UUID, version int) (bool, error)
+}
+
+type bankIDsClient struct {
+	resourceClient[BankIDData, BankIDSingle, BankIDList]
+}
+
+// BankIDsClientBuilder is used to create a BankIDsClient
+type BankIDsClientBuilder interface {
+	URL(string) BankIDsClientBuilder
+	HTTPClient(http.Client) BankIDsClientBuilder
+	StrictDecoding(bool) BankIDsClientBuilder
+	MaxBodySize(int64) BankIDsClientBuilder
+	Retry(RetryPolicy) BankIDsClientBuilder
+	Middleware(...Middleware) BankIDsClientBuilder
+	Build() BankIDsClient
+}
+
+type bankIDsClientBuilder struct {
+	transportOptions[BankIDsClientBuilder]
+}
+
+func (bb *bankIDsClientBuilder) Build() BankIDsClient {
+	bankIDs := newResource[BankIDSingle, BankIDList](bb.transport(), bankIDsPath, "bank id", "bank ids")
+	return &bankIDsClient{
+		resourceClient: newResourceClient(bankIDs, bankIDsType, func(data *BankIDData, partial bool) error {
+			return validateBankID(data.Attributes)
+		}),
+	}
+}
+
+// NewBankIDsClient is used to create a BankIDsClientBuilder
+func NewBankIDsClient() BankIDsClientBuilder {
+	bb := &bankIDsClientBuilder{}
+	bb.self = bb
+	return bb
+}
+
+func validateBankID(bankID BankID) error {
+	var validCountry = regexp.MustCompile(`^[A-Z]{2}$`)
+	if validCountry.MatchString(bankID.Country) == false {
+		return &ValidationError{Field: "Country", Message: fmt.Sprintf("Invalid Country [%s]", bankID.Country)}
+	}
+
+	var validBankID = regexp.MustCompile(`^[A-Z0-9]{1,16}$`)
+	if validBankID.MatchString(bankID.BankID) == false {
+		return &ValidationError{Field: "BankID", Message: fmt.Sprintf("Invalid BankID [%s]", bankID.BankID)}
+	}
+
+	var validBankIDCode = regexp.MustCompile(`^[A-Z]{1,16}$`)
+	if validBankIDCode.MatchString(bankID.BankIDCode) == false {
+		return &ValidationError{Field: "BankIDCode", Message: fmt.Sprintf("Invalid BankIDCode [%s]", bankID.BankIDCode)}
+	}
+
+	return nil
+}
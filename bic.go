@@ -0,0 +1,129 @@
+package accounts
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const bicsType = "bics"
+
+// BIC is a Bank Identifier Code registered with Form3 for an organisation
+type BIC struct {
+	BIC string `json:"bic,omitempty"`
+
+	// Extra holds the attributes returned by the API that this library does not know about
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// BICBuilder returns a builder for BIC struct
+type BICBuilder interface {
+	BIC(string) BICBuilder
+	Build() BIC
+}
+
+type bicBuilder struct {
+	bic string
+}
+
+func (bb *bicBuilder) BIC(value string) BICBuilder {
+	bb.bic = value
+	return bb
+}
+
+func (bb *bicBuilder) Build() BIC {
+	return BIC{BIC: bb.bic}
+}
+
+// NewBIC is used to create a BICBuilder
+func NewBIC() BICBuilder {
+	return &bicBuilder{}
+}
+
+type BICData struct {
+	ID             string     `json:"id"`
+	OrganisationID string     `json:"organisation_id"`
+	Type           string     `json:"type"`
+	CreatedOn      *time.Time `json:"created_on,omitempty"`
+	ModifiedOn     *time.Time `json:"modified_on,omitempty"`
+	Version        *int       `json:"version,omitempty"`
+	Attributes     BIC        `json:"attributes"`
+
+	// Extra holds the fields returned by the API that this library does not know about
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// BICDataBuilder returns a builder for BICData struct
+type BICDataBuilder interface {
+	ID(string) BICDataBuilder
+	OrganisationID(string) BICDataBuilder
+	Type(string) BICDataBuilder
+	Version(int) BICDataBuilder
+	Attributes(BIC) BICDataBuilder
+	Build() BICData
+}
+
+type bicDataBuilder struct {
+	id             string
+	organisationID string
+	bicDataType    string
+	version        *int
+	attributes     BIC
+}
+
+func (bb *bicDataBuilder) ID(value string) BICDataBuilder {
+	bb.id = value
+	return bb
+}
+
+func (bb *bicDataBuilder) OrganisationID(value string) BICDataBuilder {
+	bb.organisationID = value
+	return bb
+}
+
+func (bb *bicDataBuilder) Type(value string) BICDataBuilder {
+	bb.bicDataType = value
+	return bb
+}
+
+func (bb *bicDataBuilder) Version(value int) BICDataBuilder {
+	bb.version = &value
+	return bb
+}
+
+func (bb *bicDataBuilder) Attributes(value BIC) BICDataBuilder {
+	bb.attributes = value
+	return bb
+}
+
+func (bb *bicDataBuilder) Build() BICData {
+	return BICData{
+		ID:             bb.id,
+		OrganisationID: bb.organisationID,
+		Type:           bb.bicDataType,
+		Version:        bb.version,
+		Attributes:     bb.attributes,
+	}
+}
+
+// NewBICData is used to create a BICDataBuilder
+func NewBICData() BICDataBuilder {
+	return &bicDataBuilder{}
+}
+
+// BICSingle is the response payload when fetching an individual BIC
+type BICSingle struct {
+	BICData BICData `json:"data"`
+	Links   Links   `json:"links"`
+
+	// Extra holds the members of the response that this library does not know about
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// BICList is the response payload when requesting a list of BICs
+type BICList struct {
+	BICData *[]BICData `json:"data,omitempty"`
+	Links   Links      `json:"links"`
+
+	// Extra holds the members of the response that this library does not know about
+	Extra map[string]json.RawMessage `json:"-"`
+}
@@ -0,0 +1,68 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+const bicsPath = "/v1/organisation/bics"
+
+// BICsClient is the client interface to access the BICs registered with the Form3 API
+//
+// The WithContext variants abort the request to the API once the context is done
+type BICsClient interface {
+	Create(request BICData) (BICSingle, error)
+	Fetch(id uuid.UUID) (BICSingle, error)
+	List(page *Page, filter *Filter) (BICList, error)
+	Delete(id uuid.UUID, version int) (bool, error)
+	CreateWithContext(ctx context.Context, request BICData) (BICSingle, error)
+	FetchWithContext(ctx context.Context, id uuid.UUID) (BICSingle, error)
+	ListWithContext(ctx context.Context, page *Page, filter *Filter) (BICList, error)
+	DeleteWithContext(ctx context.Context, id uuid.UUID, version int) (bool, error)
+}
+
+type bicsClient struct {
+	resourceClient[BICData, BICSingle, BICList]
+}
+
+// BICsClientBuilder is used to create a BICsClient
+type BICsClientBuilder interface {
+	URL(string) BICsClientBuilder
+	HTTPClient(http.Client) BICsClientBuilder
+	StrictDecoding(bool) BICsClientBuilder
+	MaxBodySize(int64) BICsClientBuilder
+	Retry(RetryPolicy) BICsClientBuilder
+	Middleware(...Middleware) BICsClientBuilder
+	Build() BICsClient
+}
+
+type bicsClientBuilder struct {
+	transportOptions[BICsClientBuilder]
+}
+
+func (bb *bicsClientBuilder) Build() BICsClient {
+	bics := newResource[BICSingle, BICList](bb.transport(), bicsPath, "bic", "bics")
+	return &bicsClient{
+		resourceClient: newResourceClient(bics, bicsType, func(data *BICData, partial bool) error {
+			return validateBIC(data.Attributes)
+		}),
+	}
+}
+
+// NewBICsClient is used to create a BICsClientBuilder
+func NewBICsClient() BICsClientBuilder {
+	bb := &bicsClientBuilder{}
+	bb.self = bb
+	return bb
+}
+
+func validateBIC(bic BIC) error {
+	if validBIC.MatchString(bic.BIC) == false {
+		return &ValidationError{Field: "BIC", Message: fmt.Sprintf("Invalid BIC [%s]", bic.BIC)}
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package accounts
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// defaultMaxBodySize is the largest response body read when the client is not told otherwise
+const defaultMaxBodySize = 10 << 20
+
+// snippetSize is the number of bytes of a body kept in errors
+const snippetSize = 512
+
+// drainLimit is the number of bytes left unread that are discarded before closing a body, so that
+// the connection can be reused; larger leftovers are cheaper to drop along with the connection
+const drainLimit = 64 << 10
+
+// readBody reads the body of a response, failing when it is larger than the maximum body size
+func (t *transport) readBody(resp *http.Response) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, t.maxBodySize+1))
+	if err != nil {
+		return nil, &ResponseError{Message: "An error has occured while reading response", Err: err, Body: snippet(body)}
+	}
+	if int64(len(body)) > t.maxBodySize {
+		return nil, &ResponseError{Message: fmt.Sprintf("Response body exceeds [%d] bytes", t.maxBodySize), Body: snippet(body)}
+	}
+
+	return body, nil
+}
+
+// drainAndClose discards what is left of a body before closing it
+func drainAndClose(body io.ReadCloser) {
+	io.Copy(io.Discard, io.LimitReader(body, drainLimit))
+	body.Close()
+}
+
+// isJSON tells whether a content type may hold JSON
+//
+// A missing type and text/plain are accepted, the latter being what Go servers send for JSON when
+// they do not set the type
+func isJSON(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || mediaType == "text/plain" ||
+		(strings.HasPrefix(mediaType, "application/") && strings.HasSuffix(mediaType, "+json"))
+}
+
+// snippet returns the start of a body to be kept in errors, marking where it was cut
+func snippet(body []byte) string {
+	if len(body) <= snippetSize {
+		return string(body)
+	}
+
+	cut := snippetSize
+	for cut > 0 && !utf8.RuneStart(body[cut]) {
+		cut--
+	}
+	return string(body[:cut]) + "..."
+}
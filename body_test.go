@@ -0,0 +1,155 @@
+package accounts
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// cannedServer answers every request with the same status, content type and body
+func cannedServer(status int, contentType string, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+}
+
+func TestUnexpectedResponses(t *testing.T) {
+
+	Convey("When a proxy answers with an HTML error page", t, func() {
+		server := cannedServer(http.StatusBadGateway, "text/html", "<html><body>502 Bad Gateway</body></html>")
+		defer server.Close()
+
+		_, err := NewClient().HTTPClient(HTTPClient).URL(server.URL).Build().Fetch(uuid.New())
+
+		Convey("Then the error tells the status and holds the body", func() {
+			var apiErr *APIError
+			So(errors.As(err, &apiErr), ShouldBeTrue)
+			So(apiErr.StatusCode, ShouldEqual, http.StatusBadGateway)
+			So(apiErr.Message, ShouldEqual, "Unexpected response [502 Bad Gateway]")
+			So(apiErr.Body, ShouldEqual, "<html><body>502 Bad Gateway</body></html>")
+		})
+	})
+
+	Convey("When the API answers an error without a body", t, func() {
+		server := cannedServer(http.StatusNotFound, "", "")
+		defer server.Close()
+
+		_, err := NewClient().HTTPClient(HTTPClient).URL(server.URL).Build().Delete(uuid.New(), 0)
+
+		Convey("Then the error tells the status", func() {
+			var apiErr *APIError
+			So(errors.As(err, &apiErr), ShouldBeTrue)
+			So(apiErr.StatusCode, ShouldEqual, http.StatusNotFound)
+		})
+	})
+
+	Convey("When the API answers an error with a message", t, func() {
+		server := cannedServer(http.StatusConflict, "application/vnd.api+json", `{"error_message":"invalid version"}`)
+		defer server.Close()
+
+		_, err := NewClient().HTTPClient(HTTPClient).URL(server.URL).Build().Delete(uuid.New(), 0)
+
+		Convey("Then the error holds the message", func() {
+			So(err, ShouldBeError, "invalid version")
+		})
+	})
+
+	Convey("When the API answers an error with malformed JSON", t, func() {
+		server := cannedServer(http.StatusInternalServerError, "application/json", `{"error_message":`)
+		defer server.Close()
+
+		_, err := NewClient().HTTPClient(HTTPClient).URL(server.URL).Build().Delete(uuid.New(), 0)
+
+		Convey("Then the error holds the body that could not be decoded", func() {
+			var responseErr *ResponseError
+			So(errors.As(err, &responseErr), ShouldBeTrue)
+			So(responseErr.Message, ShouldEqual, "An error has occured while decoding error response")
+			So(responseErr.Body, ShouldEqual, `{"error_message":`)
+		})
+	})
+
+	Convey("When the API answers a success status other than 200, 201 and 204", t, func() {
+		server := cannedServer(http.StatusAccepted, "", "")
+		defer server.Close()
+
+		deleted, err := NewClient().HTTPClient(HTTPClient).URL(server.URL).Build().Delete(uuid.New(), 0)
+
+		Convey("Then it is a success", func() {
+			So(err, ShouldBeNil)
+			So(deleted, ShouldBeTrue)
+		})
+	})
+
+	Convey("When the API answers a success with a body that is not JSON", t, func() {
+		server := cannedServer(http.StatusOK, "text/html; charset=utf-8", "<html></html>")
+		defer server.Close()
+
+		_, err := NewClient().HTTPClient(HTTPClient).URL(server.URL).Build().Fetch(uuid.New())
+
+		Convey("Then the content type is rejected", func() {
+			var responseErr *ResponseError
+			So(errors.As(err, &responseErr), ShouldBeTrue)
+			So(responseErr.Message, ShouldEqual, "Unexpected content type [text/html; charset=utf-8] in response")
+			So(responseErr.Body, ShouldEqual, "<html></html>")
+		})
+	})
+
+	Convey("When the API answers a body larger than the maximum", t, func() {
+		server := cannedServer(http.StatusOK, "application/json", `{"data":{"id":"`+strings.Repeat("a", 2000)+`"}}`)
+		defer server.Close()
+
+		_, err := NewClient().HTTPClient(HTTPClient).URL(server.URL).MaxBodySize(1000).Build().Fetch(uuid.New())
+
+		Convey("Then it is not decoded and only its start is kept", func() {
+			var responseErr *ResponseError
+			So(errors.As(err, &responseErr), ShouldBeTrue)
+			So(responseErr.Message, ShouldEqual, "Response body exceeds [1000] bytes")
+			So(len(responseErr.Body), ShouldEqual, snippetSize+len("..."))
+			So(responseErr.Body, ShouldStartWith, `{"data":{"id":"aaa`)
+			So(responseErr.Body, ShouldEndWith, "...")
+		})
+	})
+}
+
+func TestConnectionsAreReused(t *testing.T) {
+
+	Convey("Given an API answering deletes with a body the client does not read", t, func() {
+		var connections uint64
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(strings.Repeat(" ", 32<<10)))
+		}))
+		server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+			if state == http.StateNew {
+				atomic.AddUint64(&connections, 1)
+			}
+		}
+		server.Start()
+		defer server.Close()
+
+		AccountsService := NewClient().HTTPClient(http.Client{}).URL(server.URL).Build()
+
+		Convey("When I delete accounts one after the other", func() {
+			for i := 0; i < 5; i++ {
+				_, err := AccountsService.Delete(uuid.New(), 0)
+				So(err, ShouldBeNil)
+			}
+
+			Convey("Then a single connection is used", func() {
+				So(atomic.LoadUint64(&connections), ShouldEqual, 1)
+			})
+		})
+	})
+}
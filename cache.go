@@ -0,0 +1,316 @@
+package accounts
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CacheEntry is a fetched account held in a Cache
+type CacheEntry struct {
+	Single    Single
+	ETag      string
+	ExpiresAt time.Time
+}
+
+// Cache is the storage of a caching client, keyed by account ID
+//
+// Implementations must be safe for concurrent use; expiry is handled by the caching client
+type Cache interface {
+	Get(key string) (CacheEntry, bool)
+	Set(key string, entry CacheEntry)
+	Delete(key string)
+}
+
+type memoryCache struct {
+	mutex    sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+type memoryCacheItem struct {
+	key   string
+	entry CacheEntry
+}
+
+// NewMemoryCache returns an in-memory Cache evicting the least recently used entry beyond capacity
+func NewMemoryCache(capacity int) Cache {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	return &memoryCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (mc *memoryCache) Get(key string) (CacheEntry, bool) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+
+	element, ok := mc.entries[key]
+	if !ok {
+		return CacheEntry{}, false
+	}
+	mc.order.MoveToFront(element)
+
+	return element.Value.(*memoryCacheItem).entry, true
+}
+
+func (mc *memoryCache) Set(key string, entry CacheEntry) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+
+	if element, ok := mc.entries[key]; ok {
+		element.Value.(*memoryCacheItem).entry = entry
+		mc.order.MoveToFront(element)
+		return
+	}
+
+	mc.entries[key] = mc.order.PushFront(&memoryCacheItem{key: key, entry: entry})
+	if mc.order.Len() > mc.capacity {
+		oldest := mc.order.Back()
+		mc.order.Remove(oldest)
+		delete(mc.entries, oldest.Value.(*memoryCacheItem).key)
+	}
+}
+
+func (mc *memoryCache) Delete(key string) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+
+	if element, ok := mc.entries[key]; ok {
+		mc.order.Remove(element)
+		delete(mc.entries, key)
+	}
+}
+
+// CacheStats counts how Fetch calls were served by a caching client
+//
+// Revalidations are the expired entries the API confirmed as not modified, by entity tag or version;
+// they are counted as hits too
+type CacheStats struct {
+	Hits          uint64
+	Misses        uint64
+	Revalidations uint64
+}
+
+// CachingClient is a ContextClient serving Fetch from a cache
+type CachingClient interface {
+	ContextClient
+	Stats() CacheStats
+}
+
+// conditionalFetcher is implemented by clients able to fetch an account only when it changed
+type conditionalFetcher interface {
+	fetchIfNoneMatch(ctx context.Context, id uuid.UUID, etag string) (Single, string, bool, error)
+}
+
+type cachingClient struct {
+	// the counters come first to be 64-bit aligned for atomic access on 32-bit platforms
+	hits          uint64
+	misses        uint64
+	revalidations uint64
+
+	ContextClient
+	cache Cache
+	ttl   time.Duration
+
+	// generation counts the invalidations, so that a fetch started before one does not store its result
+	mutex      sync.Mutex
+	generation uint64
+}
+
+// CachingClientBuilder is used to create a CachingClient
+type CachingClientBuilder interface {
+	Client(ContextClient) CachingClientBuilder
+	Cache(Cache) CachingClientBuilder
+	TTL(time.Duration) CachingClientBuilder
+	Build() CachingClient
+}
+
+type cachingClientBuilder struct {
+	client ContextClient
+	cache  Cache
+	ttl    time.Duration
+}
+
+func (cb *cachingClientBuilder) Client(value ContextClient) CachingClientBuilder {
+	cb.client = value
+	return cb
+}
+
+// Cache is where fetched accounts are kept, an in-memory cache of 1000 entries when not set
+func (cb *cachingClientBuilder) Cache(value Cache) CachingClientBuilder {
+	cb.cache = value
+	return cb
+}
+
+// TTL is how long a fetched account is served without asking the API, a minute when not set
+func (cb *cachingClientBuilder) TTL(value time.Duration) CachingClientBuilder {
+	cb.ttl = value
+	return cb
+}
+
+func (cb *cachingClientBuilder) Build() CachingClient {
+	cc := &cachingClient{
+		ContextClient: cb.client,
+		cache:         cb.cache,
+		ttl:           cb.ttl,
+	}
+	if cc.cache == nil {
+		cc.cache = NewMemoryCache(1000)
+	}
+	if cc.ttl <= 0 {
+		cc.ttl = time.Minute
+	}
+
+	return cc
+}
+
+// NewCachingClient is used to create a CachingClientBuilder
+func NewCachingClient() CachingClientBuilder {
+	return &cachingClientBuilder{}
+}
+
+func (cc *cachingClient) Stats() CacheStats {
+	return CacheStats{
+		Hits:          atomic.LoadUint64(&cc.hits),
+		Misses:        atomic.LoadUint64(&cc.misses),
+		Revalidations: atomic.LoadUint64(&cc.revalidations),
+	}
+}
+
+// Fetch an account from the cache, falling back to the API
+func (cc *cachingClient) Fetch(id uuid.UUID) (Single, error) {
+	return cc.FetchWithContext(context.Background(), id)
+}
+
+// FetchWithContext fetches an account from the cache, falling back to the API until the context is done
+//
+// Expired entries are revalidated with their entity tag when both the API and the wrapped client support it.
+// Otherwise the account is fetched again and the entry counts as revalidated when its version did not change.
+// A failed fetch counts as a miss either way. The account returned is a copy that callers may change
+// without changing the cache
+func (cc *cachingClient) FetchWithContext(ctx context.Context, id uuid.UUID) (Single, error) {
+	key := id.String()
+	generation := cc.currentGeneration()
+	entry, ok := cc.cache.Get(key)
+	if ok && time.Now().Before(entry.ExpiresAt) {
+		atomic.AddUint64(&cc.hits, 1)
+		return deepCopy(entry.Single), nil
+	}
+
+	fetcher, conditional := cc.ContextClient.(conditionalFetcher)
+	if ok && conditional && entry.ETag != "" {
+		result, etag, notModified, err := fetcher.fetchIfNoneMatch(ctx, id, entry.ETag)
+		if err != nil {
+			atomic.AddUint64(&cc.misses, 1)
+			return Single{}, err
+		}
+		if notModified {
+			atomic.AddUint64(&cc.hits, 1)
+			atomic.AddUint64(&cc.revalidations, 1)
+			entry.ExpiresAt = time.Now().Add(cc.ttl)
+			cc.set(key, generation, entry)
+			return deepCopy(entry.Single), nil
+		}
+
+		atomic.AddUint64(&cc.misses, 1)
+		cc.set(key, generation, CacheEntry{Single: result, ETag: etag, ExpiresAt: time.Now().Add(cc.ttl)})
+		return deepCopy(result), nil
+	}
+
+	var result Single
+	var etag string
+	var err error
+	if conditional {
+		result, etag, _, err = fetcher.fetchIfNoneMatch(ctx, id, "")
+	} else {
+		result, err = cc.ContextClient.FetchWithContext(ctx, id)
+	}
+	if err != nil {
+		atomic.AddUint64(&cc.misses, 1)
+		return Single{}, err
+	}
+
+	if ok && sameVersion(entry.Single, result) {
+		atomic.AddUint64(&cc.hits, 1)
+		atomic.AddUint64(&cc.revalidations, 1)
+	} else {
+		atomic.AddUint64(&cc.misses, 1)
+	}
+	cc.set(key, generation, CacheEntry{Single: result, ETag: etag, ExpiresAt: time.Now().Add(cc.ttl)})
+	return deepCopy(result), nil
+}
+
+// sameVersion tells whether two fetches of an account returned the same version of it
+func sameVersion(cached Single, fetched Single) bool {
+	return cached.AccountData.Version != nil && fetched.AccountData.Version != nil &&
+		*cached.AccountData.Version == *fetched.AccountData.Version
+}
+
+// listLink lists the page a link of a list points to through the wrapped client, lists not being cached
+func (cc *cachingClient) listLink(ctx context.Context, link string) (List, error) {
+	lister, ok := cc.ContextClient.(linkLister)
+	if !ok {
+		return List{}, &RequestError{Message: "An error has occured while listing accounts", Err: errLinkNotSupported}
+	}
+	return lister.listLink(ctx, link)
+}
+
+func (cc *cachingClient) currentGeneration() uint64 {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+	return cc.generation
+}
+
+// set stores an entry fetched at generation, dropping it when the cache was invalidated meanwhile
+func (cc *cachingClient) set(key string, generation uint64, entry CacheEntry) {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+	if cc.generation == generation {
+		cc.cache.Set(key, entry)
+	}
+}
+
+// invalidate drops an entry, along with the results of the fetches still running
+func (cc *cachingClient) invalidate(key string) {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+	cc.generation++
+	cc.cache.Delete(key)
+}
+
+// Delete an account and drop it from the cache
+func (cc *cachingClient) Delete(id uuid.UUID, version int) (bool, error) {
+	return cc.DeleteWithContext(context.Background(), id, version)
+}
+
+// DeleteWithContext deletes an account until the context is done and drops it from the cache
+//
+// The entry is dropped even when the delete fails, as the state of the account is then unknown
+func (cc *cachingClient) DeleteWithContext(ctx context.Context, id uuid.UUID, version int) (bool, error) {
+	defer cc.invalidate(id.String())
+	return cc.ContextClient.DeleteWithContext(ctx, id, version)
+}
+
+// Update an account and drop it from the cache
+func (cc *cachingClient) Update(id uuid.UUID, request AccountData) (Single, error) {
+	return cc.UpdateWithContext(context.Background(), id, request)
+}
+
+// UpdateWithContext updates an account until the context is done and drops it from the cache
+//
+// The entry is dropped even when the update fails, as the state of the account is then unknown
+func (cc *cachingClient) UpdateWithContext(ctx context.Context, id uuid.UUID, request AccountData) (Single, error) {
+	defer cc.invalidate(id.String())
+	return cc.ContextClient.UpdateWithContext(ctx, id, request)
+}
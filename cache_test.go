@@ -0,0 +1,254 @@
+package accounts
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// etagServer serves an account of the given version with an entity tag, answering Not Modified when it matches;
+// it fails every request once failing is set
+type etagServer struct {
+	requests uint64
+	failing  int32
+	etag     string
+	version  int
+}
+
+func (es *etagServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	atomic.AddUint64(&es.requests, 1)
+	if atomic.LoadInt32(&es.failing) == 1 {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{"error_message": "internal error"}`)
+		return
+	}
+	if r.Method == http.MethodDelete {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if es.etag != "" && r.Header.Get("If-None-Match") == es.etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if es.etag != "" {
+		w.Header().Set("ETag", es.etag)
+	}
+	json.NewEncoder(w).Encode(Single{AccountData: NewAccountData().ID(r.URL.Path[len(path)+1:]).Version(es.version).Attributes(NewAccount().Country("GB").AlternativeBankAccountNames([]string{"Jane"}).Build()).Build()})
+}
+
+func TestFetchAccountThroughCache(t *testing.T) {
+
+	Convey("Given a caching client", t, func() {
+		api := &etagServer{etag: `"v1"`}
+		server := httptest.NewServer(api)
+		defer server.Close()
+
+		ID := uuid.New()
+		AccountsService := NewCachingClient().
+			Client(NewClient().HTTPClient(HTTPClient).URL(server.URL).Build()).
+			TTL(time.Hour).
+			Build()
+
+		Convey("When I fetch the same account three times", func() {
+			for i := 0; i < 3; i++ {
+				resp, err := AccountsService.Fetch(ID)
+				So(err, ShouldBeNil)
+				So(resp.AccountData.ID, ShouldEqual, ID.String())
+			}
+
+			Convey("Then the API is called once", func() {
+				So(api.requests, ShouldEqual, 1)
+				So(AccountsService.Stats(), ShouldResemble, CacheStats{Hits: 2, Misses: 1})
+			})
+		})
+
+		Convey("When I change the account a fetch returned and fetch it again", func() {
+			first, _ := AccountsService.Fetch(ID)
+			(*first.AccountData.Attributes.AlternativeBankAccountNames)[0] = "Changed"
+			*first.AccountData.Version = 7
+			resp, err := AccountsService.Fetch(ID)
+
+			Convey("Then the cached account is unchanged", func() {
+				So(err, ShouldBeNil)
+				So(*resp.AccountData.Attributes.AlternativeBankAccountNames, ShouldResemble, []string{"Jane"})
+				So(*resp.AccountData.Version, ShouldEqual, 0)
+				So(AccountsService.Stats(), ShouldResemble, CacheStats{Hits: 1, Misses: 1})
+			})
+		})
+
+		Convey("When I delete the account and fetch it again", func() {
+			AccountsService.Fetch(ID)
+			AccountsService.Delete(ID, 0)
+			AccountsService.Fetch(ID)
+
+			Convey("Then the account is fetched again from the API", func() {
+				So(api.requests, ShouldEqual, 3)
+				So(AccountsService.Stats().Misses, ShouldEqual, 2)
+			})
+		})
+	})
+
+}
+
+func TestRevalidateExpiredAccountInCache(t *testing.T) {
+
+	Convey("Given a caching client whose entries expire at once", t, func() {
+		api := &etagServer{etag: `"v1"`}
+		server := httptest.NewServer(api)
+		defer server.Close()
+
+		ID := uuid.New()
+		AccountsService := NewCachingClient().
+			Client(NewClient().HTTPClient(HTTPClient).URL(server.URL).Build()).
+			TTL(time.Nanosecond).
+			Build()
+
+		Convey("When I fetch an account twice", func() {
+			AccountsService.Fetch(ID)
+			resp, err := AccountsService.Fetch(ID)
+
+			Convey("Then the second fetch is revalidated with the entity tag", func() {
+				So(err, ShouldBeNil)
+				So(resp.AccountData.ID, ShouldEqual, ID.String())
+				So(AccountsService.Stats(), ShouldResemble, CacheStats{Hits: 1, Misses: 1, Revalidations: 1})
+			})
+		})
+
+		Convey("When the account changes between two fetches", func() {
+			AccountsService.Fetch(ID)
+			api.etag = `"v2"`
+			AccountsService.Fetch(ID)
+
+			Convey("Then the second fetch is a miss", func() {
+				So(AccountsService.Stats(), ShouldResemble, CacheStats{Misses: 2})
+			})
+		})
+
+		Convey("When the revalidation of an account fails", func() {
+			AccountsService.Fetch(ID)
+			atomic.StoreInt32(&api.failing, 1)
+			_, err := AccountsService.Fetch(ID)
+
+			Convey("Then the second fetch is a miss, as a failed first fetch is", func() {
+				So(err, ShouldNotBeNil)
+				So(AccountsService.Stats(), ShouldResemble, CacheStats{Misses: 2})
+			})
+		})
+	})
+
+}
+
+func TestRevalidateExpiredAccountInCacheByVersion(t *testing.T) {
+
+	Convey("Given a caching client whose entries expire at once, in front of an API without entity tags", t, func() {
+		api := &etagServer{}
+		server := httptest.NewServer(api)
+		defer server.Close()
+
+		ID := uuid.New()
+		AccountsService := NewCachingClient().
+			Client(NewClient().HTTPClient(HTTPClient).URL(server.URL).Build()).
+			TTL(time.Nanosecond).
+			Build()
+
+		Convey("When I fetch an account twice", func() {
+			AccountsService.Fetch(ID)
+			resp, err := AccountsService.Fetch(ID)
+
+			Convey("Then the second fetch is revalidated with the version", func() {
+				So(err, ShouldBeNil)
+				So(resp.AccountData.ID, ShouldEqual, ID.String())
+				So(api.requests, ShouldEqual, 2)
+				So(AccountsService.Stats(), ShouldResemble, CacheStats{Hits: 1, Misses: 1, Revalidations: 1})
+			})
+		})
+
+		Convey("When the version of the account changes between two fetches", func() {
+			AccountsService.Fetch(ID)
+			api.version = 1
+			resp, _ := AccountsService.Fetch(ID)
+
+			Convey("Then the second fetch is a miss returning the new version", func() {
+				So(*resp.AccountData.Version, ShouldEqual, 1)
+				So(AccountsService.Stats(), ShouldResemble, CacheStats{Misses: 2})
+			})
+		})
+	})
+
+}
+
+func TestFetchRunningDuringDeleteIsNotCached(t *testing.T) {
+
+	Convey("Given a caching client whose fetches wait to be released", t, func() {
+		fetching := make(chan struct{}, 2)
+		release := make(chan struct{})
+		var requests uint64
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddUint64(&requests, 1)
+			if r.Method == http.MethodDelete {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			fetching <- struct{}{}
+			<-release
+			json.NewEncoder(w).Encode(Single{AccountData: NewAccountData().ID(r.URL.Path[len(path)+1:]).Version(0).Build()})
+		}))
+		defer server.Close()
+
+		ID := uuid.New()
+		AccountsService := NewCachingClient().
+			Client(NewClient().HTTPClient(HTTPClient).URL(server.URL).Build()).
+			TTL(time.Hour).
+			Build()
+
+		Convey("When the account is deleted while a fetch is running", func() {
+			done := make(chan struct{})
+			go func() {
+				AccountsService.Fetch(ID)
+				close(done)
+			}()
+			<-fetching
+			AccountsService.Delete(ID, 0)
+			close(release)
+			<-done
+
+			AccountsService.Fetch(ID)
+
+			Convey("Then the result of the fetch is not cached", func() {
+				So(atomic.LoadUint64(&requests), ShouldEqual, 3)
+				So(AccountsService.Stats().Hits, ShouldEqual, 0)
+			})
+		})
+	})
+
+}
+
+func TestMemoryCacheEvictsLeastRecentlyUsedEntry(t *testing.T) {
+
+	Convey("Given an in-memory cache of 2 entries", t, func() {
+		cache := NewMemoryCache(2)
+		cache.Set("a", CacheEntry{ETag: "a"})
+		cache.Set("b", CacheEntry{ETag: "b"})
+
+		Convey("When I use the first entry and add a third one", func() {
+			cache.Get("a")
+			cache.Set("c", CacheEntry{ETag: "c"})
+
+			Convey("Then the second entry is evicted", func() {
+				_, a := cache.Get("a")
+				_, b := cache.Get("b")
+				_, c := cache.Get("c")
+				So(fmt.Sprint(a, b, c), ShouldEqual, "true false true")
+			})
+		})
+	})
+
+}
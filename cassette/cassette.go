@@ -0,0 +1,398 @@
+// Package cassette records the HTTP interactions of tests to a JSON Lines file and replays them in later runs
+package cassette
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Mode tells whether a Recorder records interactions or replays them
+type Mode int
+
+const (
+	// Auto replays the cassette when its file exists and records it otherwise
+	Auto Mode = iota
+	// Record sends every request and records it, replacing the cassette
+	Record
+	// Replay answers requests from the cassette
+	Replay
+)
+
+// DefaultRedacted are the headers whose values are not written to cassettes
+var DefaultRedacted = []string{"Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// redactedValue replaces the values of redacted headers
+const redactedValue = "REDACTED"
+
+// RecordedRequest is a request as written to a cassette
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is a response as written to a cassette
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Interaction is a request along with its response, written as a line of a cassette
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// ErrUnmatched is returned by a strict Recorder replaying a request that is not in its cassette
+var ErrUnmatched = errors.New("Unmatched request")
+
+// Recorder is an http.RoundTripper recording or replaying the interactions of a cassette
+type Recorder interface {
+	http.RoundTripper
+	// Mode is the mode the recorder runs in, never Auto once built
+	Mode() Mode
+	// Close closes the cassette file being recorded
+	Close() error
+}
+
+type recorder struct {
+	mutex     sync.Mutex
+	mode      Mode
+	transport http.RoundTripper
+	matchers  []Matcher
+	redacted  []string
+	strict    bool
+	mapIDs    bool
+	file      *os.File
+	recorded  []Interaction
+	replayed  []bool
+	ids       idMapping
+}
+
+// RecorderBuilder is used to create a Recorder
+type RecorderBuilder interface {
+	Path(string) RecorderBuilder
+	Mode(Mode) RecorderBuilder
+	Transport(http.RoundTripper) RecorderBuilder
+	Matchers(...Matcher) RecorderBuilder
+	Redact(...string) RecorderBuilder
+	Strict(bool) RecorderBuilder
+	MapIDs(bool) RecorderBuilder
+	Build() (Recorder, error)
+}
+
+type recorderBuilder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+	matchers  []Matcher
+	redacted  []string
+	strict    bool
+	mapIDs    bool
+}
+
+// Path is the cassette file
+func (rb *recorderBuilder) Path(value string) RecorderBuilder {
+	rb.path = value
+	return rb
+}
+
+// Mode is Auto when not set
+func (rb *recorderBuilder) Mode(value Mode) RecorderBuilder {
+	rb.mode = value
+	return rb
+}
+
+// Transport sends the requests being recorded, or not matched when replaying, http.DefaultTransport when not set
+func (rb *recorderBuilder) Transport(value http.RoundTripper) RecorderBuilder {
+	rb.transport = value
+	return rb
+}
+
+// Matchers tell whether a request matches a recorded one, DefaultMatchers when not set
+func (rb *recorderBuilder) Matchers(value ...Matcher) RecorderBuilder {
+	rb.matchers = value
+	return rb
+}
+
+// Redact names the headers whose values are not recorded, DefaultRedacted when not set
+func (rb *recorderBuilder) Redact(value ...string) RecorderBuilder {
+	rb.redacted = value
+	return rb
+}
+
+// Strict fails the requests not found in the cassette when replaying, instead of sending them
+func (rb *recorderBuilder) Strict(value bool) RecorderBuilder {
+	rb.strict = value
+	return rb
+}
+
+// MapIDs replays requests holding other UUIDs than the recorded ones, e.g. generated with uuid.New(), as
+// long as every recorded ID maps to the same replayed ID across the cassette; the recorded IDs of the
+// responses are rewritten to the replayed ones
+func (rb *recorderBuilder) MapIDs(value bool) RecorderBuilder {
+	rb.mapIDs = value
+	return rb
+}
+
+func (rb *recorderBuilder) Build() (Recorder, error) {
+	r := &recorder{
+		mode:      rb.mode,
+		transport: rb.transport,
+		matchers:  rb.matchers,
+		redacted:  rb.redacted,
+		strict:    rb.strict,
+		mapIDs:    rb.mapIDs,
+		ids:       idMapping{},
+	}
+	if r.transport == nil {
+		r.transport = http.DefaultTransport
+	}
+	if r.matchers == nil {
+		r.matchers = DefaultMatchers
+	}
+	if r.redacted == nil {
+		r.redacted = DefaultRedacted
+	}
+
+	if r.mode == Auto {
+		r.mode = Record
+		if _, err := os.Stat(rb.path); err == nil {
+			r.mode = Replay
+		}
+	}
+
+	if r.mode == Record {
+		file, err := os.Create(rb.path)
+		if err != nil {
+			return nil, fmt.Errorf("An error has occured while creating cassette [%s]", rb.path)
+		}
+		r.file = file
+		return r, nil
+	}
+
+	recorded, err := load(rb.path)
+	if err != nil {
+		return nil, err
+	}
+	r.recorded = recorded
+	r.replayed = make([]bool, len(recorded))
+
+	return r, nil
+}
+
+// NewRecorder is used to create a RecorderBuilder
+func NewRecorder() RecorderBuilder {
+	return &recorderBuilder{}
+}
+
+// load reads the interactions of a cassette
+func load(path string) ([]Interaction, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("An error has occured while opening cassette [%s]", path)
+	}
+	defer file.Close()
+
+	var recorded []Interaction
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var interaction Interaction
+		if err := json.Unmarshal(scanner.Bytes(), &interaction); err != nil {
+			return nil, fmt.Errorf("Invalid interaction at line [%d] of cassette [%s]", line, path)
+		}
+		recorded = append(recorded, interaction)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("An error has occured while reading cassette [%s]", path)
+	}
+
+	return recorded, nil
+}
+
+func (r *recorder) Mode() Mode {
+	return r.mode
+}
+
+func (r *recorder) Close() error {
+	if r.file == nil {
+		return nil
+	}
+	return r.file.Close()
+}
+
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == Replay {
+		return r.replay(req, body)
+	}
+
+	return r.record(req, body)
+}
+
+// readBody reads and closes the body of a request, leaving the request itself unchanged
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("An error has occured while reading request body")
+	}
+
+	return body, nil
+}
+
+// withBody returns a copy of the request sending the body read from it, as a RoundTripper must not change
+// the request it is given
+func withBody(req *http.Request, body []byte) *http.Request {
+	if body == nil {
+		return req
+	}
+
+	sent := req.Clone(req.Context())
+	sent.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return sent
+}
+
+// replay answers with the first recorded interaction matching the request that has not been replayed yet,
+// so that the same request recorded several times gets its responses in the recorded order
+//
+// When mapping IDs, the recorded request is matched once its IDs are rewritten to those of the request
+func (r *recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mutex.Lock()
+	for i, interaction := range r.recorded {
+		if r.replayed[i] {
+			continue
+		}
+
+		recorded, response, ids := interaction.Request, interaction.Response, r.ids
+		if r.mapIDs {
+			var ok bool
+			ids, ok = r.ids.pair(requestIDs(recorded.URL, []byte(recorded.Body)), requestIDs(req.URL.String(), body))
+			if !ok {
+				continue
+			}
+			recorded, response = ids.rewriteRequest(recorded), ids.rewriteResponse(response)
+		}
+		if !r.matches(req, body, recorded) {
+			continue
+		}
+		r.replayed[i] = true
+		r.ids = ids
+		r.mutex.Unlock()
+
+		return response.toResponse(req), nil
+	}
+	r.mutex.Unlock()
+
+	if r.strict {
+		return nil, fmt.Errorf("%w [%s %s]", ErrUnmatched, req.Method, req.URL)
+	}
+	return r.transport.RoundTrip(withBody(req, body))
+}
+
+func (r *recorder) matches(req *http.Request, body []byte, recorded RecordedRequest) bool {
+	for _, matcher := range r.matchers {
+		if !matcher(req, body, recorded) {
+			return false
+		}
+	}
+	return true
+}
+
+// record sends the request and appends it to the cassette along with its response
+func (r *recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(withBody(req, body))
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("An error has occured while reading response body")
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	line, err := json.Marshal(Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: r.redact(req.Header),
+			Body:   string(body),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     r.redact(resp.Header),
+			Body:       string(respBody),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("An error has occured while recording interaction")
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, err := r.file.Write(append(line, '\n')); err != nil {
+		return nil, fmt.Errorf("An error has occured while writing cassette")
+	}
+
+	return resp, nil
+}
+
+// redact copies headers, replacing the values of the redacted ones
+func (r *recorder) redact(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+
+	redacted := header.Clone()
+	for _, name := range r.redacted {
+		if values, ok := redacted[http.CanonicalHeaderKey(name)]; ok {
+			for i := range values {
+				values[i] = redactedValue
+			}
+		}
+	}
+	return redacted
+}
+
+func (rr RecordedResponse) toResponse(req *http.Request) *http.Response {
+	header := rr.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rr.StatusCode, http.StatusText(rr.StatusCode)),
+		StatusCode:    rr.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(rr.Body)),
+		ContentLength: int64(len(rr.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,254 @@
+package cassette
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// countingServer echoes the request body after the number of requests it served
+type countingServer struct {
+	requests uint64
+}
+
+func (cs *countingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	count := atomic.AddUint64(&cs.requests, 1)
+	body, _ := ioutil.ReadAll(r.Body)
+	w.Header().Set("Set-Cookie", "session=secret")
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(strings.Repeat("#", int(count)) + string(body)))
+}
+
+func send(client http.Client, method string, url string, body string) (int, string, error) {
+	req, _ := http.NewRequest(method, url, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer secret")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	read, _ := ioutil.ReadAll(resp.Body)
+	return resp.StatusCode, string(read), nil
+}
+
+func TestRecordAndReplay(t *testing.T) {
+
+	Convey("Given a cassette recorded against a server", t, func() {
+		api := &countingServer{}
+		server := httptest.NewServer(api)
+		defer server.Close()
+
+		dir, _ := ioutil.TempDir("", "cassette")
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "cassette.jsonl")
+
+		recorder, err := NewRecorder().Path(path).Build()
+		So(err, ShouldBeNil)
+		So(recorder.Mode(), ShouldEqual, Record)
+
+		client := http.Client{Transport: recorder}
+		send(client, "POST", server.URL+"/v1/things?b=2&a=1", `{"name": "first", "size": 1}`)
+		send(client, "GET", server.URL+"/v1/things/1", "")
+		send(client, "GET", server.URL+"/v1/things/1", "")
+		So(recorder.Close(), ShouldBeNil)
+
+		Convey("When the cassette is read", func() {
+			content, _ := ioutil.ReadFile(path)
+
+			Convey("Then it holds an interaction per line with sensitive headers redacted", func() {
+				So(strings.Count(string(content), "\n"), ShouldEqual, 3)
+				So(string(content), ShouldNotContainSubstring, "secret")
+				So(string(content), ShouldContainSubstring, redactedValue)
+			})
+		})
+
+		Convey("When the same requests are replayed once the server is gone", func() {
+			server.Close()
+			replayer, err := NewRecorder().Path(path).Strict(true).Build()
+			So(err, ShouldBeNil)
+			client := http.Client{Transport: replayer}
+
+			status, body, err := send(client, "POST", server.URL+"/v1/things?a=1&b=2", `{"size":1,"name":"first"}`)
+			_, first, _ := send(client, "GET", server.URL+"/v1/things/1", "")
+			_, second, _ := send(client, "GET", server.URL+"/v1/things/1", "")
+
+			Convey("Then the recorded responses are returned in the recorded order", func() {
+				So(replayer.Mode(), ShouldEqual, Replay)
+				So(err, ShouldBeNil)
+				So(status, ShouldEqual, http.StatusCreated)
+				So(body, ShouldEqual, `#{"name": "first", "size": 1}`)
+				So(first, ShouldEqual, "##")
+				So(second, ShouldEqual, "###")
+				So(api.requests, ShouldEqual, 3)
+			})
+		})
+
+		Convey("When a request that was not recorded is replayed strictly", func() {
+			replayer, _ := NewRecorder().Path(path).Mode(Replay).Strict(true).Build()
+			client := http.Client{Transport: replayer}
+
+			_, _, err := send(client, "POST", server.URL+"/v1/things?a=1&b=2", `{"name":"second","size":1}`)
+
+			Convey("Then it fails without reaching the server", func() {
+				So(errors.Is(err, ErrUnmatched), ShouldBeTrue)
+				So(api.requests, ShouldEqual, 3)
+			})
+		})
+
+		Convey("When a request that was not recorded is replayed leniently", func() {
+			replayer, _ := NewRecorder().Path(path).Mode(Replay).Build()
+			client := http.Client{Transport: replayer}
+
+			_, body, err := send(client, "DELETE", server.URL+"/v1/things/1", "")
+
+			Convey("Then it is sent to the server", func() {
+				So(err, ShouldBeNil)
+				So(body, ShouldEqual, "####")
+				So(api.requests, ShouldEqual, 4)
+			})
+		})
+
+		Convey("When requests are replayed matching on their method and path only", func() {
+			replayer, _ := NewRecorder().Path(path).Mode(Replay).Strict(true).Matchers(MatchMethod, MatchPath).Build()
+			client := http.Client{Transport: replayer}
+
+			_, body, err := send(client, "POST", server.URL+"/v1/things", "other")
+
+			Convey("Then the query and body are ignored", func() {
+				So(err, ShouldBeNil)
+				So(body, ShouldStartWith, "#{")
+			})
+		})
+	})
+}
+
+func TestRecordLeavesRequestUnchanged(t *testing.T) {
+
+	Convey("Given a cassette recording against a server", t, func() {
+		server := httptest.NewServer(&countingServer{})
+		defer server.Close()
+
+		dir, _ := ioutil.TempDir("", "cassette")
+		defer os.RemoveAll(dir)
+		recorder, _ := NewRecorder().Path(filepath.Join(dir, "cassette.jsonl")).Build()
+		defer recorder.Close()
+
+		Convey("When a request with a body is recorded", func() {
+			req, _ := http.NewRequest("POST", server.URL+"/v1/things", strings.NewReader("body"))
+			body := req.Body
+			resp, err := recorder.RoundTrip(req)
+
+			Convey("Then the body is sent without being set back on the request", func() {
+				So(err, ShouldBeNil)
+				read, _ := ioutil.ReadAll(resp.Body)
+				So(string(read), ShouldEqual, "#body")
+				So(req.Body == body, ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestReplayWithMappedIDs(t *testing.T) {
+
+	Convey("Given a cassette recorded with requests holding IDs", t, func() {
+		api := &countingServer{}
+		server := httptest.NewServer(api)
+		defer server.Close()
+
+		dir, _ := ioutil.TempDir("", "cassette")
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "cassette.jsonl")
+
+		account, organisation := uuid.New().String(), uuid.New().String()
+		recorder, _ := NewRecorder().Path(path).Build()
+		client := http.Client{Transport: recorder}
+		send(client, "POST", server.URL+"/v1/things", `{"id":"`+account+`","organisation_id":"`+organisation+`"}`)
+		send(client, "GET", server.URL+"/v1/things/"+account, "")
+		recorder.Close()
+		server.Close()
+
+		Convey("When the requests are replayed with other IDs", func() {
+			replayer, _ := NewRecorder().Path(path).Strict(true).MapIDs(true).Build()
+			client := http.Client{Transport: replayer}
+
+			otherAccount, otherOrganisation := uuid.New().String(), uuid.New().String()
+			_, created, createErr := send(client, "POST", server.URL+"/v1/things", `{"organisation_id":"`+otherOrganisation+`","id":"`+otherAccount+`"}`)
+			_, _, fetchErr := send(client, "GET", server.URL+"/v1/things/"+otherAccount, "")
+
+			Convey("Then they are matched and the responses hold the replayed IDs", func() {
+				So(createErr, ShouldBeNil)
+				So(fetchErr, ShouldBeNil)
+				So(created, ShouldEqual, `#{"id":"`+otherAccount+`","organisation_id":"`+otherOrganisation+`"}`)
+				So(created, ShouldNotContainSubstring, account)
+			})
+		})
+
+		Convey("When a replayed request holds an ID that does not map to the recorded one", func() {
+			replayer, _ := NewRecorder().Path(path).Strict(true).MapIDs(true).Build()
+			client := http.Client{Transport: replayer}
+
+			send(client, "POST", server.URL+"/v1/things", `{"id":"`+uuid.New().String()+`","organisation_id":"`+uuid.New().String()+`"}`)
+			_, _, err := send(client, "GET", server.URL+"/v1/things/"+uuid.New().String(), "")
+
+			Convey("Then it is not matched", func() {
+				So(errors.Is(err, ErrUnmatched), ShouldBeTrue)
+			})
+		})
+
+		Convey("When the requests are replayed with other IDs without mapping them", func() {
+			replayer, _ := NewRecorder().Path(path).Strict(true).Build()
+			client := http.Client{Transport: replayer}
+
+			_, _, err := send(client, "GET", server.URL+"/v1/things/"+uuid.New().String(), "")
+
+			Convey("Then they are not matched", func() {
+				So(errors.Is(err, ErrUnmatched), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestReplayMissingCassette(t *testing.T) {
+
+	Convey("When a cassette that does not exist is replayed", t, func() {
+		_, err := NewRecorder().Path(filepath.Join(os.TempDir(), "missing-cassette.jsonl")).Mode(Replay).Build()
+
+		Convey("Then it fails", func() {
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldStartWith, "An error has occured while opening cassette")
+		})
+	})
+}
+
+func TestNormaliseBody(t *testing.T) {
+
+	Convey("When JSON bodies differing only in key order and whitespace are normalised", t, func() {
+		first := NormaliseBody([]byte(`{"b": [1, 2], "a": {"d": 1.50, "c": null}}`))
+		second := NormaliseBody([]byte(`{"a":{"c":null,"d":1.50},"b":[1,2]}`))
+
+		Convey("Then they are equal", func() {
+			So(string(first), ShouldEqual, string(second))
+		})
+	})
+
+	Convey("When a body that is not JSON is normalised", t, func() {
+		normalised := NormaliseBody([]byte("  a=1&b=2\n"))
+
+		Convey("Then it is only trimmed", func() {
+			So(string(normalised), ShouldEqual, "a=1&b=2")
+		})
+	})
+}
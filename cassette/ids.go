@@ -0,0 +1,88 @@
+package cassette
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+)
+
+// uuidPattern matches the UUIDs held by requests and responses, e.g. account and organisation IDs
+var uuidPattern = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+
+// idMapping maps the IDs of a cassette to the IDs of the requests replaying it
+type idMapping map[string]string
+
+// requestIDs lists the IDs of a request in an order that does not depend on the order of its query
+// parameters or JSON keys: those of the path, then of the sorted query, then of the normalised body
+func requestIDs(rawURL string, body []byte) []string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil
+	}
+
+	var ids []string
+	for _, part := range []string{u.Path, u.Query().Encode(), string(NormaliseBody(body))} {
+		ids = append(ids, uuidPattern.FindAllString(part, -1)...)
+	}
+	return ids
+}
+
+// pair extends the mapping with the IDs of a recorded request mapped to those of a replayed one, in
+// order. It fails when their number differs or when an ID would be mapped to, or from, two IDs
+func (m idMapping) pair(recorded []string, replayed []string) (idMapping, bool) {
+	if len(recorded) != len(replayed) {
+		return nil, false
+	}
+
+	paired := make(idMapping, len(m)+len(recorded))
+	mappedTo := make(map[string]string, len(m)+len(recorded))
+	for from, to := range m {
+		paired[from] = to
+		mappedTo[to] = from
+	}
+	for i, from := range recorded {
+		to := replayed[i]
+		if current, ok := paired[from]; ok && current != to {
+			return nil, false
+		}
+		if current, ok := mappedTo[to]; ok && current != from {
+			return nil, false
+		}
+		paired[from] = to
+		mappedTo[to] = from
+	}
+
+	return paired, true
+}
+
+// rewrite replaces the recorded IDs of a value with the IDs replaying them, leaving unknown IDs as they are
+func (m idMapping) rewrite(value string) string {
+	return uuidPattern.ReplaceAllStringFunc(value, func(id string) string {
+		if replayed, ok := m[id]; ok {
+			return replayed
+		}
+		return id
+	})
+}
+
+// rewriteRequest returns a recorded request holding the replayed IDs
+func (m idMapping) rewriteRequest(recorded RecordedRequest) RecordedRequest {
+	recorded.URL = m.rewrite(recorded.URL)
+	recorded.Body = m.rewrite(recorded.Body)
+	return recorded
+}
+
+// rewriteResponse returns a recorded response holding the replayed IDs, in its body and headers
+func (m idMapping) rewriteResponse(recorded RecordedResponse) RecordedResponse {
+	recorded.Body = m.rewrite(recorded.Body)
+	if recorded.Header != nil {
+		header := make(http.Header, len(recorded.Header))
+		for name, values := range recorded.Header {
+			for _, value := range values {
+				header[name] = append(header[name], m.rewrite(value))
+			}
+		}
+		recorded.Header = header
+	}
+	return recorded
+}
@@ -0,0 +1,64 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"reflect"
+)
+
+// Matcher tells whether a request, whose body is given, matches a recorded one
+type Matcher func(req *http.Request, body []byte, recorded RecordedRequest) bool
+
+// DefaultMatchers match requests on their method, path, query and normalised body
+var DefaultMatchers = []Matcher{MatchMethod, MatchPath, MatchQuery, MatchBody}
+
+// MatchMethod matches requests with the same method
+func MatchMethod(req *http.Request, _ []byte, recorded RecordedRequest) bool {
+	return req.Method == recorded.Method
+}
+
+// MatchPath matches requests with the same path, whatever their host
+func MatchPath(req *http.Request, _ []byte, recorded RecordedRequest) bool {
+	u, err := url.Parse(recorded.URL)
+	return err == nil && u.Path == req.URL.Path
+}
+
+// MatchQuery matches requests with the same query parameters, whatever their order
+func MatchQuery(req *http.Request, _ []byte, recorded RecordedRequest) bool {
+	u, err := url.Parse(recorded.URL)
+	if err != nil {
+		return false
+	}
+
+	want, got := u.Query(), req.URL.Query()
+	if len(want) == 0 && len(got) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(want, got)
+}
+
+// MatchBody matches requests with the same body once normalised, see NormaliseBody
+func MatchBody(_ *http.Request, body []byte, recorded RecordedRequest) bool {
+	return bytes.Equal(NormaliseBody(body), NormaliseBody([]byte(recorded.Body)))
+}
+
+// NormaliseBody sorts the keys and drops the whitespace of a JSON body so that equal documents compare
+// equal, other bodies are only trimmed
+func NormaliseBody(body []byte) []byte {
+	body = bytes.TrimSpace(body)
+
+	var document interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&document); err != nil || decoder.More() {
+		return body
+	}
+
+	normalised, err := json.Marshal(document)
+	if err != nil {
+		return body
+	}
+	return normalised
+}
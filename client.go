@@ -1,18 +1,19 @@
 package accounts
 
 import (
-	"bytes"
-	"encoding/json"
-	"errors"
+	"context"
 	"fmt"
 	"net/http"
 	"regexp"
-	"strconv"
 
 	"github.com/google/uuid"
+
+	"github.com/razvanmuscalu/form3-accounts-client/internal/validation"
 )
 
 // Page holds the requested page number and size on the List function
+//
+// A size below 1 is left out of the request, the API then using its default size
 type Page struct {
 	Number int
 	Size   int
@@ -25,216 +26,227 @@ type Filter struct {
 
 // Client is the client interface to access the Accounts API
 type Client interface {
-	Create(request accountData) (Single, error)
+	Create(request AccountData) (Single, error)
 	Fetch(id uuid.UUID) (Single, error)
 	List(page *Page, filter *Filter) (List, error)
 	Delete(id uuid.UUID, version int) (bool, error)
 }
 
+// ContextClient is a Client which also updates accounts, and whose WithContext variants abort the request to
+// the API once the context is done
+//
+// It is kept apart from Client so that types implementing Client outside of this package still implement it
+type ContextClient interface {
+	Client
+	Update(id uuid.UUID, request AccountData) (Single, error)
+	CreateWithContext(ctx context.Context, request AccountData) (Single, error)
+	FetchWithContext(ctx context.Context, id uuid.UUID) (Single, error)
+	ListWithContext(ctx context.Context, page *Page, filter *Filter) (List, error)
+	UpdateWithContext(ctx context.Context, id uuid.UUID, request AccountData) (Single, error)
+	DeleteWithContext(ctx context.Context, id uuid.UUID, version int) (bool, error)
+}
+
 type client struct {
-	url        string
-	httpClient http.Client
+	accounts  resource[Single, List]
+	preCreate []PreCreateHook
 }
 
-// ClientBuilder is used to create a Client
+// PreCreateHook checks an account about to be created, Create returning the error it returns without
+// sending the request
+type PreCreateHook func(ctx context.Context, request AccountData) error
+
+// ClientBuilder is used to create a ContextClient
 type ClientBuilder interface {
 	URL(string) ClientBuilder
 	HTTPClient(http.Client) ClientBuilder
-	Build() Client
+	StrictDecoding(bool) ClientBuilder
+	MaxBodySize(int64) ClientBuilder
+	Retry(RetryPolicy) ClientBuilder
+	Middleware(...Middleware) ClientBuilder
+	PreCreate(...PreCreateHook) ClientBuilder
+	Build() ContextClient
 }
 
 type clientBuilder struct {
-	url        string
-	httpClient http.Client
+	transportOptions[ClientBuilder]
+	preCreate []PreCreateHook
 }
 
-func (cb *clientBuilder) URL(value string) ClientBuilder {
-	cb.url = value
+// PreCreate adds hooks run in order on every account that passed validation, before it is created
+func (cb *clientBuilder) PreCreate(values ...PreCreateHook) ClientBuilder {
+	cb.preCreate = append(cb.preCreate, values...)
 	return cb
 }
 
-func (cb *clientBuilder) HTTPClient(value http.Client) ClientBuilder {
-	cb.httpClient = value
-	return cb
-}
-
-func (cb *clientBuilder) Build() Client {
-	return &client{
-		url:        cb.url,
-		httpClient: cb.httpClient,
-	}
+func (cb *clientBuilder) Build() ContextClient {
+	return &client{accounts: newAccountsResource(cb.transport()), preCreate: cb.preCreate}
 }
 
 // NewClient is used to create a ClientBuilder
 func NewClient() ClientBuilder {
-	return &clientBuilder{}
+	cb := &clientBuilder{}
+	cb.self = cb
+	return cb
 }
 
 const path = "/v1/organisation/accounts"
 
+func newAccountsResource(t *transport) resource[Single, List] {
+	return newResource[Single, List](t, path, "account", "accounts")
+}
+
 // Create an account
 //
-// The request is pre-validated to avoid unnecessary Bad Request
-func (c client) Create(request accountData) (Single, error) {
-
-	if err := validateAccount(request.Attributes); err != nil {
-		return Single{}, err
-	}
+// The request is pre-validated to avoid unnecessary Bad Request, then checked by the pre-create hooks
+func (c client) Create(request AccountData) (Single, error) {
+	return c.CreateWithContext(context.Background(), request)
+}
 
-	req := new(bytes.Buffer)
-	if err := json.NewEncoder(req).Encode(NewAccountDataRequest().AccountData(request).Build()); err != nil {
-		return Single{}, fmt.Errorf("An error has occured while encoding request")
-	}
-	resp, err := c.httpClient.Post(fmt.Sprintf("%s%s", c.url, path), "application/json", req)
-	if err != nil {
-		return Single{}, fmt.Errorf("An error has occured while creating account")
-	}
-	defer resp.Body.Close()
+// CreateWithContext creates an account until the context is done
+func (c client) CreateWithContext(ctx context.Context, request AccountData) (Single, error) {
 
-	if err := decodeErrorResponse(resp); err != nil {
+	if err := validateAccount(request.Attributes); err != nil {
 		return Single{}, err
 	}
-
-	var result Single
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return Single{}, fmt.Errorf("An error has occured while decoding response")
+	for _, hook := range c.preCreate {
+		if err := hook(ctx, request); err != nil {
+			return Single{}, err
+		}
 	}
 
-	return result, nil
-
+	return c.accounts.create(ctx, NewAccountDataRequest().AccountData(request).Build())
 }
 
 // Fetch an account
 func (c client) Fetch(id uuid.UUID) (Single, error) {
+	return c.FetchWithContext(context.Background(), id)
+}
 
-	resp, err := c.httpClient.Get(fmt.Sprintf("%s%s/%s", c.url, path, id))
-	if err != nil {
-		return Single{}, fmt.Errorf("An error has occured while fetching account")
-	}
-	defer resp.Body.Close()
+// FetchWithContext fetches an account until the context is done
+func (c client) FetchWithContext(ctx context.Context, id uuid.UUID) (Single, error) {
+	result, _, _, err := c.fetchIfNoneMatch(ctx, id, "")
+	return result, err
+}
 
-	if err := decodeErrorResponse(resp); err != nil {
-		return Single{}, err
-	}
+// fetchIfNoneMatch fetches an account unless its entity tag still matches etag, returning the
+// entity tag of the account and whether it was not modified
+func (c client) fetchIfNoneMatch(ctx context.Context, id uuid.UUID, etag string) (Single, string, bool, error) {
+	return c.accounts.fetch(ctx, id.String(), etag)
+}
 
-	var result Single
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return Single{}, fmt.Errorf("An error has occured while decoding response")
-	}
+// List accounts
+func (c client) List(page *Page, filter *Filter) (List, error) {
+	return c.ListWithContext(context.Background(), page, filter)
+}
 
-	return result, nil
+// ListWithContext lists accounts until the context is done
+//
+// Concurrent lists of the same page with the same filter share a single request, and so the same result
+func (c client) ListWithContext(ctx context.Context, page *Page, filter *Filter) (List, error) {
+	return c.accounts.list(ctx, page, filterParams(filter))
+}
 
+// listLink lists the page of accounts a link of a list points to, so that a Scanner can follow a last link
+// naming the last page rather than numbering it
+func (c client) listLink(ctx context.Context, link string) (List, error) {
+	return c.accounts.listLink(ctx, link)
 }
 
-// List accounts
-func (c client) List(page *Page, filter *Filter) (List, error) {
+// Update the attributes of an account
+//
+// Only the attributes set in the request are changed; its version must be the current version of the
+// account. The ID and type of the request default to those of the account
+func (c client) Update(id uuid.UUID, request AccountData) (Single, error) {
+	return c.UpdateWithContext(context.Background(), id, request)
+}
 
-	resp, err := c.httpClient.Get(buildListURL(c.url, page, filter))
-	if err != nil {
-		return List{}, fmt.Errorf("An error has occured while listing accounts")
-	}
-	defer resp.Body.Close()
+// UpdateWithContext updates an account until the context is done
+func (c client) UpdateWithContext(ctx context.Context, id uuid.UUID, request AccountData) (Single, error) {
 
-	if err := decodeErrorResponse(resp); err != nil {
-		return List{}, err
+	if err := validateAttributes(request.Attributes, true); err != nil {
+		return Single{}, err
 	}
-
-	var result List
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return List{}, fmt.Errorf("An error has occured while decoding response")
+	if request.ID == "" {
+		request.ID = id.String()
+	}
+	if request.Type == "" {
+		request.Type = accountsType
 	}
 
-	return result, nil
+	return c.accounts.update(ctx, id.String(), accountPatchRequest{AccountData: request})
 }
 
 // Delete an account
 func (c client) Delete(id uuid.UUID, version int) (bool, error) {
+	return c.DeleteWithContext(context.Background(), id, version)
+}
 
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s%s/%s?version=%s", c.url, path, id, strconv.Itoa(version)), new(bytes.Buffer))
-	if err != nil {
-		return false, fmt.Errorf("An error has occured while constructing delete request")
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return false, fmt.Errorf("An error has occured while deleting account")
-	}
-	defer resp.Body.Close()
-
-	if err := decodeErrorResponse(resp); err != nil {
+// DeleteWithContext deletes an account until the context is done
+func (c client) DeleteWithContext(ctx context.Context, id uuid.UUID, version int) (bool, error) {
+	if err := c.accounts.delete(ctx, id.String(), version); err != nil {
 		return false, err
 	}
 
 	return true, nil
 }
 
-func decodeErrorResponse(resp *http.Response) error {
-	if !(resp.StatusCode == 200 || resp.StatusCode == 201 || resp.StatusCode == 204) {
-		var result ErrorResponse
-		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-			return fmt.Errorf("An error has occured while decoding error response")
-		}
+var validBIC = regexp.MustCompile(`^([A-Z]{6}[A-Z0-9]{2}|[A-Z]{6}[A-Z0-9]{5})$`)
 
-		return errors.New(result.ErrorMessage)
-	}
+// the test doubles of Client validate accounts as Create and Update do
+func init() {
+	validation.Register(validateAttributes)
+}
 
-	return nil
+func validateAccount(account Account) error {
+	return validateAttributes(account, false)
 }
 
-func validateAccount(account account) error {
-	var validBIC = regexp.MustCompile(`^([A-Z]{6}[A-Z0-9]{2}|[A-Z]{6}[A-Z0-9]{5})$`)
+// validateAttributes validates the attributes of an account, those of an update being partial and
+// so allowed to leave out the country
+func validateAttributes(account Account, partial bool) error {
 	if account.BIC != nil && validBIC.MatchString(*account.BIC) == false {
-		return fmt.Errorf("Invalid BIC [%s]", *account.BIC)
+		return &ValidationError{Field: "BIC", Message: fmt.Sprintf("Invalid BIC [%s]", *account.BIC)}
 	}
 
 	var validAccountClassification = regexp.MustCompile(`^(Personal|Business)$`)
 	if account.AccountClassification != nil && validAccountClassification.MatchString(*account.AccountClassification) == false {
-		return fmt.Errorf("Invalid AccountClassification [%s]", *account.AccountClassification)
+		return &ValidationError{Field: "AccountClassification", Message: fmt.Sprintf("Invalid AccountClassification [%s]", *account.AccountClassification)}
 	}
 
 	var validBankID = regexp.MustCompile(`^[A-Z0-9]{0,16}$`)
 	if account.BankID != nil && validBankID.MatchString(*account.BankID) == false {
-		return fmt.Errorf("Invalid BankID [%s]", *account.BankID)
+		return &ValidationError{Field: "BankID", Message: fmt.Sprintf("Invalid BankID [%s]", *account.BankID)}
 	}
 
 	var validBaseCurrency = regexp.MustCompile(`^[A-Z]{3}$`)
 	if account.BaseCurrency != nil && validBaseCurrency.MatchString(*account.BaseCurrency) == false {
-		return fmt.Errorf("Invalid BaseCurrency [%s]", *account.BaseCurrency)
+		return &ValidationError{Field: "BaseCurrency", Message: fmt.Sprintf("Invalid BaseCurrency [%s]", *account.BaseCurrency)}
 	}
 
 	var validCountry = regexp.MustCompile(`^[A-Z]{2}$`)
-	if validCountry.MatchString(account.Country) == false {
-		return fmt.Errorf("Invalid Country [%s]", account.Country)
+	if (!partial || account.Country != "") && validCountry.MatchString(account.Country) == false {
+		return &ValidationError{Field: "Country", Message: fmt.Sprintf("Invalid Country [%s]", account.Country)}
 	}
 
 	if account.AlternativeBankAccountNames != nil && len(*account.AlternativeBankAccountNames) > 3 {
-		return fmt.Errorf("Invalid AlternativeBankAccountNames %s", *account.AlternativeBankAccountNames)
+		return &ValidationError{Field: "AlternativeBankAccountNames", Message: fmt.Sprintf("Invalid AlternativeBankAccountNames %s", *account.AlternativeBankAccountNames)}
 	}
 
 	return nil
 }
 
 func buildListURL(baseURL string, page *Page, filter *Filter) string {
+	return buildQueryURL(baseURL+path, page, filterParams(filter))
+}
 
-	var params []string
-	if page != nil {
-		params = append(params, fmt.Sprintf("page[number]=%s", strconv.Itoa(page.Number)))
-		params = append(params, fmt.Sprintf("page[size]=%s", strconv.Itoa(page.Size)))
-	}
+// filterParams returns the query parameters of a filter on accounts
+func filterParams(filter *Filter) []queryParam {
+	var params []queryParam
 	if filter != nil {
 		if filter.OrganisationID != nil {
-			params = append(params, fmt.Sprintf("filter[organisation_id]=%s", *filter.OrganisationID))
-		}
-	}
-
-	var URL bytes.Buffer
-	URL.WriteString(fmt.Sprintf("%s%s", baseURL, path))
-	for i, param := range params {
-		if i == 0 {
-			URL.WriteString(fmt.Sprintf("?%s", param))
+			params = append(params, queryParam{name: "filter[organisation_id]", value: *filter.OrganisationID})
 		}
-		URL.WriteString(fmt.Sprintf("&%s", param))
 	}
 
-	return URL.String()
+	return params
 }
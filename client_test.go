@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/razvanmuscalu/form3-accounts-client/cassette"
 
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -307,6 +308,39 @@ func TestFetchBareMinimumAccount(t *testing.T) {
 
 }
 
+func TestFetchBareMinimumAccountFromCassette(t *testing.T) {
+
+	Convey("Given I created an account with only required fields through a cassette", t, func() {
+		recorder, err := cassette.NewRecorder().Path("testdata/fetch_bare_minimum_account.jsonl").Strict(true).MapIDs(true).Build()
+		So(err, ShouldBeNil)
+		defer recorder.Close()
+		AccountsService := NewClient().HTTPClient(http.Client{Timeout: HTTPClient.Timeout, Transport: recorder}).URL(GetURL()).Build()
+
+		ID := uuid.New()
+
+		AccountData := NewAccountData().
+			Attributes(NewAccount().Country("GB").Build()).
+			ID(ID.String()).
+			Type(Type).
+			OrganisationID(OrganisationID).
+			Build()
+
+		AccountsService.Create(AccountData)
+
+		Convey("When I fetch the account by ID", func() {
+
+			resp, _ := AccountsService.Fetch(ID)
+
+			Convey("Then the required account fields should equal", func() {
+				So(resp.AccountData.Attributes, ShouldResemble, NewAccount().Country("GB").Build())
+			})
+
+		})
+
+	})
+
+}
+
 func TestFetchFailure(t *testing.T) {
 
 	Convey("When I fetch an account by ID on a non-existent server", t, func() {
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+
+	accounts "github.com/razvanmuscalu/form3-accounts-client"
+)
+
+// attribute maps a command line flag onto an account attribute
+type attribute struct {
+	name  string
+	usage string
+	set   func(a *accounts.Account, value string) error
+}
+
+func stringAttribute(name string, usage string, field func(a *accounts.Account) **string) attribute {
+	return attribute{name: name, usage: usage, set: func(a *accounts.Account, value string) error {
+		*field(a) = &value
+		return nil
+	}}
+}
+
+func boolAttribute(name string, usage string, field func(a *accounts.Account) **bool) attribute {
+	return attribute{name: name, usage: usage, set: func(a *accounts.Account, value string) error {
+		parsed, err := parseBool(value)
+		if err != nil {
+			return fmt.Errorf("Invalid %s [%s]", name, value)
+		}
+		*field(a) = &parsed
+		return nil
+	}}
+}
+
+var attributes = []attribute{
+	{name: "country", usage: "ISO 3166-1 code of the country of the account", set: func(a *accounts.Account, value string) error {
+		a.Country = value
+		return nil
+	}},
+	stringAttribute("base-currency", "ISO 4217 code of the base currency", func(a *accounts.Account) **string { return &a.BaseCurrency }),
+	stringAttribute("bank-id", "local country bank identifier", func(a *accounts.Account) **string { return &a.BankID }),
+	stringAttribute("bank-id-code", "type of the bank identifier", func(a *accounts.Account) **string { return &a.BankIDCode }),
+	stringAttribute("account-number", "account number", func(a *accounts.Account) **string { return &a.AccountNumber }),
+	stringAttribute("bic", "SWIFT BIC", func(a *accounts.Account) **string { return &a.BIC }),
+	stringAttribute("iban", "IBAN", func(a *accounts.Account) **string { return &a.IBAN }),
+	stringAttribute("customer-id", "customer reference", func(a *accounts.Account) **string { return &a.CustomerID }),
+	stringAttribute("title", "title of the account holder", func(a *accounts.Account) **string { return &a.Title }),
+	stringAttribute("first-name", "first name of the account holder", func(a *accounts.Account) **string { return &a.FirstName }),
+	stringAttribute("bank-account-name", "name of the account holder", func(a *accounts.Account) **string { return &a.BankAccountName }),
+	{name: "alternative-bank-account-names", usage: "comma separated alternative names of the account holder", set: func(a *accounts.Account, value string) error {
+		names := strings.Split(value, ",")
+		a.AlternativeBankAccountNames = &names
+		return nil
+	}},
+	stringAttribute("account-classification", "Personal or Business", func(a *accounts.Account) **string { return &a.AccountClassification }),
+	boolAttribute("joint-account", "whether the account is held jointly", func(a *accounts.Account) **bool { return &a.JointAccount }),
+	boolAttribute("account-matching-opt-out", "whether the account opted out of name matching", func(a *accounts.Account) **bool { return &a.AccountMatchingOptOut }),
+	stringAttribute("secondary-identification", "secondary identification, e.g. a building society roll number", func(a *accounts.Account) **string { return &a.SecondaryIdentification }),
+}
+
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "true", "yes", "1":
+		return true, nil
+	case "false", "no", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("Invalid boolean [%s]", value)
+}
+
+func create(args []string, stdout io.Writer, stderr io.Writer) int {
+	fs := flag.NewFlagSet("create", flag.ContinueOnError)
+	o := commonFlags(fs)
+	file := fs.String("file", "", "JSON file holding the account to create, - for standard input")
+	id := fs.String("id", "", "ID of the account, generated when omitted")
+	organisationID := fs.String("organisation-id", "", "ID of the organisation owning the account")
+	values := make(map[string]*string)
+	for _, a := range attributes {
+		values[a.name] = fs.String(a.name, "", a.usage)
+	}
+	if code := parse(fs, o, args, stderr); code >= 0 {
+		return code
+	}
+
+	var data accounts.AccountData
+	if *file != "" {
+		var err error
+		if data, err = readAccountData(*file); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitUsage
+		}
+	}
+
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "id":
+			data.ID = *id
+		case "organisation-id":
+			data.OrganisationID = *organisationID
+		}
+		for _, a := range attributes {
+			if a.name == f.Name && err == nil {
+				err = a.set(&data.Attributes, *values[a.name])
+			}
+		}
+	})
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	if data.ID == "" {
+		data.ID = uuid.New().String()
+	}
+	if data.Type == "" {
+		data.Type = "accounts"
+	}
+	if data.OrganisationID == "" {
+		fmt.Fprintln(stderr, "An organisation ID is required, set it with -organisation-id or in the file")
+		return exitUsage
+	}
+
+	resp, err := o.client().Create(data)
+	if err != nil {
+		return fail(err, stderr)
+	}
+
+	return printResult(stdout, stderr, o.output, resp)
+}
+
+// readAccountData reads an account either wrapped in a data member, as sent to the API, or on its own
+func readAccountData(path string) (accounts.AccountData, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return accounts.AccountData{}, fmt.Errorf("An error has occured while opening [%s]", path)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return accounts.AccountData{}, fmt.Errorf("An error has occured while decoding [%s]", path)
+	}
+
+	body, ok := raw["data"]
+	if !ok {
+		body, _ = json.Marshal(raw)
+	}
+
+	var data accounts.AccountData
+	if err := json.Unmarshal(body, &data); err != nil {
+		return accounts.AccountData{}, fmt.Errorf("An error has occured while decoding [%s]", path)
+	}
+
+	return data, nil
+}
+
+func fetch(args []string, stdout io.Writer, stderr io.Writer) int {
+	fs := flag.NewFlagSet("fetch", flag.ContinueOnError)
+	o := commonFlags(fs)
+	id := fs.String("id", "", "ID of the account")
+	if code := parse(fs, o, args, stderr); code >= 0 {
+		return code
+	}
+
+	parsed, err := uuid.Parse(*id)
+	if err != nil {
+		fmt.Fprintf(stderr, "Invalid ID [%s]\n", *id)
+		return exitUsage
+	}
+
+	resp, err := o.client().Fetch(parsed)
+	if err != nil {
+		return fail(err, stderr)
+	}
+
+	return printResult(stdout, stderr, o.output, resp)
+}
+
+func list(args []string, stdout io.Writer, stderr io.Writer) int {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	o := commonFlags(fs)
+	number := fs.Int("page-number", 0, "number of the page to list")
+	size := fs.Int("page-size", 0, "number of accounts per page, the API default when omitted")
+	organisationID := fs.String("organisation-id", "", "only list the accounts of this organisation")
+	if code := parse(fs, o, args, stderr); code >= 0 {
+		return code
+	}
+
+	var page *accounts.Page
+	var filter *accounts.Filter
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "page-number", "page-size":
+			page = &accounts.Page{Number: *number, Size: *size}
+		case "organisation-id":
+			filter = &accounts.Filter{OrganisationID: organisationID}
+		}
+	})
+
+	resp, err := o.client().List(page, filter)
+	if err != nil {
+		return fail(err, stderr)
+	}
+
+	return printResult(stdout, stderr, o.output, resp)
+}
+
+func update(args []string, stdout io.Writer, stderr io.Writer) int {
+	fs := flag.NewFlagSet("update", flag.ContinueOnError)
+	o := commonFlags(fs)
+	file := fs.String("file", "", "JSON file holding the attributes to change, - for standard input")
+	id := fs.String("id", "", "ID of the account")
+	version := fs.Int("version", 0, "current version of the account, overriding the one of the file")
+	values := make(map[string]*string)
+	for _, a := range attributes {
+		values[a.name] = fs.String(a.name, "", a.usage)
+	}
+	if code := parse(fs, o, args, stderr); code >= 0 {
+		return code
+	}
+
+	parsed, err := uuid.Parse(*id)
+	if err != nil {
+		fmt.Fprintf(stderr, "Invalid ID [%s]\n", *id)
+		return exitUsage
+	}
+
+	var data accounts.AccountData
+	if *file != "" {
+		if data, err = readAccountData(*file); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitUsage
+		}
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "version" {
+			data.Version = version
+		}
+		for _, a := range attributes {
+			if a.name == f.Name && err == nil {
+				err = a.set(&data.Attributes, *values[a.name])
+			}
+		}
+	})
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	resp, err := o.client().Update(parsed, data)
+	if err != nil {
+		return fail(err, stderr)
+	}
+
+	return printResult(stdout, stderr, o.output, resp)
+}
+
+func remove(args []string, stdout io.Writer, stderr io.Writer) int {
+	fs := flag.NewFlagSet("delete", flag.ContinueOnError)
+	o := commonFlags(fs)
+	id := fs.String("id", "", "ID of the account")
+	version := fs.Int("version", 0, "version of the account")
+	if code := parse(fs, o, args, stderr); code >= 0 {
+		return code
+	}
+
+	parsed, err := uuid.Parse(*id)
+	if err != nil {
+		fmt.Fprintf(stderr, "Invalid ID [%s]\n", *id)
+		return exitUsage
+	}
+
+	if _, err := o.client().Delete(parsed, *version); err != nil {
+		return fail(err, stderr)
+	}
+
+	return printResult(stdout, stderr, o.output, deleted{ID: parsed.String(), Version: *version})
+}
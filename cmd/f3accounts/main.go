@@ -0,0 +1,170 @@
+// Command f3accounts manages accounts through the Form3 Accounts API
+//
+// Usage:
+//
+//	f3accounts <command> [flags]
+//
+// The commands are create, fetch, list, update and delete. The base URL of the API is read from
+// the -url flag, falling back to the ACCOUNTS_API_URL environment variable and then to
+// http://localhost:8080. Results are printed as a table, JSON or YAML depending on -output.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	accounts "github.com/razvanmuscalu/form3-accounts-client"
+)
+
+// Exit codes, one per kind of error so that scripts can react to them
+const (
+	exitOK          = 0
+	exitError       = 1
+	exitUsage       = 2
+	exitValidation  = 3
+	exitNotFound    = 4
+	exitConflict    = 5
+	exitAPI         = 6
+	exitUnavailable = 7
+	exitResponse    = 8
+)
+
+type command func(args []string, stdout io.Writer, stderr io.Writer) int
+
+var commands = map[string]command{
+	"create": create,
+	"fetch":  fetch,
+	"list":   list,
+	"update": update,
+	"delete": remove,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitUsage
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "Unknown command [%s]\n", args[0])
+		usage(stderr)
+		return exitUsage
+	}
+
+	return cmd(args[1:], stdout, stderr)
+}
+
+func usage(w io.Writer) {
+	var names []string
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "Usage: f3accounts <command> [flags]")
+	fmt.Fprintln(w, "Commands:")
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s\n", name)
+	}
+	fmt.Fprintln(w, "Run f3accounts <command> -h for the flags of a command")
+}
+
+// options holds the flags shared by every command
+type options struct {
+	url     string
+	output  string
+	timeout time.Duration
+}
+
+func commonFlags(fs *flag.FlagSet) *options {
+	o := &options{}
+	fs.StringVar(&o.url, "url", defaultURL(), "base URL of the Accounts API")
+	fs.StringVar(&o.output, "output", "table", "output format: table, json or yaml")
+	fs.DurationVar(&o.timeout, "timeout", 10*time.Second, "timeout of each request to the API")
+	return o
+}
+
+func defaultURL() string {
+	value := os.Getenv("ACCOUNTS_API_URL")
+	if len(value) == 0 {
+		return "http://localhost:8080"
+	}
+	return value
+}
+
+func (o *options) client() accounts.ContextClient {
+	return accounts.NewClient().
+		HTTPClient(http.Client{Timeout: o.timeout}).
+		URL(o.url).
+		Build()
+}
+
+func (o *options) validate() error {
+	if _, ok := printers[o.output]; !ok {
+		return fmt.Errorf("Invalid output [%s]", o.output)
+	}
+	return nil
+}
+
+// parse parses the flags of a command, returning a non-negative exit code when the command should stop
+func parse(fs *flag.FlagSet, o *options, args []string, stderr io.Writer) int {
+	fs.SetOutput(stderr)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if err := o.validate(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "Unexpected arguments %s\n", fs.Args())
+		return exitUsage
+	}
+
+	return -1
+}
+
+// fail reports an error and returns the exit code matching its type
+func fail(err error, stderr io.Writer) int {
+	fmt.Fprintln(stderr, err)
+
+	var validationErr *accounts.ValidationError
+	var apiErr *accounts.APIError
+	var requestErr *accounts.RequestError
+	var responseErr *accounts.ResponseError
+
+	switch {
+	case errors.As(err, &validationErr):
+		return exitValidation
+	case errors.As(err, &apiErr):
+		switch apiErr.StatusCode {
+		case http.StatusNotFound:
+			return exitNotFound
+		case http.StatusConflict:
+			return exitConflict
+		case http.StatusBadRequest:
+			return exitValidation
+		}
+		return exitAPI
+	case errors.As(err, &requestErr):
+		return exitUnavailable
+	case errors.As(err, &responseErr):
+		return exitResponse
+	}
+
+	return exitError
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const account = `{
+	"data": {"id": "%[1]s", "organisation_id": "%[2]s", "type": "accounts", "version": 0, "attributes": {"country": "GB", "bank_id": "400300", "joint_account": true}},
+	"links": {"self": "/v1/organisation/accounts/%[1]s"}
+}`
+
+func TestCreateAccountFromFlags(t *testing.T) {
+
+	Convey("Given the API accepts accounts", t, func() {
+		var body string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			raw, _ := ioutil.ReadAll(r.Body)
+			body = string(raw)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, account, "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c")
+		}))
+		defer server.Close()
+
+		Convey("When I create an account with attribute flags and JSON output", func() {
+			var stdout, stderr bytes.Buffer
+			code := run([]string{"create", "-url", server.URL, "-output", "json", "-organisation-id", "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c",
+				"-country", "GB", "-bank-id", "400300", "-joint-account", "true"}, &stdout, &stderr)
+
+			Convey("Then the command succeeds", func() {
+				So(code, ShouldEqual, exitOK)
+				So(stderr.String(), ShouldEqual, "")
+			})
+
+			Convey("And the attributes are sent to the API", func() {
+				So(body, ShouldContainSubstring, `"bank_id":"400300"`)
+				So(body, ShouldContainSubstring, `"joint_account":true`)
+			})
+
+			Convey("And the created account is printed as JSON", func() {
+				So(stdout.String(), ShouldContainSubstring, `"id": "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"`)
+			})
+		})
+
+		Convey("When I create an account from a JSON file", func() {
+			file := filepath.Join(os.TempDir(), uuid.New().String()+".json")
+			ioutil.WriteFile(file, []byte(`{"data": {"organisation_id": "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c", "attributes": {"country": "FR"}}}`), 0600)
+			defer os.Remove(file)
+
+			var stdout, stderr bytes.Buffer
+			code := run([]string{"create", "-url", server.URL, "-file", file, "-bic", "NWBKGB42"}, &stdout, &stderr)
+
+			Convey("Then the account from the file is sent with the flags on top", func() {
+				So(code, ShouldEqual, exitOK)
+				So(body, ShouldContainSubstring, `"country":"FR"`)
+				So(body, ShouldContainSubstring, `"bic":"NWBKGB42"`)
+				So(body, ShouldContainSubstring, `"type":"accounts"`)
+			})
+		})
+	})
+
+}
+
+func TestCreateInvalidAccount(t *testing.T) {
+
+	Convey("When I create an account with an invalid country", t, func() {
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"create", "-url", "http://unknown:9999", "-organisation-id", "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c", "-country", "GBR"}, &stdout, &stderr)
+
+		Convey("Then the validation error is reported with its own exit code", func() {
+			So(code, ShouldEqual, exitValidation)
+			So(stderr.String(), ShouldEqual, "Invalid Country [GBR]\n")
+		})
+	})
+
+}
+
+func TestFetchAccountAsYAML(t *testing.T) {
+
+	Convey("Given the API returns an account", t, func() {
+		ID := uuid.New()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, account, ID, "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c")
+		}))
+		defer server.Close()
+
+		Convey("When I fetch it with YAML output", func() {
+			var stdout, stderr bytes.Buffer
+			code := run([]string{"fetch", "-url", server.URL, "-output", "yaml", "-id", ID.String()}, &stdout, &stderr)
+
+			Convey("Then the account is printed as YAML", func() {
+				So(code, ShouldEqual, exitOK)
+				So(stdout.String(), ShouldStartWith, fmt.Sprintf("data:\n  id: %s\n", ID))
+				So(stdout.String(), ShouldContainSubstring, "    bank_id: \"400300\"\n")
+				So(stdout.String(), ShouldContainSubstring, "    joint_account: true\n")
+			})
+		})
+	})
+
+}
+
+func TestFetchNonExistentAccount(t *testing.T) {
+
+	Convey("Given the API does not know an account", t, func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error_message": "record does not exist"}`)
+		}))
+		defer server.Close()
+
+		Convey("When I fetch it", func() {
+			var stdout, stderr bytes.Buffer
+			code := run([]string{"fetch", "-url", server.URL, "-id", uuid.New().String()}, &stdout, &stderr)
+
+			Convey("Then the not found exit code is returned", func() {
+				So(code, ShouldEqual, exitNotFound)
+				So(stderr.String(), ShouldEqual, "record does not exist\n")
+			})
+		})
+	})
+
+}
+
+func TestListAccountsAsTable(t *testing.T) {
+
+	Convey("Given the API returns a page of accounts", t, func() {
+		var query string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.RawQuery
+			fmt.Fprint(w, `{"data": [{"id": "1", "organisation_id": "o", "type": "accounts", "attributes": {"country": "GB", "bic": "NWBKGB42"}}], "links": {"self": ""}}`)
+		}))
+		defer server.Close()
+
+		Convey("When I list them as a table", func() {
+			var stdout, stderr bytes.Buffer
+			code := run([]string{"list", "-url", server.URL, "-page-size", "5", "-organisation-id", "o"}, &stdout, &stderr)
+
+			Convey("Then the paging and filter are sent to the API", func() {
+				So(code, ShouldEqual, exitOK)
+				So(query, ShouldContainSubstring, "page[size]=5")
+				So(query, ShouldContainSubstring, "filter[organisation_id]=o")
+			})
+
+			Convey("And a page size that is not given is left out", func() {
+				code := run([]string{"list", "-url", server.URL, "-page-number", "2"}, &stdout, &stderr)
+				So(code, ShouldEqual, exitOK)
+				So(query, ShouldEqual, "page[number]=2")
+			})
+
+			Convey("And one row is printed per account", func() {
+				lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+				So(len(lines), ShouldEqual, 2)
+				So(lines[0], ShouldStartWith, "ID")
+				So(lines[1], ShouldContainSubstring, "NWBKGB42")
+			})
+		})
+	})
+
+}
+
+func TestUpdateAccount(t *testing.T) {
+
+	Convey("Given the API accepts updates", t, func() {
+		var method, target, body string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			raw, _ := ioutil.ReadAll(r.Body)
+			method, target, body = r.Method, r.URL.Path, string(raw)
+			fmt.Fprintf(w, account, "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c")
+		}))
+		defer server.Close()
+
+		Convey("When I update the attributes of an account", func() {
+			var stdout, stderr bytes.Buffer
+			code := run([]string{"update", "-url", server.URL, "-id", "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", "-version", "2",
+				"-bank-account-name", "Samantha Holder"}, &stdout, &stderr)
+
+			Convey("Then only the changed attributes are patched with the version", func() {
+				So(code, ShouldEqual, exitOK)
+				So(method, ShouldEqual, "PATCH")
+				So(target, ShouldEqual, "/v1/organisation/accounts/ad27e265-9605-4b4b-a0e5-3003ea9cc4dc")
+				So(body, ShouldContainSubstring, `"version":2`)
+				So(body, ShouldContainSubstring, `"bank_account_name":"Samantha Holder"`)
+				So(body, ShouldNotContainSubstring, `"bank_id"`)
+				So(body, ShouldNotContainSubstring, `"country"`)
+			})
+		})
+
+		Convey("When I update an account from a JSON file holding its version", func() {
+			file := filepath.Join(os.TempDir(), uuid.New().String()+".json")
+			ioutil.WriteFile(file, []byte(`{"data": {"version": 3, "attributes": {"bank_account_name": "Samantha Holder"}}}`), 0600)
+			defer os.Remove(file)
+
+			var stdout, stderr bytes.Buffer
+			code := run([]string{"update", "-url", server.URL, "-id", "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", "-file", file}, &stdout, &stderr)
+
+			Convey("Then the version of the file is kept", func() {
+				So(code, ShouldEqual, exitOK)
+				So(body, ShouldContainSubstring, `"version":3`)
+			})
+		})
+
+		Convey("When I update an account without a valid ID", func() {
+			var stdout, stderr bytes.Buffer
+			code := run([]string{"update", "-url", server.URL, "-id", "1", "-version", "2"}, &stdout, &stderr)
+
+			Convey("Then the command fails on usage", func() {
+				So(code, ShouldEqual, exitUsage)
+				So(stderr.String(), ShouldEqual, "Invalid ID [1]\n")
+			})
+		})
+	})
+
+}
+
+func TestUnknownCommand(t *testing.T) {
+
+	Convey("When I run an unknown command", t, func() {
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"rename"}, &stdout, &stderr)
+
+		Convey("Then the usage is printed", func() {
+			So(code, ShouldEqual, exitUsage)
+			So(stderr.String(), ShouldStartWith, "Unknown command [rename]\nUsage: f3accounts <command> [flags]")
+		})
+	})
+
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+
+	accounts "github.com/razvanmuscalu/form3-accounts-client"
+)
+
+// deleted is printed once an account has been deleted
+type deleted struct {
+	ID      string `json:"id"`
+	Version int    `json:"version"`
+}
+
+type printer func(w io.Writer, value interface{}) error
+
+var printers = map[string]printer{
+	"table": printTable,
+	"json":  printJSON,
+	"yaml":  printYAML,
+}
+
+func printResult(stdout io.Writer, stderr io.Writer, format string, value interface{}) int {
+	if err := printers[format](stdout, value); err != nil {
+		fmt.Fprintf(stderr, "An error has occured while printing %s output\n", format)
+		return exitError
+	}
+	return exitOK
+}
+
+func printJSON(w io.Writer, value interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// printYAML converts the JSON encoding of value to YAML, so that the field names and their
+// order are the same in both formats
+func printYAML(w io.Writer, value interface{}) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(encoded, &node); err != nil {
+		return err
+	}
+	blockStyle(&node)
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// blockStyle drops the flow style and quoting the nodes inherited from JSON
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!str" {
+		var plain yaml.Node
+		if err := plain.Encode(node.Value); err == nil {
+			node.Style = plain.Style
+		}
+	}
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+var columns = []string{"ID", "ORGANISATION ID", "VERSION", "COUNTRY", "BANK ID", "BIC", "ACCOUNT NUMBER", "IBAN", "NAME"}
+
+func printTable(w io.Writer, value interface{}) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	switch v := value.(type) {
+	case accounts.Single:
+		writeRow(tw, columns)
+		writeRow(tw, row(v.AccountData))
+	case accounts.List:
+		writeRow(tw, columns)
+		if v.AccountData != nil {
+			for _, data := range *v.AccountData {
+				writeRow(tw, row(data))
+			}
+		}
+	case deleted:
+		writeRow(tw, []string{"ID", "VERSION", "STATUS"})
+		writeRow(tw, []string{v.ID, strconv.Itoa(v.Version), "deleted"})
+	default:
+		return fmt.Errorf("Unsupported value %T", value)
+	}
+
+	return tw.Flush()
+}
+
+func writeRow(w io.Writer, cells []string) {
+	var line bytes.Buffer
+	for i, cell := range cells {
+		if i > 0 {
+			line.WriteString("\t")
+		}
+		line.WriteString(cell)
+	}
+	line.WriteString("\n")
+	w.Write(line.Bytes())
+}
+
+func row(data accounts.AccountData) []string {
+	version := ""
+	if data.Version != nil {
+		version = strconv.Itoa(*data.Version)
+	}
+
+	return []string{
+		data.ID,
+		data.OrganisationID,
+		version,
+		data.Attributes.Country,
+		optional(data.Attributes.BankID),
+		optional(data.Attributes.BIC),
+		optional(data.Attributes.AccountNumber),
+		optional(data.Attributes.IBAN),
+		optional(data.Attributes.BankAccountName),
+	}
+}
+
+func optional(value *string) string {
+	if value == nil {
+		return "-"
+	}
+	return *value
+}
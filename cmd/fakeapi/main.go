@@ -0,0 +1,32 @@
+// Command fakeapi serves the in-memory fake Accounts API until interrupted, so that the client tests
+// can run against it, e.g. ACCOUNTS_API_URL=http://localhost:8080 go test .
+//
+// Usage:
+//
+//	fakeapi [-address :8080] [-missing-delete-status 204]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/razvanmuscalu/form3-accounts-client/fakeapi"
+)
+
+func main() {
+	address := flag.String("address", ":8080", "address to listen on")
+	missingDeleteStatus := flag.Int("missing-delete-status", http.StatusNoContent, "status answering the delete of a missing account, a success as the client tests expect")
+	flag.Parse()
+
+	api := fakeapi.NewServer().Address(*address).MissingDeleteStatus(*missingDeleteStatus).Build()
+	defer api.Close()
+	fmt.Printf("Serving the fake Accounts API on %s\n", api.URL())
+
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt, syscall.SIGTERM)
+	<-interrupted
+}
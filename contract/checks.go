@@ -0,0 +1,252 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	accounts "github.com/razvanmuscalu/form3-accounts-client"
+)
+
+func checkCreate(r *run) error {
+	data := r.newAccount()
+	result, resp, err := r.create(data)
+	if err != nil {
+		return err
+	}
+	if err := expectStatus("create", resp, http.StatusCreated); err != nil {
+		return err
+	}
+
+	switch {
+	case result.AccountData.ID != data.ID:
+		return fmt.Errorf("create answered id [%s], expected [%s]", result.AccountData.ID, data.ID)
+	case result.AccountData.OrganisationID != data.OrganisationID:
+		return fmt.Errorf("create answered organisation_id [%s], expected [%s]", result.AccountData.OrganisationID, data.OrganisationID)
+	case result.AccountData.Type != "accounts":
+		return fmt.Errorf("create answered type [%s], expected [accounts]", result.AccountData.Type)
+	case result.AccountData.Version == nil || *result.AccountData.Version != 0:
+		return fmt.Errorf("create answered no version 0")
+	case result.AccountData.Attributes.Country != "GB":
+		return fmt.Errorf("create answered country [%s], expected [GB]", result.AccountData.Attributes.Country)
+	case result.Links.Self == "":
+		return fmt.Errorf("create answered no self link")
+	}
+	return nil
+}
+
+func checkCreateInvalid(r *run) error {
+	data := r.newAccount()
+	data.ID = "not-a-uuid"
+
+	resp, err := r.do(accountsPath, "POST", accountsPath, accounts.NewAccountDataRequest().AccountData(data).Build())
+	if err != nil {
+		return err
+	}
+	if err := expectStatus("create invalid", resp, http.StatusBadRequest); err != nil {
+		return err
+	}
+	return expectErrorMessage("create invalid", resp)
+}
+
+func checkCreateDuplicate(r *run) error {
+	data, err := r.mustCreate()
+	if err != nil {
+		return err
+	}
+
+	_, resp, err := r.create(data)
+	if err != nil {
+		return err
+	}
+	if err := expectStatus("create duplicate", resp, http.StatusConflict); err != nil {
+		return err
+	}
+	return expectErrorMessage("create duplicate", resp)
+}
+
+func checkFetch(r *run) error {
+	data, err := r.mustCreate()
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.do(accountsPath+"/{id}", "GET", accountsPath+"/"+data.ID, nil)
+	if err != nil {
+		return err
+	}
+	if err := expectStatus("fetch", resp, http.StatusOK); err != nil {
+		return err
+	}
+
+	var result accounts.Single
+	if err := json.Unmarshal(resp.body, &result); err != nil {
+		return fmt.Errorf("fetch answered a body that cannot be decoded: %v", err)
+	}
+	if result.AccountData.ID != data.ID {
+		return fmt.Errorf("fetch answered id [%s], expected [%s]", result.AccountData.ID, data.ID)
+	}
+	if result.AccountData.Attributes.BankID == nil || *result.AccountData.Attributes.BankID != "400300" {
+		return fmt.Errorf("fetch answered another bank_id than the one created")
+	}
+	return nil
+}
+
+func checkFetchMissing(r *run) error {
+	resp, err := r.do(accountsPath+"/{id}", "GET", accountsPath+"/"+uuid.New().String(), nil)
+	if err != nil {
+		return err
+	}
+	if err := expectStatus("fetch missing", resp, http.StatusNotFound); err != nil {
+		return err
+	}
+	return expectErrorMessage("fetch missing", resp)
+}
+
+// list lists a page of the accounts of the organisation of the check
+func (r *run) list(number int, size int) (accounts.List, error) {
+	query := url.Values{}
+	query.Set("page[number]", strconv.Itoa(number))
+	query.Set("page[size]", strconv.Itoa(size))
+	query.Set("filter[organisation_id]", r.organisationID)
+
+	label := fmt.Sprintf("%s?page[number]=%d&page[size]=%d&filter[organisation_id]", accountsPath, number, size)
+	resp, err := r.do(label, "GET", accountsPath+"?"+query.Encode(), nil)
+	if err != nil {
+		return accounts.List{}, err
+	}
+	if err := expectStatus("list", resp, http.StatusOK); err != nil {
+		return accounts.List{}, err
+	}
+
+	var result accounts.List
+	if err := json.Unmarshal(resp.body, &result); err != nil {
+		return accounts.List{}, fmt.Errorf("list answered a body that cannot be decoded: %v", err)
+	}
+	return result, nil
+}
+
+func count(list accounts.List) int {
+	if list.AccountData == nil {
+		return 0
+	}
+	return len(*list.AccountData)
+}
+
+func checkListPaging(r *run) error {
+	for i := 0; i < 3; i++ {
+		if _, err := r.mustCreate(); err != nil {
+			return err
+		}
+	}
+
+	first, err := r.list(0, 2)
+	if err != nil {
+		return err
+	}
+	switch {
+	case count(first) != 2:
+		return fmt.Errorf("first page holds %d accounts, expected 2", count(first))
+	case first.Links.Self == "":
+		return fmt.Errorf("first page has no self link")
+	case first.Links.Next == nil || *first.Links.Next == "":
+		return fmt.Errorf("first page has no next link")
+	case first.Links.First == nil || first.Links.Last == nil:
+		return fmt.Errorf("first page has no first or last link")
+	}
+
+	second, err := r.list(1, 2)
+	if err != nil {
+		return err
+	}
+	switch {
+	case count(second) != 1:
+		return fmt.Errorf("second page holds %d accounts, expected 1", count(second))
+	case second.Links.Prev == nil || *second.Links.Prev == "":
+		return fmt.Errorf("second page has no prev link")
+	}
+	for _, ad := range *second.AccountData {
+		for _, previous := range *first.AccountData {
+			if ad.ID == previous.ID {
+				return fmt.Errorf("account [%s] is on both the first and second pages", ad.ID)
+			}
+		}
+	}
+
+	beyond, err := r.list(5, 2)
+	if err != nil {
+		return err
+	}
+	if count(beyond) != 0 {
+		return fmt.Errorf("page beyond the last holds %d accounts, expected none", count(beyond))
+	}
+	return nil
+}
+
+func checkListFilter(r *run) error {
+	data, err := r.mustCreate()
+	if err != nil {
+		return err
+	}
+
+	result, err := r.list(0, 100)
+	if err != nil {
+		return err
+	}
+	if count(result) != 1 || (*result.AccountData)[0].ID != data.ID {
+		return fmt.Errorf("listing by organisation answered %d accounts, expected only the one created", count(result))
+	}
+	return nil
+}
+
+func checkDelete(r *run) error {
+	data, err := r.mustCreate()
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.do(accountsPath+"/{id}?version=0", "DELETE", accountsPath+"/"+data.ID+"?version=0", nil)
+	if err != nil {
+		return err
+	}
+	if err := expectStatus("delete", resp, http.StatusNoContent); err != nil {
+		return err
+	}
+	r.forget(data.ID)
+
+	resp, err = r.do(accountsPath+"/{id}", "GET", accountsPath+"/"+data.ID, nil)
+	if err != nil {
+		return err
+	}
+	return expectStatus("fetch deleted", resp, http.StatusNotFound)
+}
+
+func checkDeleteVersionConflict(r *run) error {
+	data, err := r.mustCreate()
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.do(accountsPath+"/{id}?version=7", "DELETE", accountsPath+"/"+data.ID+"?version=7", nil)
+	if err != nil {
+		return err
+	}
+	if err := expectStatus("delete with another version", resp, http.StatusConflict); err != nil {
+		return err
+	}
+	return expectErrorMessage("delete with another version", resp)
+}
+
+// checkDeleteMissing expects deleting an account that does not exist to succeed, as the client tests
+// of TestDeleteNonExistentAccount assert
+func checkDeleteMissing(r *run) error {
+	resp, err := r.do(accountsPath+"/{id}?version=0", "DELETE", accountsPath+"/"+uuid.New().String()+"?version=0", nil)
+	if err != nil {
+		return err
+	}
+	return expectSuccess("delete missing", resp)
+}
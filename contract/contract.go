@@ -0,0 +1,304 @@
+// Package contract checks that an Accounts API implementation behaves the way this library expects,
+// so that the docker-compose fake, the in-memory fake and real deployments can be compared
+package contract
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	accounts "github.com/razvanmuscalu/form3-accounts-client"
+)
+
+const accountsPath = "/v1/organisation/accounts"
+
+// CheckResult is the outcome of a check along with what was observed of the API while running it
+//
+// Observations leave out the IDs generated by the check so that they can be compared between runs
+type CheckResult struct {
+	Name     string   `json:"name"`
+	Passed   bool     `json:"passed"`
+	Failure  string   `json:"failure,omitempty"`
+	Observed []string `json:"observed"`
+}
+
+// Report holds the results of running the suite against an API, in the order the checks ran
+type Report struct {
+	URL     string        `json:"url"`
+	Started time.Time     `json:"started"`
+	Checks  []CheckResult `json:"checks"`
+}
+
+// Passed tells whether every check passed
+func (r Report) Passed() bool {
+	for _, check := range r.Checks {
+		if !check.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Failed returns the checks that failed
+func (r Report) Failed() []CheckResult {
+	var failed []CheckResult
+	for _, check := range r.Checks {
+		if !check.Passed {
+			failed = append(failed, check)
+		}
+	}
+	return failed
+}
+
+// Suite runs the contract checks against an API
+type Suite interface {
+	// Run runs every check, creating accounts under a new organisation and deleting them afterwards
+	Run(ctx context.Context) Report
+}
+
+type suite struct {
+	url        string
+	httpClient http.Client
+}
+
+// SuiteBuilder is used to create a Suite
+type SuiteBuilder interface {
+	URL(string) SuiteBuilder
+	HTTPClient(http.Client) SuiteBuilder
+	Build() Suite
+}
+
+type suiteBuilder struct {
+	url        string
+	httpClient http.Client
+}
+
+// URL is the base URL of the API, without the accounts path
+func (sb *suiteBuilder) URL(value string) SuiteBuilder {
+	sb.url = value
+	return sb
+}
+
+func (sb *suiteBuilder) HTTPClient(value http.Client) SuiteBuilder {
+	sb.httpClient = value
+	return sb
+}
+
+func (sb *suiteBuilder) Build() Suite {
+	return &suite{
+		url:        strings.TrimRight(sb.url, "/"),
+		httpClient: sb.httpClient,
+	}
+}
+
+// NewSuite is used to create a SuiteBuilder
+func NewSuite() SuiteBuilder {
+	return &suiteBuilder{}
+}
+
+// check is a named contract check, failing with an error describing what the API did wrong
+type check struct {
+	name string
+	run  func(r *run) error
+}
+
+var checks = []check{
+	{name: "create account", run: checkCreate},
+	{name: "create invalid account", run: checkCreateInvalid},
+	{name: "create duplicate account", run: checkCreateDuplicate},
+	{name: "fetch account", run: checkFetch},
+	{name: "fetch missing account", run: checkFetchMissing},
+	{name: "list accounts by page", run: checkListPaging},
+	{name: "list accounts by organisation", run: checkListFilter},
+	{name: "delete account", run: checkDelete},
+	{name: "delete account with another version", run: checkDeleteVersionConflict},
+	{name: "delete missing account", run: checkDeleteMissing},
+}
+
+func (s suite) Run(ctx context.Context) Report {
+	report := Report{URL: s.url, Started: time.Now().UTC()}
+
+	for _, c := range checks {
+		r := &run{suite: s, ctx: ctx, organisationID: uuid.New().String()}
+		err := c.run(r)
+		r.cleanUp()
+
+		result := CheckResult{Name: c.name, Passed: err == nil, Observed: r.observed}
+		if err != nil {
+			result.Failure = err.Error()
+		}
+		report.Checks = append(report.Checks, result)
+	}
+
+	return report
+}
+
+// run holds the state of a single check
+type run struct {
+	suite          suite
+	ctx            context.Context
+	organisationID string
+	observed       []string
+	created        map[string]int
+}
+
+// response is what the API answered to a request
+type response struct {
+	status int
+	body   []byte
+}
+
+// do sends a request, recording what it observed under label, the request with the IDs left out
+func (r *run) do(label string, method string, target string, body interface{}) (response, error) {
+	var payload []byte
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return response{}, err
+		}
+		payload = encoded
+	}
+
+	req, err := http.NewRequestWithContext(r.ctx, method, r.suite.url+target, bytes.NewReader(payload))
+	if err != nil {
+		return response{}, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/vnd.api+json")
+	}
+
+	resp, err := r.suite.httpClient.Do(req)
+	if err != nil {
+		r.observed = append(r.observed, fmt.Sprintf("%s %s -> error", method, label))
+		return response{}, fmt.Errorf("%s %s failed: %v", method, label, err)
+	}
+	defer resp.Body.Close()
+
+	read, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return response{}, fmt.Errorf("%s %s failed reading body: %v", method, label, err)
+	}
+
+	result := response{status: resp.StatusCode, body: read}
+	r.observed = append(r.observed, fmt.Sprintf("%s %s -> %d %s", method, label, result.status, bodyShape(read)))
+	return result, nil
+}
+
+// bodyShape describes a body without its values, as empty, an error message, a single resource or a list
+func bodyShape(body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return "empty"
+	}
+
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(body, &document); err != nil {
+		return "not json"
+	}
+	if _, ok := document["error_message"]; ok {
+		return "error_message"
+	}
+	data := bytes.TrimSpace(document["data"])
+	switch {
+	case len(data) == 0:
+		return "no data"
+	case data[0] == '[':
+		var list []json.RawMessage
+		json.Unmarshal(data, &list)
+		return fmt.Sprintf("data[%d]", len(list))
+	default:
+		return "data"
+	}
+}
+
+func expectStatus(label string, got response, want int) error {
+	if got.status != want {
+		return fmt.Errorf("%s answered %d, expected %d", label, got.status, want)
+	}
+	return nil
+}
+
+// expectSuccess checks the status is a 2xx one, which the client reports as a success whatever its value
+func expectSuccess(label string, got response) error {
+	if got.status < 200 || got.status > 299 {
+		return fmt.Errorf("%s answered %d, expected a success", label, got.status)
+	}
+	return nil
+}
+
+// expectErrorMessage checks the body is an error response with a message
+func expectErrorMessage(label string, got response) error {
+	var result accounts.ErrorResponse
+	if err := json.Unmarshal(got.body, &result); err != nil || result.ErrorMessage == "" {
+		return fmt.Errorf("%s answered no error_message", label)
+	}
+	return nil
+}
+
+func (r *run) newAccount() accounts.AccountData {
+	return accounts.NewAccountData().
+		ID(uuid.New().String()).
+		OrganisationID(r.organisationID).
+		Type("accounts").
+		Attributes(accounts.NewAccount().Country("GB").BankID("400300").BankIDCode("GBDSC").BIC("NWBKGB22").Build()).
+		Build()
+}
+
+// create creates an account, keeping track of it to delete it once the check is done
+func (r *run) create(data accounts.AccountData) (accounts.Single, response, error) {
+	resp, err := r.do(accountsPath, "POST", accountsPath, accounts.NewAccountDataRequest().AccountData(data).Build())
+	if err != nil {
+		return accounts.Single{}, resp, err
+	}
+	if resp.status != http.StatusCreated {
+		return accounts.Single{}, resp, nil
+	}
+
+	var result accounts.Single
+	if err := json.Unmarshal(resp.body, &result); err != nil {
+		return accounts.Single{}, resp, fmt.Errorf("create answered a body that cannot be decoded: %v", err)
+	}
+	if r.created == nil {
+		r.created = make(map[string]int)
+	}
+	version := 0
+	if result.AccountData.Version != nil {
+		version = *result.AccountData.Version
+	}
+	r.created[data.ID] = version
+
+	return result, resp, nil
+}
+
+// mustCreate creates an account, failing when the API does not
+func (r *run) mustCreate() (accounts.AccountData, error) {
+	data := r.newAccount()
+	_, resp, err := r.create(data)
+	if err != nil {
+		return data, err
+	}
+	return data, expectStatus("create", resp, http.StatusCreated)
+}
+
+// cleanUp deletes the accounts the check created and did not delete, without observing it
+func (r *run) cleanUp() {
+	for id, version := range r.created {
+		req, err := http.NewRequestWithContext(r.ctx, "DELETE", fmt.Sprintf("%s%s/%s?version=%d", r.suite.url, accountsPath, id, version), nil)
+		if err != nil {
+			continue
+		}
+		if resp, err := r.suite.httpClient.Do(req); err == nil {
+			resp.Body.Close()
+		}
+	}
+}
+
+func (r *run) forget(id string) {
+	delete(r.created, id)
+}
@@ -0,0 +1,115 @@
+package contract
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/razvanmuscalu/form3-accounts-client/fakeapi"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestContractAgainstFakeAPI(t *testing.T) {
+
+	Convey("Given the in-memory fake API, answering the delete of a missing account as the API does", t, func() {
+		api := fakeapi.NewServer().MissingDeleteStatus(http.StatusNoContent).Build()
+		defer api.Close()
+
+		Convey("When the contract suite runs against it", func() {
+			report := NewSuite().URL(api.URL()).HTTPClient(http.Client{Timeout: 2 * time.Second}).Build().Run(context.Background())
+
+			Convey("Then every check passes", func() {
+				So(report.Failed(), ShouldBeEmpty)
+				So(report.Passed(), ShouldBeTrue)
+				So(len(report.Checks), ShouldEqual, len(checks))
+			})
+
+			Convey("And the accounts created are deleted", func() {
+				So(api.Requests(fakeapi.DeleteAccount), ShouldBeGreaterThan, 0)
+				list, err := http.Get(api.URL() + accountsPath)
+				So(err, ShouldBeNil)
+				defer list.Body.Close()
+				So(bodyShapeOf(list), ShouldEqual, "no data")
+			})
+		})
+
+		Convey("When the contract suite runs against it while it fails", func() {
+			api.Inject(fakeapi.DeleteAccount, fakeapi.ServerErrors(http.StatusInternalServerError))
+			report := NewSuite().URL(api.URL()).HTTPClient(http.Client{Timeout: 2 * time.Second}).Build().Run(context.Background())
+
+			Convey("Then the checks deleting accounts fail", func() {
+				So(report.Passed(), ShouldBeFalse)
+				var failed []string
+				for _, check := range report.Failed() {
+					failed = append(failed, check.Name)
+				}
+				So(failed, ShouldResemble, []string{"delete account", "delete account with another version", "delete missing account"})
+			})
+		})
+	})
+}
+
+// TestContractAgainstAPI compares the API at CONTRACT_API_URL, e.g. the docker-compose one or a staging
+// deployment, with the in-memory fake API
+func TestContractAgainstAPI(t *testing.T) {
+	URL := os.Getenv("CONTRACT_API_URL")
+	if URL == "" {
+		t.Skip("CONTRACT_API_URL is not set")
+	}
+
+	Convey("When the contract suite runs against the API and the in-memory fake API", t, func() {
+		api := fakeapi.NewServer().MissingDeleteStatus(http.StatusNoContent).Build()
+		defer api.Close()
+
+		httpClient := http.Client{Timeout: 5 * time.Second}
+		report := NewSuite().URL(URL).HTTPClient(httpClient).Build().Run(context.Background())
+		fake := NewSuite().URL(api.URL()).HTTPClient(httpClient).Build().Run(context.Background())
+
+		Convey("Then they behave alike", func() {
+			for _, difference := range Diff(report, fake) {
+				t.Log(difference)
+			}
+			So(report.Failed(), ShouldBeEmpty)
+		})
+	})
+}
+
+func TestDiff(t *testing.T) {
+
+	Convey("Given two reports", t, func() {
+		left := Report{Checks: []CheckResult{
+			{Name: "same", Passed: true, Observed: []string{"GET /a -> 200 data"}},
+			{Name: "observed differently", Passed: true, Observed: []string{"DELETE /a -> 404 empty"}},
+			{Name: "failed on the left", Passed: false, Failure: "boom", Observed: []string{"GET /b -> 500 error_message"}},
+			{Name: "left only", Passed: true},
+		}}
+		right := Report{Checks: []CheckResult{
+			{Name: "same", Passed: true, Observed: []string{"GET /a -> 200 data"}},
+			{Name: "observed differently", Passed: true, Observed: []string{"DELETE /a -> 404 error_message"}},
+			{Name: "failed on the left", Passed: true, Observed: []string{"GET /b -> 500 error_message"}},
+			{Name: "right only", Passed: true},
+		}}
+
+		Convey("When they are compared", func() {
+			differences := Diff(left, right)
+
+			Convey("Then the checks that did not behave alike are reported", func() {
+				var names []string
+				for _, difference := range differences {
+					names = append(names, difference.Check)
+				}
+				So(names, ShouldResemble, []string{"observed differently", "failed on the left", "left only", "right only"})
+				So(differences[1].String(), ShouldContainSubstring, "failed: boom")
+			})
+		})
+	})
+}
+
+func bodyShapeOf(resp *http.Response) string {
+	body, _ := ioutil.ReadAll(resp.Body)
+	return bodyShape(body)
+}
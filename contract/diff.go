@@ -0,0 +1,62 @@
+package contract
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Difference is a check that two APIs did not pass alike or were not observed to behave alike
+type Difference struct {
+	Check string
+	Left  CheckResult
+	Right CheckResult
+}
+
+func (d Difference) String() string {
+	describe := func(result CheckResult) string {
+		outcome := "passed"
+		if !result.Passed {
+			outcome = "failed: " + result.Failure
+		}
+		return fmt.Sprintf("%s\n    %s", outcome, strings.Join(result.Observed, "\n    "))
+	}
+
+	return fmt.Sprintf("%s\n  left %s\n  right %s", d.Check, describe(d.Left), describe(d.Right))
+}
+
+// Diff compares the reports of two APIs check by check, a check run against one API only differing too
+func Diff(left Report, right Report) []Difference {
+	rights := make(map[string]CheckResult)
+	for _, result := range right.Checks {
+		rights[result.Name] = result
+	}
+
+	var differences []Difference
+	for _, l := range left.Checks {
+		r, ok := rights[l.Name]
+		delete(rights, l.Name)
+		if ok && l.Passed == r.Passed && equal(l.Observed, r.Observed) {
+			continue
+		}
+		differences = append(differences, Difference{Check: l.Name, Left: l, Right: r})
+	}
+	for _, r := range right.Checks {
+		if _, ok := rights[r.Name]; ok {
+			differences = append(differences, Difference{Check: r.Name, Right: r})
+		}
+	}
+
+	return differences
+}
+
+func equal(left []string, right []string) bool {
+	if len(left) != len(right) {
+		return false
+	}
+	for i := range left {
+		if left[i] != right[i] {
+			return false
+		}
+	}
+	return true
+}
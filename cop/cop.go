@@ -0,0 +1,210 @@
+// Package cop evaluates the name a payer gives for an account against the names held on the account, the way
+// Confirmation of Payee does, without calling the API
+package cop
+
+import (
+	"strings"
+
+	accounts "github.com/razvanmuscalu/form3-accounts-client"
+)
+
+// Outcome is the answer to a payer asking whether the name they gave is the name of the account
+type Outcome string
+
+// the outcomes of matching a name
+const (
+	Match      Outcome = "MATCH"
+	CloseMatch Outcome = "CLOSE_MATCH"
+	NoMatch    Outcome = "NO_MATCH"
+	OptedOut   Outcome = "OPTED_OUT"
+)
+
+// Result is the outcome of matching a name against an account, along with the score it was given
+type Result struct {
+	Outcome Outcome
+	Score   float64
+	// SuggestedName is the name of the account closest to the name given, only set on a close match
+	SuggestedName string
+}
+
+// Matcher matches the names payers give against the names of accounts
+type Matcher interface {
+	// Match scores name against the bank account name and the alternative names of account, those names
+	// being normalised first, so that case, punctuation, diacritics, titles and word order do not matter and
+	// an initial matches any word starting with it
+	//
+	// A joint account also matches the name of any one of its holders, e.g. "Sam Holder" matches "S Holder
+	// & A Jones". An account opted out of matching is never matched
+	Match(name string, account accounts.Account) Result
+}
+
+type matcher struct {
+	algorithm           Algorithm
+	matchThreshold      float64
+	closeMatchThreshold float64
+}
+
+// MatcherBuilder is used to create a Matcher
+type MatcherBuilder interface {
+	Algorithm(Algorithm) MatcherBuilder
+	MatchThreshold(float64) MatcherBuilder
+	CloseMatchThreshold(float64) MatcherBuilder
+	Build() Matcher
+}
+
+type matcherBuilder struct {
+	algorithm           Algorithm
+	matchThreshold      float64
+	closeMatchThreshold float64
+}
+
+// Algorithm scores the words of the names against each other, JaroWinkler when not set
+func (mb *matcherBuilder) Algorithm(value Algorithm) MatcherBuilder {
+	mb.algorithm = value
+	return mb
+}
+
+// MatchThreshold is the lowest score of a match, 1 when not set so that only names made of the same words
+// match
+func (mb *matcherBuilder) MatchThreshold(value float64) MatcherBuilder {
+	mb.matchThreshold = value
+	return mb
+}
+
+// CloseMatchThreshold is the lowest score of a close match, 0.8 when not set; words scoring less are
+// counted as not matching at all
+func (mb *matcherBuilder) CloseMatchThreshold(value float64) MatcherBuilder {
+	mb.closeMatchThreshold = value
+	return mb
+}
+
+func (mb *matcherBuilder) Build() Matcher {
+	m := &matcher{
+		algorithm:           mb.algorithm,
+		matchThreshold:      mb.matchThreshold,
+		closeMatchThreshold: mb.closeMatchThreshold,
+	}
+	if m.algorithm == nil {
+		m.algorithm = JaroWinkler
+	}
+	if m.matchThreshold <= 0 {
+		m.matchThreshold = 1
+	}
+	if m.closeMatchThreshold <= 0 {
+		m.closeMatchThreshold = 0.8
+	}
+
+	return m
+}
+
+// NewMatcher is used to create a MatcherBuilder
+func NewMatcher() MatcherBuilder {
+	return &matcherBuilder{}
+}
+
+func (m matcher) Match(name string, account accounts.Account) Result {
+	if account.AccountMatchingOptOut != nil && *account.AccountMatchingOptOut {
+		return Result{Outcome: OptedOut}
+	}
+
+	given := words(name)
+	joint := account.JointAccount != nil && *account.JointAccount
+	best := Result{Outcome: NoMatch}
+	for _, candidate := range accountNames(account) {
+		candidateWords := words(candidate)
+		score := m.score(given, candidateWords)
+		if joint {
+			for _, holder := range holders(candidateWords) {
+				if holderScore := m.score(given, holder); holderScore > score {
+					score = holderScore
+				}
+			}
+		}
+		if score > best.Score {
+			best.Score = score
+			best.SuggestedName = candidate
+		}
+	}
+
+	switch {
+	case best.Score >= m.matchThreshold:
+		best.Outcome = Match
+		best.SuggestedName = ""
+	case best.Score >= m.closeMatchThreshold:
+		best.Outcome = CloseMatch
+	default:
+		best.SuggestedName = ""
+	}
+
+	return best
+}
+
+// accountNames returns the names of the account that are not blank, its bank account name first
+func accountNames(account accounts.Account) []string {
+	var names []string
+	if account.BankAccountName != nil && strings.TrimSpace(*account.BankAccountName) != "" {
+		names = append(names, *account.BankAccountName)
+	}
+	if account.AlternativeBankAccountNames != nil {
+		for _, name := range *account.AlternativeBankAccountNames {
+			if strings.TrimSpace(name) != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// score pairs off the words of two names, the most alike first whatever their order, and returns the sum of
+// the scores of the pairs relative to the mean number of words, so that missing words lower the score
+func (m matcher) score(given []string, candidate []string) float64 {
+	if len(given) == 0 || len(candidate) == 0 {
+		return 0
+	}
+
+	scores := make([][]float64, len(given))
+	for i, left := range given {
+		scores[i] = make([]float64, len(candidate))
+		for j, right := range candidate {
+			scores[i][j] = m.wordScore(left, right)
+		}
+	}
+
+	usedGiven := make([]bool, len(given))
+	usedCandidate := make([]bool, len(candidate))
+	total := 0.0
+	for pairs := smallest(len(given), len(candidate)); pairs > 0; pairs-- {
+		bestI, bestJ, best := -1, -1, 0.0
+		for i := range given {
+			for j := range candidate {
+				if !usedGiven[i] && !usedCandidate[j] && scores[i][j] > best {
+					bestI, bestJ, best = i, j, scores[i][j]
+				}
+			}
+		}
+		if bestI < 0 {
+			break
+		}
+		usedGiven[bestI], usedCandidate[bestJ] = true, true
+		total += best
+	}
+
+	return total / (float64(len(given)+len(candidate)) / 2)
+}
+
+// wordScore scores two words, an initial fully matching the words starting with it, and words less alike
+// than a close match not at all
+func (m matcher) wordScore(left string, right string) float64 {
+	if left == right {
+		return 1
+	}
+	if (len(left) == 1 || len(right) == 1) && left[0] == right[0] {
+		return 1
+	}
+
+	score := m.algorithm(left, right)
+	if score < m.closeMatchThreshold {
+		return 0
+	}
+	return score
+}
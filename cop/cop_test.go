@@ -0,0 +1,155 @@
+package cop
+
+import (
+	"testing"
+
+	accounts "github.com/razvanmuscalu/form3-accounts-client"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func newAccount(name string, alternatives ...string) accounts.Account {
+	builder := accounts.NewAccount().Country("GB").BankAccountName(name)
+	if len(alternatives) > 0 {
+		builder.AlternativeBankAccountNames(alternatives)
+	}
+	return builder.Build()
+}
+
+func TestNormalise(t *testing.T) {
+
+	Convey("Given names written in different ways", t, func() {
+
+		Convey("Then case, punctuation, titles and diacritics are normalised", func() {
+			So(Normalise("Mr. J. O'Brien-Müller"), ShouldEqual, "j obrien muller")
+			So(Normalise("  DR  Zoë   Łukasiewicz "), ShouldEqual, "zoe lukasiewicz")
+			So(Normalise("Smith & Sons Ltd."), ShouldEqual, "smith and sons ltd")
+			So(Normalise("Ærøskøbing Straße"), ShouldEqual, "aeroskobing strasse")
+		})
+
+		Convey("And a name made of titles only is kept", func() {
+			So(Normalise("Lord"), ShouldEqual, "lord")
+		})
+	})
+}
+
+func TestSimilarity(t *testing.T) {
+
+	Convey("Given the fuzzy algorithms", t, func() {
+
+		Convey("Then the same words score 1 and words without letters in common score 0", func() {
+			for _, algorithm := range []Algorithm{Levenshtein, JaroWinkler} {
+				So(algorithm("holder", "holder"), ShouldEqual, 1)
+				So(algorithm("abc", "xyz"), ShouldEqual, 0)
+				So(algorithm("", ""), ShouldEqual, 1)
+			}
+		})
+
+		Convey("And typos score in between", func() {
+			So(Levenshtein("kitten", "sitting"), ShouldAlmostEqual, 1-3.0/7, 0.0001)
+			So(JaroWinkler("martha", "marhta"), ShouldAlmostEqual, 0.9611, 0.0001)
+		})
+
+		Convey("And an odd number of transpositions counts as half transpositions", func() {
+			So(JaroWinkler("abcdef", "bcadef"), ShouldAlmostEqual, (1+1+(6-1.5)/6)/3, 0.0001)
+		})
+	})
+}
+
+func TestMatch(t *testing.T) {
+
+	Convey("Given a matcher with the default settings", t, func() {
+		matcher := NewMatcher().Build()
+
+		Convey("When the name given is the name of the account written differently", func() {
+			result := matcher.Match("mr samuel HOLDER", newAccount("Samuel Holder"))
+
+			Convey("Then it is a match", func() {
+				So(result.Outcome, ShouldEqual, Match)
+				So(result.Score, ShouldEqual, 1)
+				So(result.SuggestedName, ShouldEqual, "")
+			})
+		})
+
+		Convey("When the name given has its words in another order or initials", func() {
+			So(matcher.Match("Holder, Samuel", newAccount("Samuel Holder")).Outcome, ShouldEqual, Match)
+			So(matcher.Match("S. Holder", newAccount("Samuel Holder")).Outcome, ShouldEqual, Match)
+		})
+
+		Convey("When the name given has a typo", func() {
+			result := matcher.Match("Samuel Holdre", newAccount("Samuel Holder"))
+
+			Convey("Then it is a close match suggesting the name of the account", func() {
+				So(result.Outcome, ShouldEqual, CloseMatch)
+				So(result.SuggestedName, ShouldEqual, "Samuel Holder")
+			})
+		})
+
+		Convey("When the name given misses a middle name", func() {
+			result := matcher.Match("Samuel Holder", newAccount("Samuel James Holder"))
+
+			Convey("Then it is a close match", func() {
+				So(result.Outcome, ShouldEqual, CloseMatch)
+			})
+		})
+
+		Convey("When the name given only shares the surname", func() {
+			result := matcher.Match("Jane Holder", newAccount("Samuel Holder"))
+
+			Convey("Then it is no match, without suggesting a name", func() {
+				So(result.Outcome, ShouldEqual, NoMatch)
+				So(result.SuggestedName, ShouldEqual, "")
+			})
+		})
+
+		Convey("When the name given is an alternative name of the account", func() {
+			result := matcher.Match("Holder Trading", newAccount("Samuel Holder", "Holder Trading Ltd", "Holder Tradng"))
+
+			Convey("Then the closest alternative name is suggested", func() {
+				So(result.Outcome, ShouldEqual, CloseMatch)
+				So(result.SuggestedName, ShouldEqual, "Holder Tradng")
+			})
+		})
+
+		Convey("When the name given is one of the holders of a joint account", func() {
+			account := newAccount("S Holder & A Jones")
+
+			Convey("Then it is a match only when the account is joint", func() {
+				So(matcher.Match("Samuel Holder", account).Outcome, ShouldEqual, NoMatch)
+
+				joint := true
+				account.JointAccount = &joint
+				So(matcher.Match("Samuel Holder", account).Outcome, ShouldEqual, Match)
+				So(matcher.Match("Alex Jones", account).Outcome, ShouldEqual, Match)
+				So(matcher.Match("A. Jones & Sam Holder", account).Outcome, ShouldEqual, Match)
+			})
+		})
+
+		Convey("When the account is opted out of matching", func() {
+			account := newAccount("Samuel Holder")
+			optOut := true
+			account.AccountMatchingOptOut = &optOut
+
+			Convey("Then the name is not matched", func() {
+				So(matcher.Match("Samuel Holder", account), ShouldResemble, Result{Outcome: OptedOut})
+			})
+		})
+
+		Convey("When the account has no name", func() {
+			result := matcher.Match("Samuel Holder", accounts.NewAccount().Country("GB").Build())
+
+			Convey("Then it is no match", func() {
+				So(result.Outcome, ShouldEqual, NoMatch)
+			})
+		})
+	})
+
+	Convey("Given a matcher scoring words with Levenshtein and looser thresholds", t, func() {
+		matcher := NewMatcher().Algorithm(Levenshtein).MatchThreshold(0.9).CloseMatchThreshold(0.6).Build()
+
+		Convey("Then a name close enough is a match", func() {
+			So(matcher.Match("Samuel Holdr", newAccount("Samuel Holder")).Outcome, ShouldEqual, Match)
+			So(matcher.Match("Samual Holdar", newAccount("Samuel Holder")).Outcome, ShouldEqual, CloseMatch)
+		})
+	})
+}
@@ -0,0 +1,88 @@
+package cop
+
+import (
+	"strings"
+	"unicode"
+)
+
+// folds spells the letters with diacritics, and the ligatures, of the Latin alphabets in plain ASCII
+var folds = func() map[rune]string {
+	groups := map[string]string{
+		"àáâãäåāăą": "a", "æ": "ae", "çćĉċč": "c", "ďđð": "d", "èéêëēĕėęě": "e", "ĝğġģ": "g", "ĥħ": "h",
+		"ìíîïĩīĭįı": "i", "ĳ": "ij", "ĵ": "j", "ķ": "k", "ĺļľŀł": "l", "ñńņňŉ": "n", "òóôõöøōŏő": "o",
+		"œ": "oe", "ŕŗř": "r", "śŝşšș": "s", "ß": "ss", "ţťŧț": "t", "þ": "th", "ùúûüũūŭůűų": "u",
+		"ŵ": "w", "ýÿŷ": "y", "źżž": "z",
+	}
+	folds := make(map[rune]string)
+	for letters, plain := range groups {
+		for _, letter := range letters {
+			folds[letter] = plain
+		}
+	}
+	return folds
+}()
+
+// titles are left out of names, unless a name is nothing but titles
+var titles = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "miss": true, "mx": true, "master": true, "dr": true, "prof": true,
+	"sir": true, "dame": true, "lord": true, "lady": true, "rev": true, "revd": true,
+}
+
+// Normalise returns name in lower case, its diacritics and punctuation removed and its titles left out,
+// e.g. "Mr. J. O'Brien-Müller" becomes "j obrien muller"
+//
+// Initials are kept as single letter words, "&" is spelled "and"
+func Normalise(name string) string {
+	return strings.Join(words(name), " ")
+}
+
+// words returns the words of the normalised name
+func words(name string) []string {
+	var folded strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r == '\'' || r == '’' || r == '`':
+		case r == '&':
+			folded.WriteString(" and ")
+		case folds[r] != "":
+			folded.WriteString(folds[r])
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			folded.WriteRune(r)
+		default:
+			folded.WriteRune(' ')
+		}
+	}
+
+	all := strings.Fields(folded.String())
+	var kept []string
+	for _, word := range all {
+		if !titles[word] {
+			kept = append(kept, word)
+		}
+	}
+	if len(kept) == 0 {
+		return all
+	}
+	return kept
+}
+
+// holders splits the words of the name of a joint account into the names of its holders, e.g. "j smith and
+// a jones" into "j smith" and "a jones"
+func holders(words []string) [][]string {
+	var names [][]string
+	var current []string
+	for _, word := range words {
+		if word == "and" {
+			if len(current) > 0 {
+				names = append(names, current)
+			}
+			current = nil
+			continue
+		}
+		current = append(current, word)
+	}
+	if len(current) > 0 {
+		names = append(names, current)
+	}
+	return names
+}
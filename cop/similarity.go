@@ -0,0 +1,112 @@
+package cop
+
+// Algorithm scores how similar two normalised words or names are, from 0 when nothing is alike to 1 when
+// they are the same
+type Algorithm func(a string, b string) float64
+
+// Levenshtein scores a and b by the number of letters to insert, delete or substitute to turn one into the
+// other, relative to the length of the longest
+func Levenshtein(a string, b string) float64 {
+	left, right := []rune(a), []rune(b)
+	longest := len(left)
+	if len(right) > longest {
+		longest = len(right)
+	}
+	if longest == 0 {
+		return 1
+	}
+
+	previous := make([]int, len(right)+1)
+	current := make([]int, len(right)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(left); i++ {
+		current[0] = i
+		for j := 1; j <= len(right); j++ {
+			cost := 1
+			if left[i-1] == right[j-1] {
+				cost = 0
+			}
+			current[j] = smallest(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return 1 - float64(previous[len(right)])/float64(longest)
+}
+
+// JaroWinkler scores a and b by the letters they have in common near the same place, favouring those that
+// start alike, which suits names where typos are more likely towards the end
+func JaroWinkler(a string, b string) float64 {
+	left, right := []rune(a), []rune(b)
+	if len(left) == 0 && len(right) == 0 {
+		return 1
+	}
+	if len(left) == 0 || len(right) == 0 {
+		return 0
+	}
+
+	window := largest(len(left), len(right))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+	leftMatched := make([]bool, len(left))
+	rightMatched := make([]bool, len(right))
+	matches := 0
+	for i := range left {
+		for j := largest(0, i-window); j < smallest(len(right), i+window+1); j++ {
+			if !rightMatched[j] && left[i] == right[j] {
+				leftMatched[i], rightMatched[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions := 0
+	j := 0
+	for i := range left {
+		if !leftMatched[i] {
+			continue
+		}
+		for !rightMatched[j] {
+			j++
+		}
+		if left[i] != right[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(left)) + m/float64(len(right)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for prefix < smallest(4, len(left), len(right)) && left[prefix] == right[prefix] {
+		prefix++
+	}
+
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+func smallest(first int, others ...int) int {
+	for _, value := range others {
+		if value < first {
+			first = value
+		}
+	}
+	return first
+}
+
+func largest(first int, others ...int) int {
+	for _, value := range others {
+		if value > first {
+			first = value
+		}
+	}
+	return first
+}
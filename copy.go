@@ -0,0 +1,64 @@
+package accounts
+
+import "reflect"
+
+// deepCopy copies a value along with what its pointers, slices, maps and interfaces refer to, so that callers
+// given the same cached or shared result cannot change it for one another
+//
+// Unexported fields are copied shallowly, this package only reading what they refer to
+func deepCopy[T any](value T) T {
+	source := reflect.ValueOf(&value).Elem()
+	copied := reflect.New(source.Type()).Elem()
+	copyValue(copied, source)
+	return copied.Interface().(T)
+}
+
+func copyValue(dst reflect.Value, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+		dst.Set(reflect.New(src.Type().Elem()))
+		copyValue(dst.Elem(), src.Elem())
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		value := reflect.New(src.Elem().Type()).Elem()
+		copyValue(value, src.Elem())
+		dst.Set(value)
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		dst.Set(reflect.MakeSlice(src.Type(), src.Len(), src.Len()))
+		for i := 0; i < src.Len(); i++ {
+			copyValue(dst.Index(i), src.Index(i))
+		}
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			copyValue(dst.Index(i), src.Index(i))
+		}
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		dst.Set(reflect.MakeMapWithSize(src.Type(), src.Len()))
+		entries := src.MapRange()
+		for entries.Next() {
+			value := reflect.New(src.Type().Elem()).Elem()
+			copyValue(value, entries.Value())
+			dst.SetMapIndex(entries.Key(), value)
+		}
+	case reflect.Struct:
+		dst.Set(src)
+		for i := 0; i < src.NumField(); i++ {
+			if dst.Field(i).CanSet() {
+				copyValue(dst.Field(i), src.Field(i))
+			}
+		}
+	default:
+		dst.Set(src)
+	}
+}
@@ -0,0 +1,58 @@
+package accounts
+
+// ValidationError is returned when a request is rejected before being sent to the API
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// APIError is returned when the API answers with an error status code
+//
+// Message is the error message of the API, or describes the status when the body holds none, e.g. an HTML
+// page from a proxy; Body holds the start of the body as received
+type APIError struct {
+	StatusCode int
+	Message    string
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// RequestError is returned when a request cannot be built or sent to the API
+type RequestError struct {
+	Message string
+	Err     error
+}
+
+func (e *RequestError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the underlying error
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// ResponseError is returned when a response from the API cannot be decoded
+//
+// Body holds the start of the body as received, when it could be read
+type ResponseError struct {
+	Message string
+	Err     error
+	Body    string
+}
+
+func (e *ResponseError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the underlying error
+func (e *ResponseError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,453 @@
+package accounts
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// PIIFields are the account attributes holding personal data, masked by default by an Exporter
+var PIIFields = []string{
+	"account_number",
+	"iban",
+	"title",
+	"first_name",
+	"bank_account_name",
+	"alternative_bank_account_names",
+	"secondary_identification",
+}
+
+// exportColumn is a column of an export along with the way to read it from account data
+type exportColumn struct {
+	name  string
+	value func(ad AccountData) interface{}
+}
+
+// exportColumns lists every column that can be exported, the account data fields followed by
+// the account attributes in the order they are declared
+var exportColumns = func() []exportColumn {
+	columns := []exportColumn{
+		{name: "id", value: func(ad AccountData) interface{} { return ad.ID }},
+		{name: "organisation_id", value: func(ad AccountData) interface{} { return ad.OrganisationID }},
+		{name: "type", value: func(ad AccountData) interface{} { return ad.Type }},
+		{name: "version", value: func(ad AccountData) interface{} { return exportValue(reflect.ValueOf(ad.Version)) }},
+		{name: "created_on", value: func(ad AccountData) interface{} { return exportTime(ad.CreatedOn) }},
+		{name: "modified_on", value: func(ad AccountData) interface{} { return exportTime(ad.ModifiedOn) }},
+	}
+
+	t := reflect.TypeOf(Account{})
+	for f := 0; f < t.NumField(); f++ {
+		name := strings.Split(t.Field(f).Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		index := f
+		columns = append(columns, exportColumn{name: name, value: func(ad AccountData) interface{} {
+			return exportValue(reflect.ValueOf(ad.Attributes).Field(index))
+		}})
+	}
+
+	return columns
+}()
+
+func exportTime(value *time.Time) interface{} {
+	if value == nil {
+		return nil
+	}
+	return value.Format(time.RFC3339Nano)
+}
+
+// exportValue returns the value of a field as it is typed, e.g. a bool or a []string, nil when not set
+func exportValue(value reflect.Value) interface{} {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() == reflect.Slice && value.IsNil() {
+		return nil
+	}
+
+	return value.Interface()
+}
+
+// exportString formats a value the way the importer reads it back, lists being separated by semicolons
+func exportString(value interface{}) *string {
+	var formatted string
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		formatted = v
+	case []string:
+		formatted = strings.Join(v, ";")
+	default:
+		formatted = fmt.Sprint(v)
+	}
+
+	return &formatted
+}
+
+// partiallyMasked are the columns whose last four characters are left visible when masked, so that
+// accounts can still be told apart as on a bank statement
+var partiallyMasked = map[string]bool{"account_number": true, "iban": true}
+
+// mask hides a value, leaving the last four characters of long enough account identifiers visible
+func mask(column string, value string) string {
+	runes := []rune(value)
+	visible := 4
+	if !partiallyMasked[column] || len(runes) <= 2*visible {
+		visible = 0
+	}
+	return strings.Repeat("*", len(runes)-visible) + string(runes[len(runes)-visible:])
+}
+
+// maskValue masks a value as typed, every name of a list being masked on its own
+func maskValue(column string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return mask(column, v)
+	case []string:
+		masked := make([]string, len(v))
+		for i, name := range v {
+			masked[i] = mask(column, name)
+		}
+		return masked
+	}
+
+	return value
+}
+
+// ExportManifest describes an export, it is written as JSON once the export is complete
+type ExportManifest struct {
+	OrganisationID string    `json:"organisation_id"`
+	Format         string    `json:"format"`
+	SnapshotTime   time.Time `json:"snapshot_time"`
+	Pages          int       `json:"pages"`
+	Rows           int       `json:"rows"`
+	Columns        []string  `json:"columns"`
+	Masked         []string  `json:"masked,omitempty"`
+}
+
+// Exporter writes a snapshot of all the accounts of an organisation
+type Exporter interface {
+	// Export pages through the accounts, writes them to output and the manifest describing them to manifest
+	Export(output io.Writer, manifest io.Writer) (ExportManifest, error)
+}
+
+type exporter struct {
+	client         ContextClient
+	organisationID string
+	format         FileFormat
+	pageSize       int
+	fields         []string
+	masked         []string
+}
+
+// ExporterBuilder is used to create an Exporter
+type ExporterBuilder interface {
+	Client(ContextClient) ExporterBuilder
+	OrganisationID(string) ExporterBuilder
+	Format(FileFormat) ExporterBuilder
+	PageSize(int) ExporterBuilder
+	Fields([]string) ExporterBuilder
+	Mask([]string) ExporterBuilder
+	Build() Exporter
+}
+
+type exporterBuilder struct {
+	client         ContextClient
+	organisationID string
+	format         FileFormat
+	pageSize       int
+	fields         []string
+	masked         []string
+}
+
+func (eb *exporterBuilder) Client(value ContextClient) ExporterBuilder {
+	eb.client = value
+	return eb
+}
+
+func (eb *exporterBuilder) OrganisationID(value string) ExporterBuilder {
+	eb.organisationID = value
+	return eb
+}
+
+func (eb *exporterBuilder) Format(value FileFormat) ExporterBuilder {
+	eb.format = value
+	return eb
+}
+
+// PageSize is the number of accounts requested per page, 100 when not set
+func (eb *exporterBuilder) PageSize(value int) ExporterBuilder {
+	eb.pageSize = value
+	return eb
+}
+
+// Fields selects the columns to export, named as in the API; columns keep their stable order
+// whatever the order of the fields. All columns are exported when not set
+func (eb *exporterBuilder) Fields(value []string) ExporterBuilder {
+	eb.fields = value
+	return eb
+}
+
+// Mask selects the columns whose values are masked, PIIFields when not set; pass an empty
+// slice to export the values as they are
+func (eb *exporterBuilder) Mask(value []string) ExporterBuilder {
+	eb.masked = value
+	return eb
+}
+
+func (eb *exporterBuilder) Build() Exporter {
+	pageSize := eb.pageSize
+	if pageSize < 1 {
+		pageSize = 100
+	}
+	masked := eb.masked
+	if masked == nil {
+		masked = PIIFields
+	}
+
+	return &exporter{
+		client:         eb.client,
+		organisationID: eb.organisationID,
+		format:         eb.format,
+		pageSize:       pageSize,
+		fields:         eb.fields,
+		masked:         masked,
+	}
+}
+
+// NewExporter is used to create an ExporterBuilder
+func NewExporter() ExporterBuilder {
+	return &exporterBuilder{}
+}
+
+// columns returns the selected columns in their stable order, failing on unknown field names
+func (e exporter) columns() ([]exportColumn, error) {
+	if e.fields == nil {
+		return exportColumns, nil
+	}
+
+	selected := make(map[string]bool)
+	for _, field := range e.fields {
+		selected[field] = true
+	}
+
+	var columns []exportColumn
+	for _, column := range exportColumns {
+		if selected[column.name] {
+			columns = append(columns, column)
+			delete(selected, column.name)
+		}
+	}
+	for _, field := range e.fields {
+		if selected[field] {
+			return nil, fmt.Errorf("Invalid export field [%s]", field)
+		}
+	}
+
+	return columns, nil
+}
+
+func (e exporter) Export(output io.Writer, manifest io.Writer) (ExportManifest, error) {
+	columns, err := e.columns()
+	if err != nil {
+		return ExportManifest{}, err
+	}
+
+	result := ExportManifest{
+		OrganisationID: e.organisationID,
+		Format:         e.format.String(),
+		SnapshotTime:   time.Now().UTC(),
+	}
+	masked := make(map[string]bool)
+	for _, field := range e.masked {
+		masked[field] = true
+	}
+	for _, column := range columns {
+		result.Columns = append(result.Columns, column.name)
+		if masked[column.name] {
+			result.Masked = append(result.Masked, column.name)
+		}
+	}
+
+	writer, err := newExportWriter(e.format, output, result.Columns, masked)
+	if err != nil {
+		return result, err
+	}
+
+	filter := &Filter{OrganisationID: &e.organisationID}
+	result.Pages, err = eachPage(e.client, filter, e.pageSize, func(data []AccountData) error {
+		var rows [][]interface{}
+		for _, ad := range data {
+			row := make([]interface{}, len(columns))
+			for c, column := range columns {
+				row[c] = column.value(ad)
+			}
+			rows = append(rows, row)
+		}
+		if err := writer.write(rows); err != nil {
+			return fmt.Errorf("An error has occured while writing export")
+		}
+		result.Rows += len(rows)
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	if err := writer.close(); err != nil {
+		return result, fmt.Errorf("An error has occured while writing export")
+	}
+	if manifest != nil {
+		encoder := json.NewEncoder(manifest)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(result); err != nil {
+			return result, fmt.Errorf("An error has occured while writing export manifest")
+		}
+	}
+
+	return result, nil
+}
+
+// exportWriter writes the rows of an export page by page, every row holding the typed value of each
+// column with nil for null
+type exportWriter interface {
+	write(rows [][]interface{}) error
+	close() error
+}
+
+func newExportWriter(format FileFormat, w io.Writer, columns []string, masked map[string]bool) (exportWriter, error) {
+	switch format {
+	case CSV:
+		return newCSVExportWriter(w, columns, masked)
+	case JSONLines:
+		return &jsonLinesExportWriter{w: w, columns: columns, masked: masked}, nil
+	case Parquet:
+		pw, err := newParquetWriter(w, columns)
+		if err != nil {
+			return nil, fmt.Errorf("An error has occured while writing export")
+		}
+		return &parquetExportWriter{pw: pw, columns: columns, masked: masked}, nil
+	}
+
+	return nil, fmt.Errorf("Invalid export format [%s]", format)
+}
+
+// exportStrings formats the values of a row as strings, masking the formatted values of the masked columns
+func exportStrings(columns []string, masked map[string]bool, row []interface{}) []*string {
+	values := make([]*string, len(row))
+	for c, value := range row {
+		values[c] = exportString(value)
+		if values[c] != nil && masked[columns[c]] {
+			formatted := mask(columns[c], *values[c])
+			values[c] = &formatted
+		}
+	}
+	return values
+}
+
+type csvExportWriter struct {
+	w       *csv.Writer
+	columns []string
+	masked  map[string]bool
+}
+
+func newCSVExportWriter(w io.Writer, columns []string, masked map[string]bool) (exportWriter, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return nil, fmt.Errorf("An error has occured while writing export")
+	}
+	return &csvExportWriter{w: cw, columns: columns, masked: masked}, nil
+}
+
+func (cw *csvExportWriter) write(rows [][]interface{}) error {
+	for _, row := range rows {
+		record := make([]string, len(row))
+		for c, value := range exportStrings(cw.columns, cw.masked, row) {
+			if value != nil {
+				record[c] = *value
+			}
+		}
+		if err := cw.w.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+func (cw *csvExportWriter) close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// jsonLinesExportWriter writes every row as a flat JSON object, keeping the column order, the type
+// of the values and leaving out nulls
+type jsonLinesExportWriter struct {
+	w       io.Writer
+	columns []string
+	masked  map[string]bool
+}
+
+func (jw *jsonLinesExportWriter) write(rows [][]interface{}) error {
+	var line bytes.Buffer
+	for _, row := range rows {
+		line.Reset()
+		line.WriteByte('{')
+		for c, value := range row {
+			if value == nil {
+				continue
+			}
+			if jw.masked[jw.columns[c]] {
+				value = maskValue(jw.columns[c], value)
+			}
+			if line.Len() > 1 {
+				line.WriteByte(',')
+			}
+			name, _ := json.Marshal(jw.columns[c])
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			line.Write(name)
+			line.WriteByte(':')
+			line.Write(encoded)
+		}
+		line.WriteString("}\n")
+		if _, err := jw.w.Write(line.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (jw *jsonLinesExportWriter) close() error {
+	return nil
+}
+
+type parquetExportWriter struct {
+	pw      *parquetWriter
+	columns []string
+	masked  map[string]bool
+}
+
+func (pw *parquetExportWriter) write(rows [][]interface{}) error {
+	var values [][]*string
+	for _, row := range rows {
+		values = append(values, exportStrings(pw.columns, pw.masked, row))
+	}
+	return pw.pw.writeRowGroup(values)
+}
+
+func (pw *parquetExportWriter) close() error {
+	return pw.pw.close()
+}
@@ -0,0 +1,303 @@
+package accounts
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// exportServer serves 5 accounts in pages, linking to the next page until the last one
+func exportServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		number, _ := strconv.Atoi(r.URL.Query().Get("page[number]"))
+		size, _ := strconv.Atoi(r.URL.Query().Get("page[size]"))
+
+		var data []AccountData
+		for i := number * size; i < (number+1)*size && i < 5; i++ {
+			data = append(data, NewAccountData().
+				ID(strconv.Itoa(i)).
+				OrganisationID(r.URL.Query().Get("filter[organisation_id]")).
+				Type(Type).
+				Version(0).
+				Attributes(NewAccount().Country("GB").IBAN(fmt.Sprintf("GB28NWBK4003021276420%d", i)).AlternativeBankAccountNames([]string{"Jane", "Janet"}).JointAccount(i%2 == 0).Build()).
+				Build())
+		}
+
+		list := List{AccountData: &data}
+		if len(data) == size {
+			next := fmt.Sprintf("/v1/organisation/accounts?page[number]=%d", number+1)
+			list.Links.Next = &next
+		}
+		json.NewEncoder(w).Encode(list)
+	}))
+}
+
+func TestExportAccountsToCSV(t *testing.T) {
+
+	Convey("Given an organisation with 5 accounts", t, func() {
+		server := exportServer()
+		defer server.Close()
+
+		Exporter := NewExporter().
+			Client(NewClient().HTTPClient(HTTPClient).URL(server.URL).Build()).
+			OrganisationID(OrganisationID).
+			Format(CSV).
+			PageSize(2).
+			Fields([]string{"iban", "id", "alternative_bank_account_names"}).
+			Build()
+
+		Convey("When I export its accounts to CSV with 2 accounts per page", func() {
+			var output, manifest bytes.Buffer
+			result, err := Exporter.Export(&output, &manifest)
+
+			Convey("Then every account is written with the selected columns in their stable order", func() {
+				So(err, ShouldBeNil)
+				lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+				So(len(lines), ShouldEqual, 6)
+				So(lines[0], ShouldEqual, "id,iban,alternative_bank_account_names")
+			})
+
+			Convey("And the personal data is masked", func() {
+				lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+				So(lines[1], ShouldEqual, "0,******************4200,**********")
+			})
+
+			Convey("And the manifest records the pages and rows", func() {
+				So(result.Pages, ShouldEqual, 3)
+				So(result.Rows, ShouldEqual, 5)
+				So(result.Masked, ShouldResemble, []string{"iban", "alternative_bank_account_names"})
+
+				var written ExportManifest
+				json.Unmarshal(manifest.Bytes(), &written)
+				So(written.SnapshotTime.Equal(result.SnapshotTime), ShouldBeTrue)
+				So(written.Format, ShouldEqual, "csv")
+			})
+
+		})
+
+	})
+
+}
+
+func TestExportAccountsToJSONLinesWithoutMasking(t *testing.T) {
+
+	Convey("Given an organisation with 5 accounts", t, func() {
+		server := exportServer()
+		defer server.Close()
+
+		Exporter := NewExporter().
+			Client(NewClient().HTTPClient(HTTPClient).URL(server.URL).Build()).
+			OrganisationID(OrganisationID).
+			Format(JSONLines).
+			Fields([]string{"id", "version", "iban", "bic", "alternative_bank_account_names", "joint_account"}).
+			Mask([]string{}).
+			Build()
+
+		Convey("When I export its accounts to JSON Lines", func() {
+			var output bytes.Buffer
+			result, err := Exporter.Export(&output, nil)
+
+			Convey("Then every account is written as a flat object of typed values without the null columns", func() {
+				So(err, ShouldBeNil)
+				So(result.Pages, ShouldEqual, 1)
+				lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+				So(len(lines), ShouldEqual, 5)
+				So(lines[0], ShouldEqual, `{"id":"0","version":0,"iban":"GB28NWBK40030212764200","alternative_bank_account_names":["Jane","Janet"],"joint_account":true}`)
+				So(lines[1], ShouldEqual, `{"id":"1","version":0,"iban":"GB28NWBK40030212764201","alternative_bank_account_names":["Jane","Janet"],"joint_account":false}`)
+			})
+
+		})
+
+	})
+
+}
+
+func TestExportAccountsToParquet(t *testing.T) {
+
+	Convey("Given an organisation with 5 accounts", t, func() {
+		server := exportServer()
+		defer server.Close()
+
+		Exporter := NewExporter().
+			Client(NewClient().HTTPClient(HTTPClient).URL(server.URL).Build()).
+			OrganisationID(OrganisationID).
+			Format(Parquet).
+			Fields([]string{"id", "bic", "iban", "joint_account"}).
+			PageSize(3).
+			Build()
+
+		Convey("When I export its accounts to Parquet", func() {
+			var output bytes.Buffer
+			result, err := Exporter.Export(&output, nil)
+
+			Convey("Then a Parquet file is written with one row group per page", func() {
+				So(err, ShouldBeNil)
+				So(result.Rows, ShouldEqual, 5)
+				So(result.Pages, ShouldEqual, 2)
+				So(output.String(), ShouldStartWith, "PAR1")
+				So(output.String(), ShouldEndWith, "PAR1")
+
+				meta := parquetFooter(output.Bytes())
+				So(meta[3], ShouldEqual, 5)
+				So(len(meta[4].([]interface{})), ShouldEqual, 2)
+			})
+
+			Convey("And the schema holds every column", func() {
+				var names []string
+				for _, element := range parquetFooter(output.Bytes())[2].([]interface{})[1:] {
+					names = append(names, element.(map[int16]interface{})[4].(string))
+				}
+				So(names, ShouldResemble, []string{"id", "bic", "iban", "joint_account"})
+			})
+
+			Convey("And the column chunks can be read back", func() {
+				So(parquetColumn(output.Bytes(), 0, 0), ShouldResemble, []*string{strPtr("0"), strPtr("1"), strPtr("2")})
+				So(parquetColumn(output.Bytes(), 1, 1), ShouldResemble, []*string{nil, nil})
+				So(parquetColumn(output.Bytes(), 1, 2), ShouldResemble, []*string{strPtr("******************4203"), strPtr("******************4204")})
+				So(parquetColumn(output.Bytes(), 1, 3), ShouldResemble, []*string{strPtr("false"), strPtr("true")})
+			})
+
+		})
+
+		Convey("When I export its accounts to Parquet in pages of 5", func() {
+			var output bytes.Buffer
+			result, err := NewExporter().
+				Client(NewClient().HTTPClient(HTTPClient).URL(server.URL).Build()).
+				OrganisationID(OrganisationID).
+				Format(Parquet).
+				PageSize(5).
+				Build().
+				Export(&output, nil)
+
+			Convey("Then the empty page following the last full one is not counted", func() {
+				So(err, ShouldBeNil)
+				So(result.Pages, ShouldEqual, 1)
+				So(len(parquetFooter(output.Bytes())[4].([]interface{})), ShouldEqual, 1)
+			})
+
+		})
+
+	})
+
+}
+
+func strPtr(value string) *string {
+	return &value
+}
+
+// parquetFooter decodes the file metadata of a Parquet file, as Thrift fields by ID
+func parquetFooter(file []byte) map[int16]interface{} {
+	size := binary.LittleEndian.Uint32(file[len(file)-8:])
+	reader := thriftReader{bytes.NewReader(file[len(file)-8-int(size) : len(file)-8])}
+	return reader.readStruct()
+}
+
+// parquetColumn reads back the values of a column chunk of a row group, nil for null
+func parquetColumn(file []byte, group int, column int) []*string {
+	groups := parquetFooter(file)[4].([]interface{})
+	chunks := groups[group].(map[int16]interface{})[1].([]interface{})
+	offset := chunks[column].(map[int16]interface{})[3].(map[int16]interface{})[9].(int64)
+
+	reader := thriftReader{bytes.NewReader(file[offset:])}
+	header := reader.readStruct()
+	count := int(header[5].(map[int16]interface{})[1].(int64))
+	page := make([]byte, header[3].(int64))
+	io.ReadFull(reader.r, page)
+
+	var levelsSize uint32
+	pageReader := bytes.NewReader(page)
+	binary.Read(pageReader, binary.LittleEndian, &levelsSize)
+	levels := make([]byte, levelsSize)
+	io.ReadFull(pageReader, levels)
+	levelsReader := bytes.NewReader(levels)
+
+	var values []*string
+	for len(values) < count {
+		run, _ := binary.ReadUvarint(levelsReader)
+		level, _ := levelsReader.ReadByte()
+		for i := uint64(0); i < run>>1; i++ {
+			if level == 0 {
+				values = append(values, nil)
+				continue
+			}
+			var length uint32
+			binary.Read(pageReader, binary.LittleEndian, &length)
+			value := make([]byte, length)
+			io.ReadFull(pageReader, value)
+			values = append(values, strPtr(string(value)))
+		}
+	}
+	return values
+}
+
+// thriftReader decodes the Thrift compact protocol types written by thriftWriter
+type thriftReader struct {
+	r *bytes.Reader
+}
+
+func (tr thriftReader) readStruct() map[int16]interface{} {
+	fields := make(map[int16]interface{})
+	var id int16
+	for {
+		header, _ := tr.r.ReadByte()
+		if header == 0 {
+			return fields
+		}
+		if delta := int16(header >> 4); delta != 0 {
+			id += delta
+		} else {
+			value, _ := binary.ReadVarint(tr.r)
+			id = int16(value)
+		}
+		fields[id] = tr.readValue(header & 0x0f)
+	}
+}
+
+func (tr thriftReader) readValue(valueType byte) interface{} {
+	switch valueType {
+	case thriftI32, thriftI64:
+		value, _ := binary.ReadVarint(tr.r)
+		return value
+	case thriftBinary:
+		size, _ := binary.ReadUvarint(tr.r)
+		value := make([]byte, size)
+		io.ReadFull(tr.r, value)
+		return string(value)
+	case thriftList:
+		header, _ := tr.r.ReadByte()
+		size := uint64(header >> 4)
+		if size == 15 {
+			size, _ = binary.ReadUvarint(tr.r)
+		}
+		var elements []interface{}
+		for i := uint64(0); i < size; i++ {
+			elements = append(elements, tr.readValue(header&0x0f))
+		}
+		return elements
+	case thriftStruct:
+		return tr.readStruct()
+	}
+	panic(fmt.Sprintf("unexpected Thrift type %d", valueType))
+}
+
+func TestExportUnknownField(t *testing.T) {
+
+	Convey("When I export accounts with a field that does not exist", t, func() {
+		_, err := NewExporter().Fields([]string{"id", "colour"}).Build().Export(&bytes.Buffer{}, nil)
+
+		Convey("Then an appropriate error is propagated to the caller", func() {
+			So(err.Error(), ShouldEqual, "Invalid export field [colour]")
+		})
+
+	})
+
+}
@@ -0,0 +1,390 @@
+package accounts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	accountFields = jsonFields(reflect.TypeOf(Account{}))
+)
+
+// jsonFields returns the JSON names of the fields of a struct type
+func jsonFields(t reflect.Type) map[string]bool {
+	fields := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = t.Field(i).Name
+		}
+		fields[name] = true
+	}
+
+	return fields
+}
+
+// unknownFields returns the members of a JSON object that are not in known
+//
+// It returns nil rather than an empty map when every member is known, so decoded values
+// compare equal to the ones built with the builders
+func unknownFields(data []byte, known map[string]bool) (map[string]json.RawMessage, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+
+	var extra map[string]json.RawMessage
+	for name, value := range members {
+		if known[name] {
+			continue
+		}
+		if extra == nil {
+			extra = make(map[string]json.RawMessage)
+		}
+		extra[name] = value
+	}
+
+	return extra, nil
+}
+
+// mergeFields adds the extra members to an encoded JSON object
+//
+// Members already present in the encoded object take precedence over the extra ones
+func mergeFields(encoded []byte, extra map[string]json.RawMessage) ([]byte, error) {
+	if len(extra) == 0 {
+		return encoded, nil
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &members); err != nil {
+		return nil, err
+	}
+	for name, value := range extra {
+		if _, ok := members[name]; !ok {
+			members[name] = value
+		}
+	}
+
+	return json.Marshal(members)
+}
+
+// UnmarshalJSON decodes an account, keeping the attributes this library does not know about in Extra
+func (a *Account) UnmarshalJSON(data []byte) error {
+	type plain Account
+	if err := json.Unmarshal(data, (*plain)(a)); err != nil {
+		return err
+	}
+
+	extra, err := unknownFields(data, accountFields)
+	if err != nil {
+		return err
+	}
+	a.Extra = extra
+
+	return nil
+}
+
+// MarshalJSON encodes an account, re-emitting the attributes kept in Extra
+func (a Account) MarshalJSON() ([]byte, error) {
+	type plain Account
+	encoded, err := json.Marshal(plain(a))
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeFields(encoded, a.Extra)
+}
+
+// MarshalJSON encodes account data, re-emitting the fields kept in Extra
+func (ad AccountData) MarshalJSON() ([]byte, error) {
+	type plain AccountData
+	encoded, err := json.Marshal(plain(ad))
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeFields(encoded, ad.Extra)
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// decodeJSON decodes data into value as json.Unmarshal does, keeping in the Extra field of every struct the
+// members it has no field for and parsing timestamps tolerantly, as the API emits them in several formats
+//
+// Values with a decoding method of their own are decoded by it, so that the payloads of a resource need none
+func decodeJSON(data []byte, value interface{}) error {
+	return decodeValue(data, reflect.ValueOf(value).Elem())
+}
+
+func decodeValue(data []byte, value reflect.Value) error {
+	null := bytes.Equal(bytes.TrimSpace(data), []byte("null"))
+	if value.Kind() == reflect.Ptr {
+		if null {
+			value.Set(reflect.Zero(value.Type()))
+			return nil
+		}
+		if value.IsNil() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		return decodeValue(data, value.Elem())
+	}
+	if value.Type() == timeType {
+		var timestamp *string
+		if err := json.Unmarshal(data, &timestamp); err != nil || timestamp == nil {
+			return err
+		}
+		parsed, err := parseTimestamp(*timestamp)
+		if err != nil {
+			return err
+		}
+		value.Set(reflect.ValueOf(parsed))
+		return nil
+	}
+	if _, ok := value.Addr().Interface().(json.Unmarshaler); ok {
+		return json.Unmarshal(data, value.Addr().Interface())
+	}
+
+	switch {
+	case null:
+		return json.Unmarshal(data, value.Addr().Interface())
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() != reflect.Uint8:
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		decoded := reflect.MakeSlice(value.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := decodeValue(item, decoded.Index(i)); err != nil {
+				return err
+			}
+		}
+		value.Set(decoded)
+		return nil
+	case value.Kind() == reflect.Struct:
+		return decodeStruct(data, value)
+	default:
+		return json.Unmarshal(data, value.Addr().Interface())
+	}
+}
+
+func decodeStruct(data []byte, value reflect.Value) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	known := make(map[string]bool)
+	for i := 0; i < value.NumField(); i++ {
+		name, ok := jsonName(value.Type().Field(i))
+		if !ok {
+			continue
+		}
+		known[name] = true
+		if member, ok := members[name]; ok {
+			if err := decodeValue(member, value.Field(i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	extra, ok := extraField(value)
+	if !ok {
+		return nil
+	}
+	var unknown map[string]json.RawMessage
+	for name, member := range members {
+		if known[name] {
+			continue
+		}
+		if unknown == nil {
+			unknown = make(map[string]json.RawMessage)
+		}
+		unknown[name] = member
+	}
+	extra.Set(reflect.ValueOf(unknown))
+
+	return nil
+}
+
+// encodeJSON encodes value as json.Marshal does, writing back the members kept in the Extra field of every
+// struct after its fields, ordered by name
+//
+// Values with an encoding method of their own are encoded by it, so that the payloads of a resource need none
+func encodeJSON(value interface{}) ([]byte, error) {
+	return encodeValue(reflect.ValueOf(value))
+}
+
+func encodeValue(value reflect.Value) ([]byte, error) {
+	if !value.IsValid() {
+		return []byte("null"), nil
+	}
+	switch {
+	case value.Type().Implements(marshalerType):
+	case (value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface) && !value.IsNil():
+		return encodeValue(value.Elem())
+	case value.Kind() == reflect.Slice && !value.IsNil() && value.Type().Elem().Kind() != reflect.Uint8:
+		items := make([][]byte, value.Len())
+		for i := range items {
+			item, err := encodeValue(value.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return append(append([]byte("["), bytes.Join(items, []byte(","))...), ']'), nil
+	case value.Kind() == reflect.Struct && !reflect.PtrTo(value.Type()).Implements(marshalerType):
+		return encodeStruct(value)
+	}
+
+	return json.Marshal(value.Interface())
+}
+
+func encodeStruct(value reflect.Value) ([]byte, error) {
+	var members [][]byte
+	written := make(map[string]bool)
+	member := func(name string, encoded []byte) {
+		key, _ := json.Marshal(name)
+		members = append(members, append(append(key, ':'), encoded...))
+		written[name] = true
+	}
+
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		name, ok := jsonName(field)
+		if !ok || (strings.Contains(field.Tag.Get("json"), ",omitempty") && isEmptyValue(value.Field(i))) {
+			continue
+		}
+		encoded, err := encodeValue(value.Field(i))
+		if err != nil {
+			return nil, err
+		}
+		member(name, encoded)
+	}
+
+	if extra, ok := extraField(value); ok {
+		unknown := extra.Interface().(map[string]json.RawMessage)
+		names := make([]string, 0, len(unknown))
+		for name := range unknown {
+			if !written[name] {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			member(name, unknown[name])
+		}
+	}
+
+	return append(append([]byte("{"), bytes.Join(members, []byte(","))...), '}'), nil
+}
+
+// jsonName returns the JSON name of a field, false when the field is not encoded
+func jsonName(field reflect.StructField) (string, bool) {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if field.PkgPath != "" || name == "-" {
+		return "", false
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name, true
+}
+
+// extraField returns the Extra field of a struct, if it has one
+func extraField(value reflect.Value) (reflect.Value, bool) {
+	field, ok := value.Type().FieldByName("Extra")
+	if !ok || field.Type != extraType || len(field.Index) > 1 {
+		return reflect.Value{}, false
+	}
+	return value.FieldByIndex(field.Index), true
+}
+
+// isEmptyValue tells whether a field tagged omitempty is left out, as json.Marshal does
+func isEmptyValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return value.Len() == 0
+	case reflect.Bool:
+		return !value.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return value.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return value.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return value.IsNil()
+	}
+	return false
+}
+
+var extraType = reflect.TypeOf(map[string]json.RawMessage{})
+
+// extraFields returns the path of every member kept in an Extra map while decoding value, walking the values
+// it holds by their JSON names
+func extraFields(value reflect.Value, path string) []string {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+		return extraFields(value.Elem(), path)
+	case reflect.Slice:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			return nil
+		}
+		var fields []string
+		for i := 0; i < value.Len(); i++ {
+			fields = append(fields, extraFields(value.Index(i), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return fields
+	case reflect.Struct:
+	default:
+		return nil
+	}
+
+	var fields []string
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if field.Name == "Extra" && field.Type == extraType {
+			for name := range value.Field(i).Interface().(map[string]json.RawMessage) {
+				fields = append(fields, joinPath(path, name))
+			}
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, extraFields(value.Field(i), joinPath(path, name))...)
+	}
+	sort.Strings(fields)
+
+	return fields
+}
+
+func joinPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
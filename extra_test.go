@@ -0,0 +1,170 @@
+package accounts
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const accountWithUnknownFields = `{
+	"data": {
+		"id": "%[1]s",
+		"organisation_id": "%[2]s",
+		"type": "accounts",
+		"status": "confirmed",
+		"attributes": {"country": "GB", "name": ["Jane", "Doe"]}
+	},
+	"links": {"self": "/v1/organisation/accounts/%[1]s"}
+}`
+
+func TestFetchAccountWithUnknownFields(t *testing.T) {
+
+	Convey("Given the API returns an account with fields the library does not know about", t, func() {
+		ID := uuid.New()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, accountWithUnknownFields, ID, OrganisationID)
+		}))
+		defer server.Close()
+
+		Convey("When I fetch the account by ID", func() {
+
+			AccountsService := NewClient().HTTPClient(HTTPClient).URL(server.URL).Build()
+			resp, err := AccountsService.Fetch(ID)
+
+			Convey("Then the unknown fields are kept", func() {
+				So(err, ShouldBeNil)
+				So(string(resp.AccountData.Extra["status"]), ShouldEqual, `"confirmed"`)
+				So(string(resp.AccountData.Attributes.Extra["name"]), ShouldEqual, `["Jane", "Doe"]`)
+			})
+
+			Convey("And they are sent again when the account is encoded", func() {
+				encoded, _ := json.Marshal(resp.AccountData)
+
+				var decoded map[string]interface{}
+				json.Unmarshal(encoded, &decoded)
+				So(decoded["status"], ShouldEqual, "confirmed")
+				So(decoded["attributes"].(map[string]interface{})["name"], ShouldResemble, []interface{}{"Jane", "Doe"})
+			})
+
+		})
+
+		Convey("When I fetch the account by ID with strict decoding", func() {
+
+			AccountsService := NewClient().HTTPClient(HTTPClient).URL(server.URL).StrictDecoding(true).Build()
+			_, err := AccountsService.Fetch(ID)
+
+			Convey("Then an appropriate error is propagated to the caller", func() {
+				So(err.Error(), ShouldEqual, "Unknown fields [data.attributes.name data.status] in response")
+			})
+
+		})
+
+	})
+
+}
+
+func TestFetchResponseWithUnknownTopLevelFieldWithStrictDecoding(t *testing.T) {
+
+	Convey("When I fetch an account whose response has an unknown top level member with strict decoding", t, func() {
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"data": {"id": "1", "type": "accounts", "attributes": {"country": "GB"}}, "meta": {}, "links": {"self": ""}}`)
+		}))
+		defer server.Close()
+
+		AccountsService := NewClient().HTTPClient(HTTPClient).URL(server.URL).StrictDecoding(true).Build()
+		_, err := AccountsService.Fetch(uuid.New())
+
+		Convey("Then an appropriate error is propagated to the caller", func() {
+			So(err.Error(), ShouldEqual, "Unknown fields [meta] in response")
+		})
+
+	})
+
+}
+
+func TestListResponseWithUnknownNestedFieldsWithStrictDecoding(t *testing.T) {
+
+	Convey("When I list accounts whose links, relationships and included resources have unknown members with strict decoding", t, func() {
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"data": [{"id": "1", "type": "accounts", "attributes": {"country": "GB"},
+				"relationships": {"master_account": {"data": [], "meta": {}}, "owner": {"data": null}}}],
+				"included": [{"id": "2", "type": "accounts", "created_on": "2021-03-01T10:00:00Z", "status": "confirmed"}],
+				"links": {"self": "", "related": ""}}`)
+		}))
+		defer server.Close()
+
+		AccountsService := NewClient().HTTPClient(HTTPClient).URL(server.URL).StrictDecoding(true).Build()
+		_, err := AccountsService.List(nil, nil)
+
+		Convey("Then every unknown member is reported with its path", func() {
+			So(err.Error(), ShouldEqual, "Unknown fields [data[0].relationships.master_account.meta data[0].relationships.owner included[0].status links.related] in response")
+		})
+
+	})
+
+	Convey("When I fetch an organisation whose attributes have an unknown member with strict decoding", t, func() {
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"data": {"id": "1", "type": "organisations", "attributes": {"name": "Acme", "status": "active"}}, "links": {"self": ""}}`)
+		}))
+		defer server.Close()
+
+		OrganisationsService := NewOrganisationsClient().HTTPClient(HTTPClient).URL(server.URL).StrictDecoding(true).Build()
+		_, err := OrganisationsService.Fetch(uuid.New())
+
+		Convey("Then it is reported the same way as for accounts", func() {
+			So(err.Error(), ShouldEqual, "Unknown fields [data.attributes.status] in response")
+		})
+
+	})
+
+}
+
+func TestUpdateOrganisationWithUnknownFields(t *testing.T) {
+
+	Convey("Given the API returns an organisation with fields the library does not know about", t, func() {
+		ID := uuid.New()
+		var sent []byte
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPatch {
+				sent, _ = ioutil.ReadAll(r.Body)
+			}
+			fmt.Fprintf(w, `{"data": {"id": "%s", "type": "organisations", "version": 0, "status": "active", "attributes": {"name": "Acme", "region": "EU"}}, "links": {"self": "", "audit": "/v1/audit"}}`, ID)
+		}))
+		defer server.Close()
+
+		OrganisationsService := NewOrganisationsClient().HTTPClient(HTTPClient).URL(server.URL).Build()
+
+		Convey("When I fetch the organisation", func() {
+			resp, err := OrganisationsService.Fetch(ID)
+
+			Convey("Then the unknown fields are kept at every level", func() {
+				So(err, ShouldBeNil)
+				So(string(resp.OrganisationData.Extra["status"]), ShouldEqual, `"active"`)
+				So(string(resp.OrganisationData.Attributes.Extra["region"]), ShouldEqual, `"EU"`)
+				So(string(resp.Links.Extra["audit"]), ShouldEqual, `"/v1/audit"`)
+			})
+
+			Convey("And they are sent again when the organisation is updated", func() {
+				resp.OrganisationData.Attributes.Name = "Acme Ltd"
+				_, err := OrganisationsService.Update(ID, resp.OrganisationData)
+
+				So(err, ShouldBeNil)
+				So(string(sent), ShouldEqual, fmt.Sprintf(`{"data":{"id":"%s","type":"organisations","version":0,"attributes":{"name":"Acme Ltd","region":"EU"},"status":"active"}}`, ID))
+			})
+		})
+
+	})
+
+}
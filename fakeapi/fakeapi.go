@@ -0,0 +1,472 @@
+// Package fakeapi is an in-memory Accounts API for tests, along with its account routings, able to misbehave
+// on demand
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	accounts "github.com/razvanmuscalu/form3-accounts-client"
+	"github.com/razvanmuscalu/form3-accounts-client/internal/paging"
+	"github.com/razvanmuscalu/form3-accounts-client/internal/patch"
+)
+
+const (
+	accountsPath        = "/v1/organisation/accounts"
+	accountRoutingsPath = "/v1/organisation/accountroutings"
+)
+
+// Route is an endpoint of the fake API, a method and a path where {id} stands for any path segment
+type Route string
+
+const (
+	// AnyRoute stands for every route when injecting faults
+	AnyRoute Route = "*"
+
+	// the routes of the accounts endpoints
+	CreateAccount Route = "POST " + accountsPath
+	ListAccounts  Route = "GET " + accountsPath
+	FetchAccount  Route = "GET " + accountsPath + "/{id}"
+	UpdateAccount Route = "PATCH " + accountsPath + "/{id}"
+	DeleteAccount Route = "DELETE " + accountsPath + "/{id}"
+
+	// the routes of the account routings endpoints
+	CreateAccountRouting Route = "POST " + accountRoutingsPath
+	ListAccountRoutings  Route = "GET " + accountRoutingsPath
+	FetchAccountRouting  Route = "GET " + accountRoutingsPath + "/{id}"
+	DeleteAccountRouting Route = "DELETE " + accountRoutingsPath + "/{id}"
+)
+
+// matches tells whether a request is for the route, returning the values of its {id} segments
+func (r Route) matches(req *http.Request) ([]string, bool) {
+	parts := strings.SplitN(string(r), " ", 2)
+	if len(parts) != 2 || parts[0] != req.Method {
+		return nil, false
+	}
+
+	want := strings.Split(strings.Trim(parts[1], "/"), "/")
+	got := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	if len(want) != len(got) {
+		return nil, false
+	}
+
+	var ids []string
+	for i := range want {
+		if want[i] == "{id}" {
+			ids = append(ids, got[i])
+			continue
+		}
+		if want[i] != got[i] {
+			return nil, false
+		}
+	}
+	return ids, true
+}
+
+// Server is a running fake Accounts API
+type Server interface {
+	// URL is the base URL of the API, to be given to the client
+	URL() string
+	// Inject makes the requests for route, or for every route with AnyRoute, misbehave as described by faults,
+	// faults injected earlier applying first
+	Inject(route Route, faults ...Fault)
+	// ClearFaults makes the API behave again
+	ClearFaults()
+	// Requests is the number of requests received for route, or for every route with AnyRoute
+	Requests(route Route) int
+	// Close shuts the API down
+	Close()
+}
+
+type server struct {
+	mutex    sync.Mutex
+	http     *httptest.Server
+	random   *rand.Rand
+	now      func() time.Time
+	routes   []route
+	faults   []*injectedFault
+	requests map[Route]int
+
+	accounts map[string]accounts.AccountData
+	order    []string
+	// missingDeleteStatus answers the delete of a missing account
+	missingDeleteStatus int
+
+	routings     map[string]accounts.AccountRoutingData
+	routingOrder []string
+}
+
+// route is a route along with the handler serving it, given the values of the {id} segments
+type route struct {
+	route   Route
+	handler func(w http.ResponseWriter, r *http.Request, ids []string)
+}
+
+// ServerBuilder is used to create a Server
+type ServerBuilder interface {
+	Seed(int64) ServerBuilder
+	Clock(func() time.Time) ServerBuilder
+	Address(string) ServerBuilder
+	MissingDeleteStatus(int) ServerBuilder
+	Build() Server
+}
+
+type serverBuilder struct {
+	seed                int64
+	clock               func() time.Time
+	address             string
+	missingDeleteStatus int
+}
+
+// Seed makes random latencies the same from one run to the next, they are seeded with the time when not set
+func (sb *serverBuilder) Seed(value int64) ServerBuilder {
+	sb.seed = value
+	return sb
+}
+
+// Clock gives the time accounts are created at, time.Now when not set
+func (sb *serverBuilder) Clock(value func() time.Time) ServerBuilder {
+	sb.clock = value
+	return sb
+}
+
+// Address is where the API listens, e.g. ":8080" to serve the client tests from another container, a
+// random local port when not set
+func (sb *serverBuilder) Address(value string) ServerBuilder {
+	sb.address = value
+	return sb
+}
+
+// MissingDeleteStatus answers the delete of an account that does not exist, 404 when not set; the client
+// tests expect a success such as 204, which the docker-compose API answers
+func (sb *serverBuilder) MissingDeleteStatus(value int) ServerBuilder {
+	sb.missingDeleteStatus = value
+	return sb
+}
+
+// Build starts the API on a local port, panicking when it cannot listen on the address, as httptest does
+func (sb *serverBuilder) Build() Server {
+	seed := sb.seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	s := &server{
+		random:              rand.New(rand.NewSource(seed)),
+		now:                 sb.clock,
+		requests:            make(map[Route]int),
+		accounts:            make(map[string]accounts.AccountData),
+		missingDeleteStatus: sb.missingDeleteStatus,
+		routings:            make(map[string]accounts.AccountRoutingData),
+	}
+	if s.now == nil {
+		s.now = time.Now
+	}
+	if s.missingDeleteStatus == 0 {
+		s.missingDeleteStatus = http.StatusNotFound
+	}
+
+	s.routes = []route{
+		{route: CreateAccount, handler: s.createAccount},
+		{route: ListAccounts, handler: s.listAccounts},
+		{route: FetchAccount, handler: s.fetchAccount},
+		{route: UpdateAccount, handler: s.updateAccount},
+		{route: DeleteAccount, handler: s.deleteAccount},
+		{route: CreateAccountRouting, handler: s.createAccountRouting},
+		{route: ListAccountRoutings, handler: s.listAccountRoutings},
+		{route: FetchAccountRouting, handler: s.fetchAccountRouting},
+		{route: DeleteAccountRouting, handler: s.deleteAccountRouting},
+	}
+	s.http = httptest.NewUnstartedServer(s)
+	if sb.address != "" {
+		listener, err := net.Listen("tcp", sb.address)
+		if err != nil {
+			panic(fmt.Sprintf("fakeapi: failed to listen on %s: %v", sb.address, err))
+		}
+		s.http.Listener.Close()
+		s.http.Listener = listener
+	}
+	s.http.Start()
+
+	return s
+}
+
+// NewServer is used to create a ServerBuilder
+func NewServer() ServerBuilder {
+	return &serverBuilder{}
+}
+
+func (s *server) URL() string {
+	return s.http.URL
+}
+
+func (s *server) Close() {
+	s.http.Close()
+}
+
+func (s *server) Requests(route Route) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if route != AnyRoute {
+		return s.requests[route]
+	}
+	total := 0
+	for _, count := range s.requests {
+		total += count
+	}
+	return total
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	for _, rt := range s.routes {
+		ids, ok := rt.route.matches(r)
+		if !ok {
+			continue
+		}
+
+		s.mutex.Lock()
+		s.requests[rt.route]++
+		s.mutex.Unlock()
+
+		s.serve(w, r, rt.route, func(w http.ResponseWriter) {
+			rt.handler(w, r, ids)
+		})
+		return
+	}
+
+	writeError(w, http.StatusNotFound, fmt.Sprintf("route %s %s not found", r.Method, r.URL.Path))
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	body, _ := json.Marshal(value)
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, accounts.ErrorResponse{ErrorMessage: message})
+}
+
+func (s *server) createAccount(w http.ResponseWriter, r *http.Request, _ []string) {
+	var request struct {
+		Data *accounts.AccountData `json:"data"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Data == nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	data := *request.Data
+	if message := validate(data); message != "" {
+		writeError(w, http.StatusBadRequest, "validation failure list:\n"+message)
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.accounts[data.ID]; ok {
+		writeError(w, http.StatusConflict, "Account cannot be created as it violates a duplicate constraint")
+		return
+	}
+
+	now := s.now().UTC()
+	version := 0
+	data.CreatedOn = &now
+	data.ModifiedOn = &now
+	data.Version = &version
+	s.accounts[data.ID] = data
+	s.order = append(s.order, data.ID)
+
+	writeJSON(w, http.StatusCreated, accounts.Single{
+		AccountData: data,
+		Links:       accounts.Links{Self: accountsPath + "/" + data.ID},
+	})
+}
+
+// validCountry matches the two-letter country codes the API accepts
+var validCountry = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// validate checks the fields the API requires, returning what is wrong with them
+func validate(data accounts.AccountData) string {
+	var failures []string
+	if _, err := uuid.Parse(data.ID); err != nil {
+		failures = append(failures, fmt.Sprintf("id in body must be of type uuid: %q", data.ID))
+	}
+	if _, err := uuid.Parse(data.OrganisationID); err != nil {
+		failures = append(failures, fmt.Sprintf("organisation_id in body must be of type uuid: %q", data.OrganisationID))
+	}
+	if data.Type != "accounts" {
+		failures = append(failures, fmt.Sprintf("type in body should be one of [accounts]: %q", data.Type))
+	}
+	if data.Attributes.Country == "" {
+		failures = append(failures, "country in body is required")
+	}
+
+	return strings.Join(failures, "\n")
+}
+
+func (s *server) fetchAccount(w http.ResponseWriter, r *http.Request, ids []string) {
+	s.mutex.Lock()
+	data, ok := s.accounts[ids[0]]
+	s.mutex.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("record %s does not exist", ids[0]))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, accounts.Single{
+		AccountData: data,
+		Links:       accounts.Links{Self: accountsPath + "/" + data.ID},
+	})
+}
+
+// listAccounts pages through the accounts in the order they were created
+func (s *server) listAccounts(w http.ResponseWriter, r *http.Request, _ []string) {
+	organisationID := r.URL.Query().Get("filter[organisation_id]")
+
+	s.mutex.Lock()
+	var matching []accounts.AccountData
+	for _, id := range s.order {
+		if data := s.accounts[id]; organisationID == "" || data.OrganisationID == organisationID {
+			matching = append(matching, data)
+		}
+	}
+	s.mutex.Unlock()
+
+	start, end, links := paginate(r, accountsPath, len(matching))
+	list := accounts.List{Links: links}
+	if start < end {
+		page := append([]accounts.AccountData{}, matching[start:end]...)
+		list.AccountData = &page
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+// paginate returns the bounds of the requested page among total items, along with its links
+func paginate(r *http.Request, listPath string, total int) (int, int, accounts.Links) {
+	query := r.URL.Query()
+	number, err := strconv.Atoi(query.Get("page[number]"))
+	if err != nil {
+		number = 0
+	}
+	size, err := strconv.Atoi(query.Get("page[size]"))
+	if err != nil {
+		size = paging.DefaultSize
+	}
+
+	return paging.Paginate(listPath, number, size, total, paging.OrganisationFilter(query.Get("filter[organisation_id]")))
+}
+
+// updateAccount applies the attributes of the request when its version is the current one, bumping the version
+//
+// The country may be left out, but is validated when sent
+func (s *server) updateAccount(w http.ResponseWriter, r *http.Request, ids []string) {
+	var request struct {
+		Data *struct {
+			ID         string          `json:"id"`
+			Version    *int            `json:"version"`
+			Attributes json.RawMessage `json:"attributes"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Data == nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if request.Data.ID != ids[0] {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("validation failure list:\nid in body must match the path: %q", request.Data.ID))
+		return
+	}
+	var attributes struct {
+		Country *string `json:"country"`
+	}
+	if err := json.Unmarshal(request.Data.Attributes, &attributes); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if attributes.Country != nil && !validCountry.MatchString(*attributes.Country) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("validation failure list:\ncountry in body should match '^[A-Z]{2}$': %q", *attributes.Country))
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	data, ok := s.accounts[ids[0]]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("record %s does not exist", ids[0]))
+		return
+	}
+	if request.Data.Version == nil || data.Version == nil || *data.Version != *request.Data.Version {
+		writeError(w, http.StatusConflict, "invalid version")
+		return
+	}
+
+	patched, err := patch.Merge(data.Attributes, request.Data.Attributes)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	now := s.now().UTC()
+	version := *data.Version + 1
+	data.Attributes = patched
+	data.ModifiedOn = &now
+	data.Version = &version
+	s.accounts[data.ID] = data
+
+	writeJSON(w, http.StatusOK, accounts.Single{
+		AccountData: data,
+		Links:       accounts.Links{Self: accountsPath + "/" + data.ID},
+	})
+}
+
+// deleteAccount answers a missing account with the missing delete status, 404 unless told otherwise
+func (s *server) deleteAccount(w http.ResponseWriter, r *http.Request, ids []string) {
+	version, err := strconv.Atoi(r.URL.Query().Get("version"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid version number")
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	data, ok := s.accounts[ids[0]]
+	switch {
+	case !ok && s.missingDeleteStatus == http.StatusNotFound:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("record %s does not exist", ids[0]))
+		return
+	case !ok:
+		w.WriteHeader(s.missingDeleteStatus)
+		return
+	}
+	if data.Version == nil || *data.Version != version {
+		writeError(w, http.StatusConflict, "invalid version")
+		return
+	}
+
+	delete(s.accounts, ids[0])
+	for i, id := range s.order {
+		if id == ids[0] {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,271 @@
+package fakeapi
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	accounts "github.com/razvanmuscalu/form3-accounts-client"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func newAccount(organisationID string) accounts.AccountData {
+	return accounts.NewAccountData().
+		ID(uuid.New().String()).
+		OrganisationID(organisationID).
+		Type("accounts").
+		Attributes(accounts.NewAccount().Country("GB").Build()).
+		Build()
+}
+
+func TestFakeAPI(t *testing.T) {
+
+	Convey("Given a fake API and a client", t, func() {
+		api := NewServer().Build()
+		defer api.Close()
+
+		client := accounts.NewClient().HTTPClient(http.Client{Timeout: time.Second}).URL(api.URL()).Build()
+		organisationID := uuid.New().String()
+
+		Convey("When I create an account", func() {
+			data := newAccount(organisationID)
+			created, err := client.Create(data)
+
+			Convey("Then it can be fetched", func() {
+				So(err, ShouldBeNil)
+				So(*created.AccountData.Version, ShouldEqual, 0)
+
+				fetched, err := client.Fetch(uuid.MustParse(data.ID))
+				So(err, ShouldBeNil)
+				So(fetched.AccountData.ID, ShouldEqual, data.ID)
+				So(fetched.AccountData.Attributes.Country, ShouldEqual, "GB")
+			})
+
+			Convey("And it cannot be created again", func() {
+				_, err := client.Create(data)
+				So(err, ShouldBeError, "Account cannot be created as it violates a duplicate constraint")
+			})
+
+			Convey("And it cannot be deleted with another version", func() {
+				_, err := client.Delete(uuid.MustParse(data.ID), 1)
+				var apiErr *accounts.APIError
+				So(errors.As(err, &apiErr), ShouldBeTrue)
+				So(apiErr.StatusCode, ShouldEqual, http.StatusConflict)
+			})
+
+			Convey("And it can be updated with its version", func() {
+				patch := accounts.NewAccountData().Version(0).Attributes(accounts.NewAccount().BankAccountName("Sam Holder").Build()).Build()
+				updated, err := client.Update(uuid.MustParse(data.ID), patch)
+				So(err, ShouldBeNil)
+				So(*updated.AccountData.Version, ShouldEqual, 1)
+				So(*updated.AccountData.Attributes.BankAccountName, ShouldEqual, "Sam Holder")
+				So(updated.AccountData.Attributes.Country, ShouldEqual, "GB")
+
+				_, err = client.Update(uuid.MustParse(data.ID), patch)
+				So(err, ShouldBeError, "invalid version")
+			})
+
+			Convey("And it cannot be updated with an invalid country", func() {
+				body := `{"data":{"id":"` + data.ID + `","version":0,"attributes":{"country":""}}}`
+				req, _ := http.NewRequest(http.MethodPatch, api.URL()+accountsPath+"/"+data.ID, strings.NewReader(body))
+				resp, err := http.DefaultClient.Do(req)
+				So(err, ShouldBeNil)
+				resp.Body.Close()
+				So(resp.StatusCode, ShouldEqual, http.StatusBadRequest)
+			})
+
+			Convey("And it is gone once deleted", func() {
+				deleted, err := client.Delete(uuid.MustParse(data.ID), 0)
+				So(err, ShouldBeNil)
+				So(deleted, ShouldBeTrue)
+
+				_, err = client.Fetch(uuid.MustParse(data.ID))
+				So(err.Error(), ShouldEqual, "record "+data.ID+" does not exist")
+			})
+		})
+
+		Convey("When I list the accounts of an organisation by pages", func() {
+			for i := 0; i < 5; i++ {
+				client.Create(newAccount(organisationID))
+			}
+			client.Create(newAccount(uuid.New().String()))
+
+			first, _ := client.List(&accounts.Page{Number: 0, Size: 2}, &accounts.Filter{OrganisationID: &organisationID})
+			last, _ := client.List(&accounts.Page{Number: 2, Size: 2}, &accounts.Filter{OrganisationID: &organisationID})
+
+			Convey("Then the pages link to each other", func() {
+				So(len(*first.AccountData), ShouldEqual, 2)
+				So(*first.Links.Next, ShouldContainSubstring, "page%5Bnumber%5D=1")
+				So(*first.Links.Last, ShouldContainSubstring, "page%5Bnumber%5D=2")
+				So(first.Links.Prev, ShouldBeNil)
+
+				So(len(*last.AccountData), ShouldEqual, 1)
+				So(last.Links.Next, ShouldBeNil)
+				So(*last.Links.Prev, ShouldContainSubstring, "page%5Bnumber%5D=1")
+			})
+		})
+
+		Convey("When I list the accounts of an organisation without any", func() {
+			resp, err := client.List(nil, &accounts.Filter{OrganisationID: &organisationID})
+
+			Convey("Then the list holds no data", func() {
+				So(err, ShouldBeNil)
+				So(resp.AccountData, ShouldBeNil)
+			})
+		})
+
+		Convey("When I delete an account that does not exist", func() {
+			ID := uuid.New()
+			deleted, err := client.Delete(ID, 0)
+
+			Convey("Then it is not found", func() {
+				So(deleted, ShouldBeFalse)
+				var apiErr *accounts.APIError
+				So(errors.As(err, &apiErr), ShouldBeTrue)
+				So(apiErr.StatusCode, ShouldEqual, http.StatusNotFound)
+				So(err.Error(), ShouldEqual, "record "+ID.String()+" does not exist")
+			})
+		})
+
+		Convey("When I purge accounts, one of which was deleted meanwhile", func() {
+			var listed []accounts.AccountData
+			for i := 0; i < 3; i++ {
+				resp, err := client.Create(newAccount(organisationID))
+				So(err, ShouldBeNil)
+				listed = append(listed, resp.AccountData)
+			}
+			_, err := client.Delete(uuid.MustParse(listed[1].ID), 0)
+			So(err, ShouldBeNil)
+
+			summary := accounts.NewPurger().Client(client).Build().DeleteMany(listed)
+
+			Convey("Then the account that no longer exists is skipped", func() {
+				So(summary.Deleted, ShouldHaveLength, 2)
+				So(summary.Skipped, ShouldResemble, []string{listed[1].ID})
+				So(summary.Failed, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When a burst of server errors is injected", func() {
+			api.Inject(FetchAccount, ServerErrors(http.StatusServiceUnavailable).Times(2))
+			ID := uuid.New()
+
+			var errs []error
+			for i := 0; i < 3; i++ {
+				_, err := client.Fetch(ID)
+				errs = append(errs, err)
+			}
+
+			Convey("Then only the requests of the burst fail with it", func() {
+				var apiErr *accounts.APIError
+				So(errors.As(errs[0], &apiErr), ShouldBeTrue)
+				So(apiErr.StatusCode, ShouldEqual, http.StatusServiceUnavailable)
+				So(errors.As(errs[1], &apiErr), ShouldBeTrue)
+				So(apiErr.StatusCode, ShouldEqual, http.StatusServiceUnavailable)
+				So(errs[2].Error(), ShouldEqual, "record "+ID.String()+" does not exist")
+				So(api.Requests(FetchAccount), ShouldEqual, 3)
+			})
+		})
+
+		Convey("When rate limiting is injected", func() {
+			api.Inject(AnyRoute, TooManyRequests(1500*time.Millisecond))
+			resp, err := http.Get(api.URL() + accountsPath)
+
+			Convey("Then the API tells when to retry", func() {
+				So(err, ShouldBeNil)
+				resp.Body.Close()
+				So(resp.StatusCode, ShouldEqual, http.StatusTooManyRequests)
+				So(resp.Header.Get("Retry-After"), ShouldEqual, "2")
+			})
+		})
+
+		Convey("When latency longer than the client timeout is injected", func() {
+			api.Inject(ListAccounts, RandomLatency(1500*time.Millisecond, 2*time.Second))
+			_, err := client.List(nil, nil)
+
+			Convey("Then the client gives up", func() {
+				var requestErr *accounts.RequestError
+				So(errors.As(err, &requestErr), ShouldBeTrue)
+			})
+		})
+
+		Convey("When latency is injected on another route", func() {
+			api.Inject(FetchAccount, Latency(2*time.Second))
+			_, err := client.List(nil, nil)
+
+			Convey("Then the route is not delayed", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When a truncated body is injected", func() {
+			api.Inject(ListAccounts, TruncatedBody())
+			_, err := client.List(nil, nil)
+
+			Convey("Then the response cannot be decoded", func() {
+				var responseErr *accounts.ResponseError
+				So(errors.As(err, &responseErr), ShouldBeTrue)
+			})
+		})
+
+		Convey("When a truncated body is injected and the response read", func() {
+			api.Inject(ListAccounts, TruncatedBody())
+			resp, err := http.Get(api.URL() + accountsPath)
+			So(err, ShouldBeNil)
+			defer resp.Body.Close()
+			body, readErr := ioutil.ReadAll(resp.Body)
+
+			Convey("Then the whole body is announced but the connection ends after part of it", func() {
+				So(errors.Is(readErr, io.ErrUnexpectedEOF), ShouldBeTrue)
+				So(len(body), ShouldBeGreaterThan, 0)
+				So(int64(len(body)), ShouldBeLessThan, resp.ContentLength)
+			})
+		})
+
+		Convey("When a malformed body is injected", func() {
+			api.Inject(ListAccounts, MalformedBody())
+			_, err := client.List(nil, nil)
+
+			Convey("Then the response cannot be decoded", func() {
+				var responseErr *accounts.ResponseError
+				So(errors.As(err, &responseErr), ShouldBeTrue)
+			})
+		})
+
+		Convey("When connection resets are injected", func() {
+			api.Inject(ListAccounts, ConnectionReset())
+			_, err := client.List(nil, nil)
+
+			Convey("Then the request fails", func() {
+				var requestErr *accounts.RequestError
+				So(errors.As(err, &requestErr), ShouldBeTrue)
+			})
+		})
+
+		Convey("When a slow drip is injected", func() {
+			api.Inject(ListAccounts, SlowDrip(1, 100*time.Millisecond))
+			_, err := client.List(nil, nil)
+
+			Convey("Then the client times out reading the body", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When faults are cleared", func() {
+			api.Inject(AnyRoute, ConnectionReset())
+			api.ClearFaults()
+			_, err := client.List(nil, nil)
+
+			Convey("Then the API behaves again", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,283 @@
+package fakeapi
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"time"
+)
+
+// faultKind tells how a fault changes the response
+type faultKind int
+
+const (
+	latencyFault faultKind = iota
+	statusFault
+	truncatedFault
+	malformedFault
+	resetFault
+	dripFault
+)
+
+// Fault is a way for the fake API to misbehave, on every request unless limited with Times
+type Fault struct {
+	kind       faultKind
+	latency    time.Duration
+	jitter     time.Duration
+	status     int
+	retryAfter time.Duration
+	chunk      int
+	interval   time.Duration
+	times      int
+}
+
+// Latency delays the response by a fixed duration
+func Latency(value time.Duration) Fault {
+	return Fault{kind: latencyFault, latency: value}
+}
+
+// RandomLatency delays the response by a random duration between min and max
+func RandomLatency(min time.Duration, max time.Duration) Fault {
+	return Fault{kind: latencyFault, latency: min, jitter: max - min}
+}
+
+// ServerErrors answers with an error status, a burst of them when limited with Times
+func ServerErrors(status int) Fault {
+	return Fault{kind: statusFault, status: status}
+}
+
+// TooManyRequests answers with a 429 status telling to retry after the given duration
+func TooManyRequests(retryAfter time.Duration) Fault {
+	return Fault{kind: statusFault, status: http.StatusTooManyRequests, retryAfter: retryAfter}
+}
+
+// TruncatedBody announces the whole body but sends its first half only, then drops the connection
+func TruncatedBody() Fault {
+	return Fault{kind: truncatedFault}
+}
+
+// MalformedBody answers with a body that is not valid JSON
+func MalformedBody() Fault {
+	return Fault{kind: malformedFault}
+}
+
+// ConnectionReset resets the connection instead of answering
+func ConnectionReset() Fault {
+	return Fault{kind: resetFault}
+}
+
+// SlowDrip sends the body chunk bytes at a time, waiting interval before each chunk
+func SlowDrip(chunk int, interval time.Duration) Fault {
+	if chunk < 1 {
+		chunk = 1
+	}
+	return Fault{kind: dripFault, chunk: chunk, interval: interval}
+}
+
+// Times limits the fault to the next n requests of its route
+func (f Fault) Times(n int) Fault {
+	f.times = n
+	return f
+}
+
+func (f Fault) String() string {
+	switch f.kind {
+	case latencyFault:
+		return fmt.Sprintf("latency %s+%s", f.latency, f.jitter)
+	case statusFault:
+		return fmt.Sprintf("status %d", f.status)
+	case truncatedFault:
+		return "truncated body"
+	case malformedFault:
+		return "malformed body"
+	case resetFault:
+		return "connection reset"
+	case dripFault:
+		return fmt.Sprintf("slow drip %d bytes every %s", f.chunk, f.interval)
+	}
+	return "unknown fault"
+}
+
+// injectedFault is a fault injected on a route along with the number of requests it still applies to,
+// it applies to every request when remaining is 0
+type injectedFault struct {
+	route     Route
+	fault     Fault
+	remaining int
+}
+
+func (s *server) Inject(route Route, faults ...Fault) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, fault := range faults {
+		s.faults = append(s.faults, &injectedFault{route: route, fault: fault, remaining: fault.times})
+	}
+}
+
+func (s *server) ClearFaults() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.faults = nil
+}
+
+// take returns the faults applying to a request for route, using up the ones limited in number
+func (s *server) take(route Route) []Fault {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var taken []Fault
+	kept := s.faults[:0]
+	for _, injected := range s.faults {
+		if injected.route != route && injected.route != AnyRoute {
+			kept = append(kept, injected)
+			continue
+		}
+
+		taken = append(taken, injected.fault)
+		if injected.remaining == 0 {
+			kept = append(kept, injected)
+			continue
+		}
+		injected.remaining--
+		if injected.remaining > 0 {
+			kept = append(kept, injected)
+		}
+	}
+	s.faults = kept
+
+	return taken
+}
+
+// serve handles a request for route, applying the faults injected for it; latencies add up while only the
+// first of the other faults applies
+func (s *server) serve(w http.ResponseWriter, r *http.Request, route Route, handle func(w http.ResponseWriter)) {
+	var response *Fault
+	for _, fault := range s.take(route) {
+		if fault.kind == latencyFault {
+			if !s.sleep(r, fault.latency+s.jitter(fault.jitter)) {
+				return
+			}
+			continue
+		}
+		if response == nil {
+			fault := fault
+			response = &fault
+		}
+	}
+
+	if response == nil {
+		handle(w)
+		return
+	}
+
+	switch response.kind {
+	case statusFault:
+		if response.retryAfter > 0 {
+			seconds := int((response.retryAfter + time.Second - 1) / time.Second)
+			w.Header().Set("Retry-After", strconv.Itoa(seconds))
+		}
+		writeError(w, response.status, http.StatusText(response.status))
+	case resetFault:
+		reset(w)
+	default:
+		recorder := httptest.NewRecorder()
+		handle(recorder)
+		s.rewrite(w, r, recorder, *response)
+	}
+}
+
+func (s *server) jitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return time.Duration(s.random.Int63n(int64(max)))
+}
+
+// sleep waits for duration unless the client gives up first, telling whether it waited
+func (s *server) sleep(r *http.Request, duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-r.Context().Done():
+		return false
+	}
+}
+
+// reset closes the connection without answering, discarding unsent data so that the client sees a reset
+func reset(w http.ResponseWriter) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		panic(http.ErrAbortHandler)
+	}
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		tcp.SetLinger(0)
+	}
+	conn.Close()
+}
+
+// rewrite sends the response the handler recorded, altered by fault
+func (s *server) rewrite(w http.ResponseWriter, r *http.Request, recorded *httptest.ResponseRecorder, fault Fault) {
+	body := recorded.Body.Bytes()
+	length := len(body)
+	switch fault.kind {
+	case truncatedFault:
+		body = body[:len(body)/2]
+	case malformedFault:
+		body = bytes.Replace(body, []byte("{"), []byte("{,"), 1)
+		if len(body) == 0 {
+			body = []byte("{,")
+		}
+	}
+
+	for name, values := range recorded.Header() {
+		w.Header()[name] = values
+	}
+	if fault.kind != truncatedFault {
+		length = len(body)
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(length))
+	w.WriteHeader(recorded.Code)
+
+	if fault.kind == truncatedFault {
+		// the connection is dropped once the part of the body is sent, so that the client sees it end early
+		w.Write(body)
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		panic(http.ErrAbortHandler)
+	}
+	if fault.kind != dripFault {
+		w.Write(body)
+		return
+	}
+
+	flusher, _ := w.(http.Flusher)
+	for start := 0; start < len(body); start += fault.chunk {
+		if !s.sleep(r, fault.interval) {
+			return
+		}
+		end := start + fault.chunk
+		if end > len(body) {
+			end = len(body)
+		}
+		w.Write(body[start:end])
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
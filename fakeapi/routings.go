@@ -0,0 +1,142 @@
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	accounts "github.com/razvanmuscalu/form3-accounts-client"
+)
+
+func (s *server) createAccountRouting(w http.ResponseWriter, r *http.Request, _ []string) {
+	var request struct {
+		Data *accounts.AccountRoutingData `json:"data"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Data == nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	data := *request.Data
+	if message := validateRouting(data); message != "" {
+		writeError(w, http.StatusBadRequest, "validation failure list:\n"+message)
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.routings[data.ID]; ok {
+		writeError(w, http.StatusConflict, "Account routing cannot be created as it violates a duplicate constraint")
+		return
+	}
+
+	now := s.now().UTC()
+	version := 0
+	data.CreatedOn = &now
+	data.ModifiedOn = &now
+	data.Version = &version
+	s.routings[data.ID] = data
+	s.routingOrder = append(s.routingOrder, data.ID)
+
+	writeJSON(w, http.StatusCreated, accounts.AccountRoutingSingle{
+		AccountRoutingData: data,
+		Links:              accounts.Links{Self: accountRoutingsPath + "/" + data.ID},
+	})
+}
+
+// validateRouting checks the fields the API requires, returning what is wrong with them
+func validateRouting(data accounts.AccountRoutingData) string {
+	var failures []string
+	if _, err := uuid.Parse(data.ID); err != nil {
+		failures = append(failures, fmt.Sprintf("id in body must be of type uuid: %q", data.ID))
+	}
+	if _, err := uuid.Parse(data.OrganisationID); err != nil {
+		failures = append(failures, fmt.Sprintf("organisation_id in body must be of type uuid: %q", data.OrganisationID))
+	}
+	if data.Type != "account_routings" {
+		failures = append(failures, fmt.Sprintf("type in body should be one of [account_routings]: %q", data.Type))
+	}
+	if data.Attributes.AccountGenerator == "" {
+		failures = append(failures, "account_generator in body is required")
+	}
+	if data.Attributes.Match == "" {
+		failures = append(failures, "match in body is required")
+	}
+
+	return strings.Join(failures, "\n")
+}
+
+func (s *server) fetchAccountRouting(w http.ResponseWriter, r *http.Request, ids []string) {
+	s.mutex.Lock()
+	data, ok := s.routings[ids[0]]
+	s.mutex.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("record %s does not exist", ids[0]))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, accounts.AccountRoutingSingle{
+		AccountRoutingData: data,
+		Links:              accounts.Links{Self: accountRoutingsPath + "/" + data.ID},
+	})
+}
+
+// listAccountRoutings pages through the account routings in the order they were created
+func (s *server) listAccountRoutings(w http.ResponseWriter, r *http.Request, _ []string) {
+	organisationID := r.URL.Query().Get("filter[organisation_id]")
+
+	s.mutex.Lock()
+	var matching []accounts.AccountRoutingData
+	for _, id := range s.routingOrder {
+		if data := s.routings[id]; organisationID == "" || data.OrganisationID == organisationID {
+			matching = append(matching, data)
+		}
+	}
+	s.mutex.Unlock()
+
+	start, end, links := paginate(r, accountRoutingsPath, len(matching))
+	list := accounts.AccountRoutingList{Links: links}
+	if start < end {
+		page := append([]accounts.AccountRoutingData{}, matching[start:end]...)
+		list.AccountRoutingData = &page
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *server) deleteAccountRouting(w http.ResponseWriter, r *http.Request, ids []string) {
+	version, err := strconv.Atoi(r.URL.Query().Get("version"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid version number")
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	data, ok := s.routings[ids[0]]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("record %s does not exist", ids[0]))
+		return
+	}
+	if data.Version == nil || *data.Version != version {
+		writeError(w, http.StatusConflict, "invalid version")
+		return
+	}
+
+	delete(s.routings, ids[0])
+	for i, id := range s.routingOrder {
+		if id == ids[0] {
+			s.routingOrder = append(s.routingOrder[:i], s.routingOrder[i+1:]...)
+			break
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,126 @@
+package fakeapi
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	accounts "github.com/razvanmuscalu/form3-accounts-client"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func newAccountRouting(organisationID string, match string) accounts.AccountRoutingData {
+	return accounts.NewAccountRoutingData().
+		ID(uuid.New().String()).
+		OrganisationID(organisationID).
+		Attributes(accounts.NewAccountRouting().
+			AccountGenerator(accounts.AccountGeneratorOrganisation).
+			AccountProvisioner(accounts.AccountProvisionerForm3).
+			MatchType(accounts.MatchPrefix).
+			Match(match).
+			Priority(1).
+			Build()).
+		Build()
+}
+
+func TestAccountRoutings(t *testing.T) {
+
+	Convey("Given a fake API and an account routings client", t, func() {
+		api := NewServer().Build()
+		defer api.Close()
+
+		client := accounts.NewAccountRoutingsClient().HTTPClient(http.Client{Timeout: time.Second}).URL(api.URL()).Build()
+		organisationID := uuid.New().String()
+
+		Convey("When I create an account routing", func() {
+			data := newAccountRouting(organisationID, "4003")
+			created, err := client.Create(data)
+
+			Convey("Then it can be fetched with its typed attributes", func() {
+				So(err, ShouldBeNil)
+				So(created.AccountRoutingData.Type, ShouldEqual, "account_routings")
+				So(*created.AccountRoutingData.Version, ShouldEqual, 0)
+
+				fetched, err := client.Fetch(uuid.MustParse(data.ID))
+				So(err, ShouldBeNil)
+				So(fetched.AccountRoutingData.Attributes.AccountGenerator, ShouldEqual, accounts.AccountGeneratorOrganisation)
+				So(fetched.AccountRoutingData.Attributes.AccountProvisioner, ShouldEqual, accounts.AccountProvisionerForm3)
+				So(fetched.AccountRoutingData.Attributes.MatchType, ShouldEqual, accounts.MatchPrefix)
+				So(fetched.AccountRoutingData.Attributes.Match, ShouldEqual, "4003")
+				So(*fetched.AccountRoutingData.Attributes.Priority, ShouldEqual, 1)
+			})
+
+			Convey("And it cannot be created again", func() {
+				_, err := client.Create(data)
+				var apiErr *accounts.APIError
+				So(errors.As(err, &apiErr), ShouldBeTrue)
+				So(apiErr.StatusCode, ShouldEqual, http.StatusConflict)
+			})
+
+			Convey("And it is gone once deleted with its version", func() {
+				_, err := client.Delete(uuid.MustParse(data.ID), 1)
+				So(err, ShouldBeError, "invalid version")
+
+				deleted, err := client.Delete(uuid.MustParse(data.ID), 0)
+				So(err, ShouldBeNil)
+				So(deleted, ShouldBeTrue)
+
+				_, err = client.Fetch(uuid.MustParse(data.ID))
+				So(err.Error(), ShouldEqual, "record "+data.ID+" does not exist")
+			})
+		})
+
+		Convey("When I create an account routing without a generator", func() {
+			data := newAccountRouting(organisationID, "4003")
+			data.Attributes.AccountGenerator = ""
+			_, err := client.Create(data)
+
+			Convey("Then it is rejected before reaching the API", func() {
+				var validationErr *accounts.ValidationError
+				So(errors.As(err, &validationErr), ShouldBeTrue)
+				So(validationErr.Field, ShouldEqual, "AccountGenerator")
+				So(api.Requests(CreateAccountRouting), ShouldEqual, 0)
+			})
+		})
+
+		Convey("When I list the account routings of an organisation by pages", func() {
+			for _, match := range []string{"40", "41", "42"} {
+				_, err := client.Create(newAccountRouting(organisationID, match))
+				So(err, ShouldBeNil)
+			}
+			client.Create(newAccountRouting(uuid.New().String(), "43"))
+
+			filter := &accounts.Filter{OrganisationID: &organisationID}
+			first, err := client.List(&accounts.Page{Number: 0, Size: 2}, filter)
+			So(err, ShouldBeNil)
+			last, err := client.List(&accounts.Page{Number: 1, Size: 2}, filter)
+			So(err, ShouldBeNil)
+
+			Convey("Then the pages hold the routings in the order they were created and link to each other", func() {
+				So(len(*first.AccountRoutingData), ShouldEqual, 2)
+				So((*first.AccountRoutingData)[0].Attributes.Match, ShouldEqual, "40")
+				So(*first.Links.Next, ShouldContainSubstring, "page%5Bnumber%5D=1")
+
+				So(len(*last.AccountRoutingData), ShouldEqual, 1)
+				So((*last.AccountRoutingData)[0].Attributes.Match, ShouldEqual, "42")
+				So(last.Links.Next, ShouldBeNil)
+			})
+		})
+
+		Convey("When a fault is injected on the account routings", func() {
+			api.Inject(ListAccountRoutings, ServerErrors(http.StatusServiceUnavailable).Times(1))
+			retrying := accounts.NewAccountRoutingsClient().HTTPClient(http.Client{Timeout: time.Second}).URL(api.URL()).
+				Retry(accounts.RetryPolicy{Attempts: 2, Backoff: time.Millisecond}).Build()
+			_, err := retrying.List(nil, nil)
+
+			Convey("Then the client retries it", func() {
+				So(err, ShouldBeNil)
+				So(api.Requests(ListAccountRoutings), ShouldEqual, 2)
+			})
+		})
+	})
+}
@@ -0,0 +1,94 @@
+package accounts
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// flightGroup shares a single in-flight call between the concurrent callers asking for the same key
+type flightGroup struct {
+	mutex sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done    chan struct{}
+	value   interface{}
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+func newFlightGroup() *flightGroup {
+	return &flightGroup{calls: make(map[string]*flightCall)}
+}
+
+// detachedContext keeps the values of its parent but neither its deadline nor its cancellation
+type detachedContext struct {
+	parent context.Context
+}
+
+func (dc detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (dc detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (dc detachedContext) Err() error {
+	return nil
+}
+
+func (dc detachedContext) Value(key interface{}) interface{} {
+	return dc.parent.Value(key)
+}
+
+// do calls fn once for all the concurrent callers of the same key and returns its result to each of them
+//
+// fn is not bound to the cancellation of the caller that started it, only to its values, but to a context
+// cancelled once every waiting caller gave up, so that the callers still waiting get the result when the
+// first one leaves. Each caller gets its own deep copy of the result
+func (g *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	g.mutex.Lock()
+	call, ok := g.calls[key]
+	if !ok {
+		callCtx, cancel := context.WithCancel(detachedContext{parent: ctx})
+		call = &flightCall{done: make(chan struct{}), cancel: cancel}
+		g.calls[key] = call
+
+		go func() {
+			call.value, call.err = fn(callCtx)
+
+			g.mutex.Lock()
+			if g.calls[key] == call {
+				delete(g.calls, key)
+			}
+			g.mutex.Unlock()
+
+			cancel()
+			close(call.done)
+		}()
+	}
+	call.waiters++
+	g.mutex.Unlock()
+
+	select {
+	case <-call.done:
+		return deepCopy(call.value), call.err
+	case <-ctx.Done():
+		g.mutex.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			call.cancel()
+			// later callers must not join a call that is being cancelled
+			if g.calls[key] == call {
+				delete(g.calls, key)
+			}
+		}
+		g.mutex.Unlock()
+
+		return nil, ctx.Err()
+	}
+}
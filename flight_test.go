@@ -0,0 +1,219 @@
+package accounts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// blockingServer holds every request until it is released, counting them
+type blockingServer struct {
+	requests uint64
+	arrived  chan struct{}
+	release  chan struct{}
+}
+
+func newBlockingServer() *blockingServer {
+	return &blockingServer{arrived: make(chan struct{}, 100), release: make(chan struct{})}
+}
+
+func (bs *blockingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	atomic.AddUint64(&bs.requests, 1)
+	bs.arrived <- struct{}{}
+	<-bs.release
+
+	if r.URL.Path == path {
+		data := []AccountData{NewAccountData().ID(uuid.New().String()).Build()}
+		json.NewEncoder(w).Encode(List{AccountData: &data})
+		return
+	}
+	json.NewEncoder(w).Encode(Single{AccountData: NewAccountData().ID(r.URL.Path[len(path)+1:]).Build()})
+}
+
+// traceKey is the key of a value carried by the context of a fetch
+type traceKey struct{}
+
+// waitForCallers gives the concurrent callers the time to join the request in flight
+func waitForCallers() {
+	time.Sleep(50 * time.Millisecond)
+}
+
+func TestCoalesceConcurrentRequests(t *testing.T) {
+
+	Convey("Given a client and an API holding requests", t, func() {
+		api := newBlockingServer()
+		server := httptest.NewServer(api)
+		defer server.Close()
+
+		AccountsService := NewClient().HTTPClient(HTTPClient).URL(server.URL).Build()
+		ID := uuid.New()
+
+		Convey("When the same account is fetched concurrently", func() {
+			var wg sync.WaitGroup
+			results := make([]Single, 10)
+			errs := make([]error, 10)
+			for i := range results {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					results[i], errs[i] = AccountsService.Fetch(ID)
+				}(i)
+			}
+			<-api.arrived
+			waitForCallers()
+			close(api.release)
+			wg.Wait()
+
+			Convey("Then the API is called once and every caller gets the account", func() {
+				So(api.requests, ShouldEqual, 1)
+				for i := range results {
+					So(errs[i], ShouldBeNil)
+					So(results[i].AccountData.ID, ShouldEqual, ID.String())
+				}
+			})
+		})
+
+		Convey("When different accounts are fetched concurrently", func() {
+			var wg sync.WaitGroup
+			for i := 0; i < 3; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					AccountsService.Fetch(uuid.New())
+				}()
+			}
+			for i := 0; i < 3; i++ {
+				<-api.arrived
+			}
+			close(api.release)
+			wg.Wait()
+
+			Convey("Then the API is called for each of them", func() {
+				So(api.requests, ShouldEqual, 3)
+			})
+		})
+
+		Convey("When the same page is listed concurrently", func() {
+			var wg sync.WaitGroup
+			results := make([]List, 5)
+			for i := range results {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					results[i], _ = AccountsService.List(&Page{Number: 1, Size: 10}, nil)
+				}(i)
+			}
+			<-api.arrived
+			waitForCallers()
+			close(api.release)
+			wg.Wait()
+
+			Convey("Then the API is called once and every caller gets the same page", func() {
+				So(api.requests, ShouldEqual, 1)
+				for i := range results {
+					So((*results[i].AccountData)[0].ID, ShouldEqual, (*results[0].AccountData)[0].ID)
+				}
+			})
+		})
+
+		Convey("When a caller changes the page it got from a list made concurrently", func() {
+			var wg sync.WaitGroup
+			results := make([]List, 2)
+			for i := range results {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					results[i], _ = AccountsService.List(&Page{Number: 1, Size: 10}, nil)
+				}(i)
+			}
+			<-api.arrived
+			waitForCallers()
+			close(api.release)
+			wg.Wait()
+			id := (*results[1].AccountData)[0].ID
+			(*results[0].AccountData)[0].ID = "changed"
+
+			Convey("Then the page of the other caller is unchanged", func() {
+				So(api.requests, ShouldEqual, 1)
+				So((*results[1].AccountData)[0].ID, ShouldEqual, id)
+			})
+		})
+
+		Convey("When an account is fetched with a context holding a value", func() {
+			var seen interface{}
+			recording := func(next Sender) Sender {
+				return func(req *http.Request) (*http.Response, error) {
+					seen = req.Context().Value(traceKey{})
+					return next(req)
+				}
+			}
+			TracedService := NewClient().HTTPClient(HTTPClient).URL(server.URL).Middleware(recording).Build()
+			close(api.release)
+			_, err := TracedService.FetchWithContext(context.WithValue(context.Background(), traceKey{}, "trace"), ID)
+
+			Convey("Then the request to the API keeps the value", func() {
+				So(err, ShouldBeNil)
+				So(seen, ShouldEqual, "trace")
+			})
+		})
+
+		Convey("When the first caller gives up while another one is still waiting", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			first := make(chan error, 1)
+			go func() {
+				_, err := AccountsService.FetchWithContext(ctx, ID)
+				first <- err
+			}()
+			<-api.arrived
+
+			second := make(chan Single, 1)
+			go func() {
+				resp, _ := AccountsService.Fetch(ID)
+				second <- resp
+			}()
+			waitForCallers()
+			cancel()
+			firstErr := <-first
+			close(api.release)
+
+			Convey("Then only the first caller fails and the other one gets the account", func() {
+				So(firstErr, ShouldBeError, "An error has occured while fetching account")
+				So(errors.Is(firstErr, context.Canceled), ShouldBeTrue)
+				So((<-second).AccountData.ID, ShouldEqual, ID.String())
+				So(api.requests, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When every caller gives up", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error, 1)
+			go func() {
+				_, err := AccountsService.FetchWithContext(ctx, ID)
+				done <- err
+			}()
+			<-api.arrived
+			cancel()
+			err := <-done
+
+			close(api.release)
+			resp, fetchErr := AccountsService.Fetch(ID)
+
+			Convey("Then the caller fails and a later fetch makes a new request", func() {
+				So(errors.Is(err, context.Canceled), ShouldBeTrue)
+				So(fetchErr, ShouldBeNil)
+				So(resp.AccountData.ID, ShouldEqual, ID.String())
+				So(api.requests, ShouldEqual, 2)
+			})
+		})
+	})
+}
@@ -0,0 +1,27 @@
+package accounts
+
+import "fmt"
+
+// FileFormat is the format of the files read by an Importer and written by an Exporter
+type FileFormat int
+
+const (
+	// CSV files have a header row naming their columns
+	CSV FileFormat = iota
+	// JSONLines files hold one account per line
+	JSONLines
+	// Parquet files hold the accounts column by column; they can only be exported
+	Parquet
+)
+
+func (f FileFormat) String() string {
+	switch f {
+	case CSV:
+		return "csv"
+	case JSONLines:
+		return "jsonl"
+	case Parquet:
+		return "parquet"
+	}
+	return fmt.Sprintf("FileFormat(%d)", int(f))
+}
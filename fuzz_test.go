@@ -0,0 +1,241 @@
+package accounts
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/quick"
+	"time"
+	"unicode/utf8"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// checkListURL tells how the URL built for page and filter fails to parse back to them
+func checkListURL(page *Page, filter *Filter) error {
+	built := buildListURL("http://localhost:8080", page, filter)
+	parsed, err := url.Parse(built)
+	if err != nil {
+		return fmt.Errorf("%s does not parse: %v", built, err)
+	}
+	if parsed.Path != path {
+		return fmt.Errorf("%s has path %s", built, parsed.Path)
+	}
+
+	query, err := url.ParseQuery(parsed.RawQuery)
+	if err != nil {
+		return fmt.Errorf("%s has a query that does not parse: %v", built, err)
+	}
+	want := url.Values{}
+	if page != nil {
+		want.Set("page[number]", strconv.Itoa(page.Number))
+		if page.Size > 0 {
+			want.Set("page[size]", strconv.Itoa(page.Size))
+		}
+	}
+	if filter != nil && filter.OrganisationID != nil {
+		want.Set("filter[organisation_id]", *filter.OrganisationID)
+	}
+	if len(want) == 0 {
+		want = nil
+		if len(query) == 0 {
+			query = nil
+		}
+	}
+	if !reflect.DeepEqual(query, want) {
+		return fmt.Errorf("%s has query %v, expected %v", built, query, want)
+	}
+
+	return nil
+}
+
+func TestListURLProperties(t *testing.T) {
+
+	Convey("When list URLs are built for random pages and filters", t, func() {
+		property := func(number int, size int, organisationID string, paged bool, filtered bool) bool {
+			var page *Page
+			if paged {
+				page = &Page{Number: number, Size: size}
+			}
+			var filter *Filter
+			if filtered {
+				filter = &Filter{OrganisationID: &organisationID}
+			}
+			if err := checkListURL(page, filter); err != nil {
+				t.Log(err)
+				return false
+			}
+			return true
+		}
+
+		Convey("Then their query parses back to the page and filter", func() {
+			So(quick.Check(property, &quick.Config{MaxCount: 1000}), ShouldBeNil)
+		})
+	})
+
+	Convey("When a list URL is built for a filter only", t, func() {
+		organisationID := "org"
+		built := buildListURL("", nil, &Filter{OrganisationID: &organisationID})
+
+		Convey("Then its first parameter is written once", func() {
+			So(built, ShouldEqual, path+"?filter[organisation_id]=org")
+		})
+	})
+}
+
+func FuzzBuildListURL(f *testing.F) {
+	f.Add(0, 100, "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c", true, true)
+	f.Add(-1, 0, "", true, false)
+	f.Add(3, 10, "a&b=c#d?e f%", false, true)
+	f.Add(1, 1, "page[number]=2", true, true)
+
+	f.Fuzz(func(t *testing.T, number int, size int, organisationID string, paged bool, filtered bool) {
+		if !utf8.ValidString(organisationID) {
+			t.Skip()
+		}
+
+		var page *Page
+		if paged {
+			page = &Page{Number: number, Size: size}
+		}
+		var filter *Filter
+		if filtered {
+			filter = &Filter{OrganisationID: &organisationID}
+		}
+		if err := checkListURL(page, filter); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+// referenceValidation validates the attributes checked by validateAccount without regular expressions,
+// returning the name of the first invalid field
+func referenceValidation(a Account) string {
+	isUpper := func(r byte) bool { return r >= 'A' && r <= 'Z' }
+	isDigit := func(r byte) bool { return r >= '0' && r <= '9' }
+	all := func(value string, valid func(byte) bool) bool {
+		for i := 0; i < len(value); i++ {
+			if !valid(value[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if a.BIC != nil {
+		bic := *a.BIC
+		if (len(bic) != 8 && len(bic) != 11) || !all(bic[:6], isUpper) || !all(bic[6:], func(r byte) bool { return isUpper(r) || isDigit(r) }) {
+			return "BIC"
+		}
+	}
+	if a.AccountClassification != nil && *a.AccountClassification != "Personal" && *a.AccountClassification != "Business" {
+		return "AccountClassification"
+	}
+	if a.BankID != nil && (len(*a.BankID) > 16 || !all(*a.BankID, func(r byte) bool { return isUpper(r) || isDigit(r) })) {
+		return "BankID"
+	}
+	if a.BaseCurrency != nil && (len(*a.BaseCurrency) != 3 || !all(*a.BaseCurrency, isUpper)) {
+		return "BaseCurrency"
+	}
+	if len(a.Country) != 2 || !all(a.Country, isUpper) {
+		return "Country"
+	}
+	if a.AlternativeBankAccountNames != nil && len(*a.AlternativeBankAccountNames) > 3 {
+		return "AlternativeBankAccountNames"
+	}
+
+	return ""
+}
+
+func FuzzValidateAccount(f *testing.F) {
+	f.Add("GB", "GBP", "400300", "NWBKGB22", "Personal", "Sam;Samantha", true, true, true, true, true)
+	f.Add("GBR", "GBPP", "aStringLongerThanElevenCharacters", "NWBKGB2", "unknown", "a;b;c;d", true, true, true, true, true)
+	f.Add("gb", "gbp", "4003\n", "NWBKGB22XXX\n", "Personal\n", "", true, true, true, true, false)
+	f.Add("", "", "", "", "", "", false, false, false, false, false)
+
+	f.Fuzz(func(t *testing.T, country string, baseCurrency string, bankID string, bic string, classification string, names string,
+		hasBaseCurrency bool, hasBankID bool, hasBIC bool, hasClassification bool, hasNames bool) {
+
+		builder := NewAccount().Country(country)
+		if hasBaseCurrency {
+			builder.BaseCurrency(baseCurrency)
+		}
+		if hasBankID {
+			builder.BankID(bankID)
+		}
+		if hasBIC {
+			builder.BIC(bic)
+		}
+		if hasClassification {
+			builder.AccountClassification(classification)
+		}
+		if hasNames {
+			builder.AlternativeBankAccountNames(strings.Split(names, ";"))
+		}
+		a := builder.Build()
+
+		want := referenceValidation(a)
+		err := validateAccount(a)
+		switch {
+		case want == "" && err != nil:
+			t.Errorf("validateAccount rejected valid attributes %+v: %v", a, err)
+		case want != "" && err == nil:
+			t.Errorf("validateAccount accepted attributes %+v with invalid %s", a, want)
+		case want != "" && err.(*ValidationError).Field != want:
+			t.Errorf("validateAccount reported %s instead of %s for %+v", err.(*ValidationError).Field, want, a)
+		}
+	})
+}
+
+func FuzzAccountDataJSONRoundTrip(f *testing.F) {
+	f.Add("ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c", "GB", "400300", "Samantha Holder", "Sam;Samantha", 0, int64(1600000000123456789), true)
+	f.Add("", "", "", "", "", "", -1, int64(0), false)
+	f.Add("\"quoted\"", "<html>&amp;", "é", "\\", "\t\n", ";", 42, int64(-1), true)
+
+	f.Fuzz(func(t *testing.T, id string, organisationID string, country string, bankID string, name string, names string,
+		version int, created int64, joint bool) {
+
+		for _, value := range []string{id, organisationID, country, bankID, name, names} {
+			if !utf8.ValidString(value) {
+				t.Skip()
+			}
+		}
+
+		createdOn := time.Unix(0, created).UTC()
+		data := NewAccountData().
+			ID(id).
+			OrganisationID(organisationID).
+			Type(accountsType).
+			Version(version).
+			CreatedOn(createdOn).
+			Attributes(NewAccount().
+				Country(country).
+				BankID(bankID).
+				BankAccountName(name).
+				AlternativeBankAccountNames(strings.Split(names, ";")).
+				JointAccount(joint).
+				Build()).
+			Build()
+
+		encoded, err := json.Marshal(data)
+		if err != nil {
+			t.Fatalf("%+v does not encode: %v", data, err)
+		}
+		var decoded AccountData
+		if err := json.Unmarshal(encoded, &decoded); err != nil {
+			t.Fatalf("%s does not decode: %v", encoded, err)
+		}
+
+		if decoded.CreatedOn == nil || !decoded.CreatedOn.Equal(createdOn) {
+			t.Errorf("created_on %v came back as %v", createdOn, decoded.CreatedOn)
+		}
+		data.CreatedOn, decoded.CreatedOn = nil, nil
+		if !reflect.DeepEqual(decoded, data) {
+			t.Errorf("%+v came back as %+v", data, decoded)
+		}
+	})
+}
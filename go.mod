@@ -1,8 +1,15 @@
 module github.com/razvanmuscalu/form3-accounts-client
 
-go 1.14
+go 1.18
 
 require (
 	github.com/google/uuid v1.1.1
 	github.com/smartystreets/goconvey v1.6.4
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/gopherjs/gopherjs v0.0.0-20181017120253-0766667cb4d1 // indirect
+	github.com/jtolds/gls v4.20.0+incompatible // indirect
+	github.com/smartystreets/assertions v0.0.0-20180927180507-b2de0cb4f26d // indirect
 )
@@ -0,0 +1,473 @@
+package accounts
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// ImportStatus is the outcome of importing a single row
+type ImportStatus string
+
+const (
+	// ImportCreated means the account has been created
+	ImportCreated ImportStatus = "created"
+	// ImportDuplicate means an account with the same ID already exists
+	ImportDuplicate ImportStatus = "duplicate"
+	// ImportValidationError means the row was rejected before being sent to the API
+	ImportValidationError ImportStatus = "validation_error"
+	// ImportAPIError means the API could not be reached or rejected the account
+	ImportAPIError ImportStatus = "api_error"
+)
+
+// done tells whether a row with this status has to be imported again when resuming
+func (s ImportStatus) done() bool {
+	return s == ImportCreated || s == ImportDuplicate
+}
+
+// ImportResult is the outcome of importing a row, written as one line of the JSON Lines report
+type ImportResult struct {
+	Row    int          `json:"row"`
+	ID     string       `json:"id,omitempty"`
+	Status ImportStatus `json:"status"`
+	Error  string       `json:"error,omitempty"`
+}
+
+// ImportSummary counts the rows of an import per outcome
+//
+// Rows copied from the previous report when resuming are counted under their outcome and under Resumed
+type ImportSummary struct {
+	Created         int
+	Duplicate       int
+	ValidationError int
+	APIError        int
+	Resumed         int
+}
+
+func (s *ImportSummary) add(result ImportResult) {
+	switch result.Status {
+	case ImportCreated:
+		s.Created++
+	case ImportDuplicate:
+		s.Duplicate++
+	case ImportValidationError:
+		s.ValidationError++
+	case ImportAPIError:
+		s.APIError++
+	}
+}
+
+// Importer creates accounts in bulk from a CSV or JSON Lines file
+type Importer interface {
+	// Import creates the accounts read from input and writes one result per row to report
+	Import(input io.Reader, report io.Writer) (ImportSummary, error)
+	// Resume imports again the rows of input that were not created or found to be duplicates in
+	// the previous report, copying the results of the other rows so that the new report is complete
+	Resume(input io.Reader, previous io.Reader, report io.Writer) (ImportSummary, error)
+}
+
+type importer struct {
+	client         ContextClient
+	format         FileFormat
+	mapping        map[string]string
+	organisationID string
+	workers        int
+}
+
+// ImporterBuilder is used to create an Importer
+type ImporterBuilder interface {
+	Client(ContextClient) ImporterBuilder
+	Format(FileFormat) ImporterBuilder
+	Mapping(map[string]string) ImporterBuilder
+	OrganisationID(string) ImporterBuilder
+	Workers(int) ImporterBuilder
+	Build() Importer
+}
+
+type importerBuilder struct {
+	client         ContextClient
+	format         FileFormat
+	mapping        map[string]string
+	organisationID string
+	workers        int
+}
+
+func (ib *importerBuilder) Client(value ContextClient) ImporterBuilder {
+	ib.client = value
+	return ib
+}
+
+func (ib *importerBuilder) Format(value FileFormat) ImporterBuilder {
+	ib.format = value
+	return ib
+}
+
+// Mapping maps CSV column names to account fields named as in the API, e.g. "Sort Code" to "bank_id"
+//
+// Columns that are not mapped are read as the field of the same name
+func (ib *importerBuilder) Mapping(value map[string]string) ImporterBuilder {
+	ib.mapping = value
+	return ib
+}
+
+// OrganisationID is used for the rows that do not hold an organisation ID
+func (ib *importerBuilder) OrganisationID(value string) ImporterBuilder {
+	ib.organisationID = value
+	return ib
+}
+
+// Workers is the number of accounts created concurrently, 1 when not set
+func (ib *importerBuilder) Workers(value int) ImporterBuilder {
+	ib.workers = value
+	return ib
+}
+
+func (ib *importerBuilder) Build() Importer {
+	workers := ib.workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	return &importer{
+		client:         ib.client,
+		format:         ib.format,
+		mapping:        ib.mapping,
+		organisationID: ib.organisationID,
+		workers:        workers,
+	}
+}
+
+// NewImporter is used to create an ImporterBuilder
+func NewImporter() ImporterBuilder {
+	return &importerBuilder{}
+}
+
+// importRow is a row of the input file along with the account read from it
+type importRow struct {
+	number int
+	data   AccountData
+	err    error
+}
+
+func (i importer) Import(input io.Reader, report io.Writer) (ImportSummary, error) {
+	return i.Resume(input, nil, report)
+}
+
+func (i importer) Resume(input io.Reader, previous io.Reader, report io.Writer) (ImportSummary, error) {
+	done, err := readImportReport(previous)
+	if err != nil {
+		return ImportSummary{}, err
+	}
+
+	rows, err := i.read(input)
+	if err != nil {
+		return ImportSummary{}, err
+	}
+
+	var summary ImportSummary
+	encoder := json.NewEncoder(report)
+	write := func(result ImportResult) error {
+		summary.add(result)
+		if err := encoder.Encode(result); err != nil {
+			return fmt.Errorf("An error has occured while writing import report")
+		}
+		return nil
+	}
+
+	var pending []importRow
+	for _, row := range rows {
+		if result, ok := done[row.number]; ok && result.Status.done() {
+			summary.Resumed++
+			if err := write(result); err != nil {
+				return summary, err
+			}
+			continue
+		}
+		if result, ok := done[row.number]; ok && row.data.ID == "" {
+			row.data.ID = result.ID
+		}
+		if row.data.ID == "" {
+			row.data.ID = uuid.New().String()
+		}
+
+		if row.err == nil {
+			row.err = validateAccount(row.data.Attributes)
+		}
+		if row.err != nil {
+			if err := write(ImportResult{Row: row.number, ID: row.data.ID, Status: ImportValidationError, Error: row.err.Error()}); err != nil {
+				return summary, err
+			}
+			continue
+		}
+
+		pending = append(pending, row)
+	}
+
+	// keep draining the results after a failed write so that no worker is left blocked
+	var writeErr error
+	for result := range i.create(pending) {
+		if writeErr == nil {
+			writeErr = write(result)
+		}
+	}
+
+	return summary, writeErr
+}
+
+// create sends the rows to the API from a bounded pool of workers
+func (i importer) create(rows []importRow) <-chan ImportResult {
+	jobs := make(chan importRow)
+	results := make(chan ImportResult)
+
+	var wg sync.WaitGroup
+	for w := 0; w < i.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for row := range jobs {
+				results <- i.createRow(row)
+			}
+		}()
+	}
+
+	go func() {
+		for _, row := range rows {
+			jobs <- row
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+func (i importer) createRow(row importRow) ImportResult {
+	result := ImportResult{Row: row.number, ID: row.data.ID}
+
+	_, err := i.client.Create(row.data)
+	var apiErr *APIError
+	var validationErr *ValidationError
+	switch {
+	case err == nil:
+		result.Status = ImportCreated
+	case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict:
+		result.Status = ImportDuplicate
+		result.Error = err.Error()
+	case errors.As(err, &validationErr):
+		result.Status = ImportValidationError
+		result.Error = err.Error()
+	default:
+		result.Status = ImportAPIError
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+func readImportReport(report io.Reader) (map[int]ImportResult, error) {
+	results := make(map[int]ImportResult)
+	if report == nil {
+		return results, nil
+	}
+
+	decoder := json.NewDecoder(report)
+	for {
+		var result ImportResult
+		if err := decoder.Decode(&result); err == io.EOF {
+			return results, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("An error has occured while reading import report")
+		}
+		results[result.Row] = result
+	}
+}
+
+func (i importer) read(input io.Reader) ([]importRow, error) {
+	var rows []importRow
+	var err error
+	switch i.format {
+	case CSV:
+		rows, err = i.readCSV(input)
+	case JSONLines:
+		rows, err = i.readJSONLines(input)
+	default:
+		return nil, fmt.Errorf("Invalid import format [%s]", i.format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for r := range rows {
+		if rows[r].data.OrganisationID == "" {
+			rows[r].data.OrganisationID = i.organisationID
+		}
+		if rows[r].data.Type == "" {
+			rows[r].data.Type = accountsType
+		}
+	}
+
+	return rows, nil
+}
+
+func (i importer) readJSONLines(input io.Reader) ([]importRow, error) {
+	var rows []importRow
+
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for number := 1; scanner.Scan(); number++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		row := importRow{number: number}
+		var members map[string]json.RawMessage
+		if err := json.Unmarshal(line, &members); err != nil {
+			row.err = &ValidationError{Message: fmt.Sprintf("Invalid JSON on line %d", number)}
+		} else if data, ok := members["data"]; ok {
+			line = data
+		}
+		if row.err == nil {
+			if err := json.Unmarshal(line, &row.data); err != nil {
+				row.err = &ValidationError{Message: fmt.Sprintf("Invalid JSON on line %d", number)}
+			}
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("An error has occured while reading import file")
+	}
+
+	return rows, nil
+}
+
+func (i importer) readCSV(input io.Reader) ([]importRow, error) {
+	reader := csv.NewReader(input)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("An error has occured while reading import file header")
+	}
+
+	fields := make([]string, len(header))
+	for c, column := range header {
+		fields[c] = strings.TrimSpace(column)
+		if field, ok := i.mapping[fields[c]]; ok {
+			fields[c] = field
+		}
+		if !importFields[fields[c]] && !accountFields[fields[c]] {
+			return nil, fmt.Errorf("Invalid import column [%s]", column)
+		}
+	}
+
+	var rows []importRow
+	for number := 1; ; number++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				rows = append(rows, importRow{number: number, err: &ValidationError{Message: fmt.Sprintf("Invalid CSV on row %d", number)}})
+				continue
+			}
+			return nil, fmt.Errorf("An error has occured while reading import file")
+		}
+
+		row := importRow{number: number}
+		row.data, row.err = csvAccountData(fields, record)
+		rows = append(rows, row)
+	}
+}
+
+// importFields are the account data fields that can be read from a CSV column, on top of the attributes
+var importFields = map[string]bool{"id": true, "organisation_id": true, "type": true}
+
+// csvAccountData builds account data from a CSV record by converting it to the JSON the API would send
+func csvAccountData(fields []string, record []string) (AccountData, error) {
+	if len(record) != len(fields) {
+		return AccountData{}, &ValidationError{Message: fmt.Sprintf("Invalid number of columns [%d]", len(record))}
+	}
+
+	data := make(map[string]interface{})
+	attributes := make(map[string]interface{})
+	for c, field := range fields {
+		value := strings.TrimSpace(record[c])
+		if value == "" {
+			continue
+		}
+		if importFields[field] {
+			data[field] = value
+			continue
+		}
+
+		converted, err := csvValue(field, value)
+		if err != nil {
+			return AccountData{}, err
+		}
+		attributes[field] = converted
+	}
+	data["attributes"] = attributes
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return AccountData{}, err
+	}
+
+	var result AccountData
+	if err := json.Unmarshal(encoded, &result); err != nil {
+		return AccountData{}, &ValidationError{Message: "Invalid row"}
+	}
+
+	return result, nil
+}
+
+// csvAttributeKinds maps the JSON name of every account attribute to the kind of value it holds
+var csvAttributeKinds = func() map[string]reflect.Kind {
+	kinds := make(map[string]reflect.Kind)
+	t := reflect.TypeOf(Account{})
+	for f := 0; f < t.NumField(); f++ {
+		name := strings.Split(t.Field(f).Tag.Get("json"), ",")[0]
+		kind := t.Field(f).Type.Kind()
+		if kind == reflect.Ptr {
+			kind = t.Field(f).Type.Elem().Kind()
+		}
+		kinds[name] = kind
+	}
+	return kinds
+}()
+
+// csvValue converts a CSV cell to the type of the attribute; lists are separated by semicolons
+func csvValue(field string, value string) (interface{}, error) {
+	switch csvAttributeKinds[field] {
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, &ValidationError{Field: field, Message: fmt.Sprintf("Invalid %s [%s]", field, value)}
+		}
+		return parsed, nil
+	case reflect.Slice:
+		var values []string
+		for _, v := range strings.Split(value, ";") {
+			values = append(values, strings.TrimSpace(v))
+		}
+		return values, nil
+	}
+
+	return value, nil
+}
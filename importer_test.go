@@ -0,0 +1,156 @@
+package accounts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// importServer creates accounts, answering with a conflict for known IDs and an error for bank ID FAIL
+func importServer() *httptest.Server {
+	var mutex sync.Mutex
+	created := make(map[string]bool)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request accountDataRequest
+		json.NewDecoder(r.Body).Decode(&request)
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		switch {
+		case created[request.AccountData.ID]:
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprint(w, `{"error_message": "Account cannot be created as it violates a duplicate constraint"}`)
+		case request.AccountData.Attributes.BankID != nil && *request.AccountData.Attributes.BankID == "FAIL":
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"error_message": "internal error"}`)
+		default:
+			created[request.AccountData.ID] = true
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(Single{AccountData: request.AccountData})
+		}
+	}))
+}
+
+func readReport(report *bytes.Buffer) map[int]ImportResult {
+	results, _ := readImportReport(report)
+	return results
+}
+
+func TestImportAccountsFromCSV(t *testing.T) {
+
+	Convey("Given a CSV file with custom column names", t, func() {
+		server := importServer()
+		defer server.Close()
+
+		input := strings.Join([]string{
+			"Account ID,Country,Sort Code,Names,joint_account",
+			"ad27e265-9605-4b4b-a0e5-3003ea9cc4dc,GB,400300,Jane;Janet,true",
+			"ad27e265-9605-4b4b-a0e5-3003ea9cc4dc,GB,400300,,false",
+			",GBR,400300,,",
+			",GB,FAIL,,",
+			",GB,400300,,maybe",
+		}, "\n")
+
+		Importer := NewImporter().
+			Client(NewClient().HTTPClient(HTTPClient).URL(server.URL).Build()).
+			Format(CSV).
+			Mapping(map[string]string{"Account ID": "id", "Country": "country", "Sort Code": "bank_id", "Names": "alternative_bank_account_names"}).
+			OrganisationID(OrganisationID).
+			Workers(1).
+			Build()
+
+		Convey("When I import it", func() {
+			var report bytes.Buffer
+			summary, err := Importer.Import(strings.NewReader(input), &report)
+
+			Convey("Then every row is accounted for in the summary", func() {
+				So(err, ShouldBeNil)
+				So(summary, ShouldResemble, ImportSummary{Created: 1, Duplicate: 1, ValidationError: 2, APIError: 1})
+			})
+
+			Convey("And the report holds the outcome of every row", func() {
+				results := readReport(&report)
+				So(results[1].Status, ShouldEqual, ImportCreated)
+				So(results[1].ID, ShouldEqual, "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc")
+				So(results[2].Status, ShouldEqual, ImportDuplicate)
+				So(results[3].Status, ShouldEqual, ImportValidationError)
+				So(results[3].Error, ShouldEqual, "Invalid Country [GBR]")
+				So(results[4].Status, ShouldEqual, ImportAPIError)
+				So(results[4].Error, ShouldEqual, "internal error")
+				So(results[5].Error, ShouldEqual, "Invalid joint_account [maybe]")
+			})
+
+		})
+
+	})
+
+}
+
+func TestImportAccountsFromCSVWithUnknownColumn(t *testing.T) {
+
+	Convey("When I import a CSV file with a column that is not an account field", t, func() {
+		Importer := NewImporter().Format(CSV).Build()
+
+		_, err := Importer.Import(strings.NewReader("country,colour\nGB,blue\n"), &bytes.Buffer{})
+
+		Convey("Then an appropriate error is propagated to the caller", func() {
+			So(err.Error(), ShouldEqual, "Invalid import column [colour]")
+		})
+
+	})
+
+}
+
+func TestResumeImportOfAccountsFromJSONLines(t *testing.T) {
+
+	Convey("Given I imported a JSON Lines file where one row failed", t, func() {
+		server := importServer()
+		defer server.Close()
+
+		input := strings.Join([]string{
+			`{"data": {"id": "1d3b4d1e-6b39-4c36-9b8b-0b8e3b3b2b1a", "attributes": {"country": "GB"}}}`,
+			`{"attributes": {"country": "GB", "bank_id": "FAIL"}}`,
+			`{"id": "5a2e3c57-4fd0-4f0b-9a57-5d8d4a2a8e45", "attributes": {"country": "FR"}}`,
+		}, "\n")
+
+		Importer := NewImporter().
+			Client(NewClient().HTTPClient(HTTPClient).URL(server.URL).Build()).
+			Format(JSONLines).
+			OrganisationID(OrganisationID).
+			Workers(3).
+			Build()
+
+		var first bytes.Buffer
+		summary, _ := Importer.Import(strings.NewReader(input), &first)
+		So(summary, ShouldResemble, ImportSummary{Created: 2, APIError: 1})
+		failedID := readReport(bytes.NewBuffer(first.Bytes()))[2].ID
+
+		Convey("When I resume the import once the row is fixed", func() {
+			fixed := strings.Replace(input, "FAIL", "400300", 1)
+
+			var second bytes.Buffer
+			summary, err := Importer.Resume(strings.NewReader(fixed), &first, &second)
+
+			Convey("Then only the failed row is imported again, with the same ID", func() {
+				So(err, ShouldBeNil)
+				So(summary, ShouldResemble, ImportSummary{Created: 3, Resumed: 2})
+
+				results := readReport(&second)
+				So(len(results), ShouldEqual, 3)
+				So(results[2].Status, ShouldEqual, ImportCreated)
+				So(results[2].ID, ShouldEqual, failedID)
+			})
+
+		})
+
+	})
+
+}
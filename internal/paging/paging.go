@@ -0,0 +1,69 @@
+// Package paging pages through the resources held by the in-memory APIs of the fakeapi and accountsmock
+// packages, the way the Form3 API does
+package paging
+
+import (
+	"net/url"
+	"strconv"
+
+	accounts "github.com/razvanmuscalu/form3-accounts-client"
+)
+
+// DefaultSize is the size of a page when none is requested
+const DefaultSize = 100
+
+// Paginate returns the bounds of a page among total resources, along with its links to listPath
+//
+// A negative number stands for the first page and a size below one for DefaultSize. The links keep the
+// filter parameters; unlike the real API, which links to the first and last pages by name, they link to
+// them by number
+func Paginate(listPath string, number int, size int, total int, filter url.Values) (int, int, accounts.Links) {
+	if number < 0 {
+		number = 0
+	}
+	if size < 1 {
+		size = DefaultSize
+	}
+
+	start, end := number*size, number*size+size
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+
+	last := 0
+	if total > 0 {
+		last = (total - 1) / size
+	}
+	link := func(number int) *string {
+		values := url.Values{}
+		for name, value := range filter {
+			values[name] = value
+		}
+		values.Set("page[number]", strconv.Itoa(number))
+		values.Set("page[size]", strconv.Itoa(size))
+		value := listPath + "?" + values.Encode()
+		return &value
+	}
+
+	links := accounts.Links{Self: *link(number), First: link(0), Last: link(last)}
+	if number < last {
+		links.Next = link(number + 1)
+	}
+	if number > 0 {
+		links.Prev = link(number - 1)
+	}
+
+	return start, end, links
+}
+
+// OrganisationFilter returns the filter parameters of a list of the resources of an organisation, none
+// when organisationID is empty
+func OrganisationFilter(organisationID string) url.Values {
+	if organisationID == "" {
+		return nil
+	}
+	return url.Values{"filter[organisation_id]": {organisationID}}
+}
@@ -0,0 +1,63 @@
+// Package patch encodes the attributes of a partial update and applies them the way the Form3 API does, for
+// the library and for its test doubles answering updates
+package patch
+
+import "encoding/json"
+
+// Attributes encodes attributes as an update sends them, leaving out the members named by omit when they
+// are empty strings, as an update may not change them, e.g. the country of an account
+func Attributes[T any](attributes T, omit ...string) (json.RawMessage, error) {
+	encoded, err := json.Marshal(attributes)
+	if err != nil || len(omit) == 0 {
+		return encoded, err
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &members); err != nil {
+		return nil, err
+	}
+	omitted := false
+	for _, name := range omit {
+		if string(members[name]) == `""` {
+			delete(members, name)
+			omitted = true
+		}
+	}
+	if !omitted {
+		return encoded, nil
+	}
+
+	return json.Marshal(members)
+}
+
+// Merge returns current with the attributes of patch, a JSON object of the attributes sent by an update, as
+// the API does when updating a resource
+func Merge[T any](current T, patch json.RawMessage) (T, error) {
+	encoded, err := json.Marshal(current)
+	if err != nil {
+		return current, err
+	}
+	var merged map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &merged); err != nil {
+		return current, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &fields); err != nil {
+		return current, err
+	}
+	for name, value := range fields {
+		merged[name] = value
+	}
+
+	encoded, err = json.Marshal(merged)
+	if err != nil {
+		return current, err
+	}
+	var patched T
+	if err := json.Unmarshal(encoded, &patched); err != nil {
+		return current, err
+	}
+
+	return patched, nil
+}
@@ -0,0 +1,35 @@
+// Package validation validates resources for the test doubles of the library as its clients do before sending
+// them, without the library exporting its validation; the library registers it when initialised
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	mutex      sync.RWMutex
+	validators = map[reflect.Type]interface{}{}
+)
+
+// Register sets how the resources of type T are validated, those of an update being partial
+func Register[T any](validate func(value T, partial bool) error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	validators[reflect.TypeOf((*T)(nil)).Elem()] = validate
+}
+
+// Validate validates a resource as its client does before sending it, panicking when its type has no
+// validation registered
+func Validate[T any](value T, partial bool) error {
+	mutex.RLock()
+	validate, ok := validators[reflect.TypeOf((*T)(nil)).Elem()]
+	mutex.RUnlock()
+	if !ok {
+		panic(fmt.Sprintf("no validation registered for %T", value))
+	}
+
+	return validate.(func(T, bool) error)(value, partial)
+}
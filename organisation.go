@@ -0,0 +1,135 @@
+package accounts
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const organisationsType = "organisations"
+
+type Organisation struct {
+	Name string `json:"name"`
+
+	// Extra holds the attributes returned by the API that this library does not know about
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// OrganisationBuilder returns a builder for Organisation struct
+type OrganisationBuilder interface {
+	Name(string) OrganisationBuilder
+	Build() Organisation
+}
+
+type organisationBuilder struct {
+	name string
+}
+
+func (ob *organisationBuilder) Name(value string) OrganisationBuilder {
+	ob.name = value
+	return ob
+}
+
+func (ob *organisationBuilder) Build() Organisation {
+	return Organisation{Name: ob.name}
+}
+
+// NewOrganisation is used to create an OrganisationBuilder
+func NewOrganisation() OrganisationBuilder {
+	return &organisationBuilder{}
+}
+
+type OrganisationData struct {
+	ID             string       `json:"id"`
+	OrganisationID string       `json:"organisation_id,omitempty"`
+	Type           string       `json:"type"`
+	CreatedOn      *time.Time   `json:"created_on,omitempty"`
+	ModifiedOn     *time.Time   `json:"modified_on,omitempty"`
+	Version        *int         `json:"version,omitempty"`
+	Attributes     Organisation `json:"attributes"`
+
+	// Extra holds the fields returned by the API that this library does not know about
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// AccountsFilter returns the filter listing the accounts of the organisation
+func (od OrganisationData) AccountsFilter() *Filter {
+	id := od.ID
+	return &Filter{OrganisationID: &id}
+}
+
+// OrganisationDataBuilder returns a builder for OrganisationData struct
+type OrganisationDataBuilder interface {
+	ID(string) OrganisationDataBuilder
+	OrganisationID(string) OrganisationDataBuilder
+	Type(string) OrganisationDataBuilder
+	Version(int) OrganisationDataBuilder
+	Attributes(Organisation) OrganisationDataBuilder
+	Build() OrganisationData
+}
+
+type organisationDataBuilder struct {
+	id                   string
+	organisationID       string
+	organisationDataType string
+	version              *int
+	attributes           Organisation
+}
+
+func (ob *organisationDataBuilder) ID(value string) OrganisationDataBuilder {
+	ob.id = value
+	return ob
+}
+
+// OrganisationID is the parent of the organisation, if any
+func (ob *organisationDataBuilder) OrganisationID(value string) OrganisationDataBuilder {
+	ob.organisationID = value
+	return ob
+}
+
+func (ob *organisationDataBuilder) Type(value string) OrganisationDataBuilder {
+	ob.organisationDataType = value
+	return ob
+}
+
+func (ob *organisationDataBuilder) Version(value int) OrganisationDataBuilder {
+	ob.version = &value
+	return ob
+}
+
+func (ob *organisationDataBuilder) Attributes(value Organisation) OrganisationDataBuilder {
+	ob.attributes = value
+	return ob
+}
+
+func (ob *organisationDataBuilder) Build() OrganisationData {
+	return OrganisationData{
+		ID:             ob.id,
+		OrganisationID: ob.organisationID,
+		Type:           ob.organisationDataType,
+		Version:        ob.version,
+		Attributes:     ob.attributes,
+	}
+}
+
+// NewOrganisationData is used to create an OrganisationDataBuilder
+func NewOrganisationData() OrganisationDataBuilder {
+	return &organisationDataBuilder{}
+}
+
+// OrganisationSingle is the response payload when fetching an individual organisation
+type OrganisationSingle struct {
+	OrganisationData OrganisationData `json:"data"`
+	Links            Links            `json:"links"`
+
+	// Extra holds the members of the response that this library does not know about
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// OrganisationList is the response payload when requesting a list of organisations
+type OrganisationList struct {
+	OrganisationData *[]OrganisationData `json:"data,omitempty"`
+	Links            Links               `json:"links"`
+
+	// Extra holds the members of the response that this library does not know about
+	Extra map[string]json.RawMessage `json:"-"`
+}
@@ -0,0 +1,105 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const organisationsPath = "/v1/organisation/units"
+
+// OrganisationsClient is the client interface to access the organisation units of the Form3 API
+//
+// The WithContext variants abort the request to the API once the context is done
+type OrganisationsClient interface {
+	Create(request OrganisationData) (OrganisationSingle, error)
+	Fetch(id uuid.UUID) (OrganisationSingle, error)
+	List(page *Page) (OrganisationList, error)
+	Update(id uuid.UUID, request OrganisationData) (OrganisationSingle, error)
+	Delete(id uuid.UUID, version int) (bool, error)
+	ListAccounts(id uuid.UUID, page *Page) (List, error)
+	CreateWithContext(ctx context.Context, request OrganisationData) (OrganisationSingle, error)
+	FetchWithContext(ctx context.Context, id uuid.UUID) (OrganisationSingle, error)
+	ListWithContext(ctx context.Context, page *Page) (OrganisationList, error)
+	UpdateWithContext(ctx context.Context, id uuid.UUID, request OrganisationData) (OrganisationSingle, error)
+	DeleteWithContext(ctx context.Context, id uuid.UUID, version int) (bool, error)
+	ListAccountsWithContext(ctx context.Context, id uuid.UUID, page *Page) (List, error)
+}
+
+type organisationsClient struct {
+	resourceClient[OrganisationData, OrganisationSingle, OrganisationList]
+	accounts resource[Single, List]
+}
+
+// OrganisationsClientBuilder is used to create an OrganisationsClient
+type OrganisationsClientBuilder interface {
+	URL(string) OrganisationsClientBuilder
+	HTTPClient(http.Client) OrganisationsClientBuilder
+	StrictDecoding(bool) OrganisationsClientBuilder
+	MaxBodySize(int64) OrganisationsClientBuilder
+	Retry(RetryPolicy) OrganisationsClientBuilder
+	Middleware(...Middleware) OrganisationsClientBuilder
+	Build() OrganisationsClient
+}
+
+type organisationsClientBuilder struct {
+	transportOptions[OrganisationsClientBuilder]
+}
+
+func (ob *organisationsClientBuilder) Build() OrganisationsClient {
+	t := ob.transport()
+	organisations := newResource[OrganisationSingle, OrganisationList](t, organisationsPath, "organisation", "organisations")
+	return &organisationsClient{
+		resourceClient: newResourceClient(organisations, organisationsType, func(data *OrganisationData, partial bool) error {
+			return validateOrganisation(*data, partial)
+		}),
+		accounts: newAccountsResource(t),
+	}
+}
+
+// NewOrganisationsClient is used to create an OrganisationsClientBuilder
+func NewOrganisationsClient() OrganisationsClientBuilder {
+	ob := &organisationsClientBuilder{}
+	ob.self = ob
+	return ob
+}
+
+// List organisations
+func (c organisationsClient) List(page *Page) (OrganisationList, error) {
+	return c.ListWithContext(context.Background(), page)
+}
+
+// ListWithContext lists organisations until the context is done
+func (c organisationsClient) ListWithContext(ctx context.Context, page *Page) (OrganisationList, error) {
+	return c.resource.list(ctx, page, nil)
+}
+
+// ListAccounts lists the accounts of an organisation
+func (c organisationsClient) ListAccounts(id uuid.UUID, page *Page) (List, error) {
+	return c.ListAccountsWithContext(context.Background(), id, page)
+}
+
+// ListAccountsWithContext lists the accounts of an organisation until the context is done
+func (c organisationsClient) ListAccountsWithContext(ctx context.Context, id uuid.UUID, page *Page) (List, error) {
+	organisationID := id.String()
+	return c.accounts.list(ctx, page, filterParams(&Filter{OrganisationID: &organisationID}))
+}
+
+// validateOrganisation validates an organisation, an update being allowed to leave out the name
+func validateOrganisation(organisation OrganisationData, partial bool) error {
+	if organisation.OrganisationID != "" {
+		if _, err := uuid.Parse(organisation.OrganisationID); err != nil {
+			return &ValidationError{Field: "OrganisationID", Message: fmt.Sprintf("Invalid OrganisationID [%s]", organisation.OrganisationID)}
+		}
+	}
+
+	name := organisation.Attributes.Name
+	if (!partial || name != "") && (strings.TrimSpace(name) == "" || len(name) > 255) {
+		return &ValidationError{Field: "Name", Message: fmt.Sprintf("Invalid Name [%s]", name)}
+	}
+
+	return nil
+}
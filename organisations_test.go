@@ -0,0 +1,116 @@
+package accounts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOrganisations(t *testing.T) {
+
+	Convey("Given an API holding an organisation and its accounts", t, func() {
+		organisationID := uuid.New()
+		var method, target, query string
+		var request dataRequest[OrganisationData]
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method, target, query = r.Method, r.URL.Path, r.URL.RawQuery
+			json.NewDecoder(r.Body).Decode(&request)
+
+			switch {
+			case r.URL.Path == path:
+				fmt.Fprintf(w, `{"data":[{"id":"%s","organisation_id":"%s","type":"accounts","attributes":{"country":"GB"}}],"links":{"self":""}}`, uuid.New(), organisationID)
+			case r.Method == "DELETE":
+				w.WriteHeader(http.StatusNoContent)
+			case r.URL.Path == organisationsPath+"/"+organisationID.String() || r.Method == "POST":
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprintf(w, `{"data":{"id":"%s","type":"organisations","version":1,"attributes":{"name":"Acme"}},"links":{"self":""}}`, organisationID)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"error_message":"record does not exist"}`)
+			}
+		}))
+		defer server.Close()
+
+		OrganisationsService := NewOrganisationsClient().HTTPClient(HTTPClient).URL(server.URL).Build()
+
+		Convey("When I create an organisation", func() {
+			resp, err := OrganisationsService.Create(NewOrganisationData().ID(organisationID.String()).Attributes(NewOrganisation().Name("Acme").Build()).Build())
+
+			Convey("Then it is posted to the organisation units with its type", func() {
+				So(err, ShouldBeNil)
+				So(method, ShouldEqual, "POST")
+				So(target, ShouldEqual, organisationsPath)
+				So(request.Data.Type, ShouldEqual, organisationsType)
+				So(resp.OrganisationData.Attributes.Name, ShouldEqual, "Acme")
+			})
+		})
+
+		Convey("When I create an organisation without a name", func() {
+			_, err := OrganisationsService.Create(NewOrganisationData().ID(organisationID.String()).Build())
+
+			Convey("Then it is rejected before reaching the API", func() {
+				var validationErr *ValidationError
+				So(errors.As(err, &validationErr), ShouldBeTrue)
+				So(validationErr.Field, ShouldEqual, "Name")
+				So(method, ShouldEqual, "")
+			})
+		})
+
+		Convey("When I update the name of the organisation", func() {
+			_, err := OrganisationsService.Update(organisationID, NewOrganisationData().Version(0).Attributes(NewOrganisation().Name("Acme Ltd").Build()).Build())
+
+			Convey("Then it is patched with its ID and version", func() {
+				So(err, ShouldBeNil)
+				So(method, ShouldEqual, "PATCH")
+				So(request.Data.ID, ShouldEqual, organisationID.String())
+				So(*request.Data.Version, ShouldEqual, 0)
+			})
+		})
+
+		Convey("When I fetch an organisation that does not exist", func() {
+			_, err := OrganisationsService.Fetch(uuid.New())
+
+			Convey("Then the API error is returned", func() {
+				var apiErr *APIError
+				So(errors.As(err, &apiErr), ShouldBeTrue)
+				So(apiErr.StatusCode, ShouldEqual, http.StatusNotFound)
+			})
+		})
+
+		Convey("When I delete the organisation", func() {
+			deleted, err := OrganisationsService.Delete(organisationID, 1)
+
+			Convey("Then its version is sent", func() {
+				So(err, ShouldBeNil)
+				So(deleted, ShouldBeTrue)
+				So(query, ShouldEqual, "version=1")
+			})
+		})
+
+		Convey("When I list the accounts of the fetched organisation", func() {
+			fetched, err := OrganisationsService.Fetch(organisationID)
+			So(err, ShouldBeNil)
+
+			resp, err := OrganisationsService.ListAccounts(uuid.MustParse(fetched.OrganisationData.ID), &Page{Number: 0, Size: 10})
+
+			Convey("Then the accounts are filtered on the organisation", func() {
+				So(err, ShouldBeNil)
+				So(query, ShouldEqual, "page[number]=0&page[size]=10&filter[organisation_id]="+organisationID.String())
+				So((*resp.AccountData)[0].OrganisationID, ShouldEqual, organisationID.String())
+			})
+
+			Convey("And the accounts client can list them with the filter of the organisation", func() {
+				_, err := NewClient().HTTPClient(HTTPClient).URL(server.URL).Build().List(nil, fetched.OrganisationData.AccountsFilter())
+				So(err, ShouldBeNil)
+				So(query, ShouldEqual, "filter[organisation_id]="+organisationID.String())
+			})
+		})
+	})
+}
@@ -0,0 +1,57 @@
+package accounts
+
+import (
+	"context"
+	"errors"
+)
+
+// eachPage lists the accounts matching filter page by page, following the API until Links.Next
+// is empty, and calls visit with the accounts of every non-empty page
+//
+// It returns the number of pages visited, an empty last page not being counted
+func eachPage(c ContextClient, filter *Filter, size int, visit func(data []AccountData) error) (int, error) {
+	return eachPageWithContext(context.Background(), c, filter, size, visit)
+}
+
+// eachPageWithContext lists the accounts matching filter page by page until the context is done
+func eachPageWithContext(ctx context.Context, c ContextClient, filter *Filter, size int, visit func(data []AccountData) error) (int, error) {
+	return eachListedPage(ctx, size, func(ctx context.Context, page *Page) ([]AccountData, *string, error) {
+		resp, err := c.ListWithContext(ctx, page, filter)
+		if err != nil || resp.AccountData == nil {
+			return nil, nil, err
+		}
+		return *resp.AccountData, resp.Links.Next, nil
+	}, visit)
+}
+
+// errStopPaging is returned by a visit to stop eachListedPage without failing it
+var errStopPaging = errors.New("stop paging")
+
+// eachListedPage lists a resource page by page, following Links.Next until it is empty, and calls visit
+// with the items of every non-empty page; list returns the items of a page and its link to the next one
+//
+// It returns the number of pages visited, an empty last page not being counted
+func eachListedPage[T any](ctx context.Context, size int, list func(ctx context.Context, page *Page) ([]T, *string, error), visit func(data []T) error) (int, error) {
+	pages := 0
+	for number := 0; ; number++ {
+		data, next, err := list(ctx, &Page{Number: number, Size: size})
+		if err != nil {
+			return pages, err
+		}
+		if len(data) == 0 {
+			return pages, nil
+		}
+		pages++
+
+		if err := visit(data); err != nil {
+			if errors.Is(err, errStopPaging) {
+				return pages, nil
+			}
+			return pages, err
+		}
+
+		if next == nil || *next == "" {
+			return pages, nil
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package accounts
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+// parquetWriter writes a Parquet file where every column is an optional UTF-8 string
+//
+// Each call to writeRowGroup writes one uncompressed, PLAIN encoded row group, so rows can be
+// streamed page by page; the file metadata is written on close. See
+// https://github.com/apache/parquet-format for the layout and the Thrift definitions encoded below.
+type parquetWriter struct {
+	w         io.Writer
+	offset    int64
+	columns   []string
+	rowGroups []parquetRowGroup
+	rows      int64
+}
+
+type parquetRowGroup struct {
+	rows    int64
+	size    int64
+	columns []parquetColumnChunk
+}
+
+type parquetColumnChunk struct {
+	offset int64
+	size   int64
+	values int64
+}
+
+const parquetMagic = "PAR1"
+
+// Parquet enumerations, as numbered in parquet.thrift
+const (
+	parquetByteArray     = 6
+	parquetOptional      = 1
+	parquetUTF8          = 0
+	parquetPlain         = 0
+	parquetRLE           = 3
+	parquetUncompressed  = 0
+	parquetDataPage      = 0
+	parquetFormatVersion = 1
+)
+
+func newParquetWriter(w io.Writer, columns []string) (*parquetWriter, error) {
+	pw := &parquetWriter{w: w, columns: columns}
+	if err := pw.write([]byte(parquetMagic)); err != nil {
+		return nil, err
+	}
+	return pw, nil
+}
+
+func (pw *parquetWriter) write(data []byte) error {
+	n, err := pw.w.Write(data)
+	pw.offset += int64(n)
+	return err
+}
+
+// writeRowGroup writes rows, given as one value per column with nil for null
+func (pw *parquetWriter) writeRowGroup(rows [][]*string) error {
+	group := parquetRowGroup{rows: int64(len(rows))}
+
+	for c := range pw.columns {
+		var levels []byte
+		var values bytes.Buffer
+		for _, row := range rows {
+			if row[c] == nil {
+				levels = append(levels, 0)
+				continue
+			}
+			levels = append(levels, 1)
+			binary.Write(&values, binary.LittleEndian, uint32(len(*row[c])))
+			values.WriteString(*row[c])
+		}
+
+		encodedLevels := parquetLevels(levels)
+		var page bytes.Buffer
+		binary.Write(&page, binary.LittleEndian, uint32(len(encodedLevels)))
+		page.Write(encodedLevels)
+		page.Write(values.Bytes())
+
+		var header thriftWriter
+		header.i32(1, parquetDataPage)
+		header.i32(2, int32(page.Len()))
+		header.i32(3, int32(page.Len()))
+		header.beginStruct(5)
+		header.i32(1, int32(len(rows)))
+		header.i32(2, parquetPlain)
+		header.i32(3, parquetRLE)
+		header.i32(4, parquetRLE)
+		header.endStruct()
+		header.stop()
+
+		chunk := parquetColumnChunk{
+			offset: pw.offset,
+			size:   int64(header.buf.Len() + page.Len()),
+			values: int64(len(rows)),
+		}
+		if err := pw.write(header.buf.Bytes()); err != nil {
+			return err
+		}
+		if err := pw.write(page.Bytes()); err != nil {
+			return err
+		}
+
+		group.columns = append(group.columns, chunk)
+		group.size += chunk.size
+	}
+
+	pw.rowGroups = append(pw.rowGroups, group)
+	pw.rows += group.rows
+	return nil
+}
+
+// parquetLevels encodes definition levels of bit width 1 as runs of the RLE/bit-packing hybrid
+func parquetLevels(levels []byte) []byte {
+	var encoded []byte
+	for start := 0; start < len(levels); {
+		end := start
+		for end < len(levels) && levels[end] == levels[start] {
+			end++
+		}
+		encoded = appendUvarint(encoded, uint64(end-start)<<1)
+		encoded = append(encoded, levels[start])
+		start = end
+	}
+	return encoded
+}
+
+func appendUvarint(data []byte, value uint64) []byte {
+	encoded := make([]byte, binary.MaxVarintLen64)
+	return append(data, encoded[:binary.PutUvarint(encoded, value)]...)
+}
+
+// close writes the file metadata and the footer
+func (pw *parquetWriter) close() error {
+	var meta thriftWriter
+	meta.i32(1, parquetFormatVersion)
+
+	meta.beginList(2, thriftStruct, len(pw.columns)+1)
+	meta.binary(4, "schema")
+	meta.i32(5, int32(len(pw.columns)))
+	meta.stop()
+	for _, column := range pw.columns {
+		meta.i32(1, parquetByteArray)
+		meta.i32(3, parquetOptional)
+		meta.binary(4, column)
+		meta.i32(6, parquetUTF8)
+		meta.stop()
+	}
+	meta.endList()
+
+	meta.i64(3, pw.rows)
+
+	meta.beginList(4, thriftStruct, len(pw.rowGroups))
+	for _, group := range pw.rowGroups {
+		meta.beginList(1, thriftStruct, len(group.columns))
+		for c, chunk := range group.columns {
+			meta.i64(2, chunk.offset)
+			meta.beginStruct(3)
+			meta.i32(1, parquetByteArray)
+			meta.beginList(2, thriftI32, 2)
+			meta.listI32(parquetPlain)
+			meta.listI32(parquetRLE)
+			meta.endList()
+			meta.beginList(3, thriftBinary, 1)
+			meta.listBinary(pw.columns[c])
+			meta.endList()
+			meta.i32(4, parquetUncompressed)
+			meta.i64(5, chunk.values)
+			meta.i64(6, chunk.size)
+			meta.i64(7, chunk.size)
+			meta.i64(9, chunk.offset)
+			meta.endStruct()
+			meta.stop()
+		}
+		meta.endList()
+		meta.i64(2, group.size)
+		meta.i64(3, group.rows)
+		meta.stop()
+	}
+	meta.endList()
+
+	meta.binary(6, "github.com/razvanmuscalu/form3-accounts-client")
+	meta.stop()
+
+	if err := pw.write(meta.buf.Bytes()); err != nil {
+		return err
+	}
+	footer := make([]byte, 4)
+	binary.LittleEndian.PutUint32(footer, uint32(meta.buf.Len()))
+	if err := pw.write(footer); err != nil {
+		return err
+	}
+	return pw.write([]byte(parquetMagic))
+}
+
+// Thrift compact protocol types
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter encodes structs with the Thrift compact protocol
+//
+// Elements of struct lists are written as fields followed by stop, without begin and end calls
+type thriftWriter struct {
+	buf       bytes.Buffer
+	lastField []int16
+	field     int16
+}
+
+func (tw *thriftWriter) header(id int16, fieldType byte) {
+	if delta := id - tw.field; delta > 0 && delta <= 15 {
+		tw.buf.WriteByte(byte(delta)<<4 | fieldType)
+	} else {
+		tw.buf.WriteByte(fieldType)
+		tw.varint(int64(id))
+	}
+	tw.field = id
+}
+
+func (tw *thriftWriter) varint(value int64) {
+	encoded := make([]byte, binary.MaxVarintLen64)
+	tw.buf.Write(encoded[:binary.PutVarint(encoded, value)])
+}
+
+func (tw *thriftWriter) i32(id int16, value int32) {
+	tw.header(id, thriftI32)
+	tw.varint(int64(value))
+}
+
+func (tw *thriftWriter) i64(id int16, value int64) {
+	tw.header(id, thriftI64)
+	tw.varint(value)
+}
+
+func (tw *thriftWriter) binary(id int16, value string) {
+	tw.header(id, thriftBinary)
+	tw.listBinary(value)
+}
+
+func (tw *thriftWriter) listI32(value int32) {
+	tw.varint(int64(value))
+}
+
+func (tw *thriftWriter) listBinary(value string) {
+	tw.buf.Write(appendUvarint(nil, uint64(len(value))))
+	tw.buf.WriteString(value)
+}
+
+func (tw *thriftWriter) beginStruct(id int16) {
+	tw.header(id, thriftStruct)
+	tw.lastField = append(tw.lastField, tw.field)
+	tw.field = 0
+}
+
+func (tw *thriftWriter) endStruct() {
+	tw.buf.WriteByte(0)
+	tw.field = tw.lastField[len(tw.lastField)-1]
+	tw.lastField = tw.lastField[:len(tw.lastField)-1]
+}
+
+func (tw *thriftWriter) beginList(id int16, elementType byte, size int) {
+	tw.header(id, thriftList)
+	if size < 15 {
+		tw.buf.WriteByte(byte(size)<<4 | elementType)
+	} else {
+		tw.buf.WriteByte(0xf0 | elementType)
+		tw.buf.Write(appendUvarint(nil, uint64(size)))
+	}
+	tw.lastField = append(tw.lastField, tw.field)
+	tw.field = 0
+}
+
+func (tw *thriftWriter) endList() {
+	tw.field = tw.lastField[len(tw.lastField)-1]
+	tw.lastField = tw.lastField[:len(tw.lastField)-1]
+}
+
+// stop ends a struct written as a list element and resets the field IDs for the next one
+func (tw *thriftWriter) stop() {
+	tw.buf.WriteByte(0)
+	tw.field = 0
+}
@@ -0,0 +1,6 @@
+// Package parquetcheck reads the Parquet files of the exporter back with parquet-go, a maintained Parquet
+// implementation, so that the hand-written writer of the client library is checked against another reader
+//
+// It is a module of its own so that the client library does not depend on parquet-go, nor on the Go version
+// it requires; run its tests with make test-parquet
+package parquetcheck
@@ -0,0 +1,22 @@
+module github.com/razvanmuscalu/form3-accounts-client/parquetcheck
+
+go 1.22
+
+replace github.com/razvanmuscalu/form3-accounts-client => ../
+
+require (
+	github.com/google/uuid v1.6.0
+	github.com/parquet-go/parquet-go v0.25.1
+	github.com/razvanmuscalu/form3-accounts-client v0.0.0-00010101000000-000000000000
+	github.com/smartystreets/goconvey v1.6.4
+)
+
+require (
+	github.com/andybalholm/brotli v1.1.0 // indirect
+	github.com/gopherjs/gopherjs v0.0.0-20181017120253-0766667cb4d1 // indirect
+	github.com/jtolds/gls v4.20.0+incompatible // indirect
+	github.com/klauspost/compress v1.17.9 // indirect
+	github.com/pierrec/lz4/v4 v4.1.21 // indirect
+	github.com/smartystreets/assertions v0.0.0-20180927180507-b2de0cb4f26d // indirect
+	golang.org/x/sys v0.21.0 // indirect
+)
@@ -0,0 +1,97 @@
+package parquetcheck
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/parquet-go/parquet-go"
+
+	accounts "github.com/razvanmuscalu/form3-accounts-client"
+	"github.com/razvanmuscalu/form3-accounts-client/fakeapi"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// row is an account as the exporter writes it to Parquet, every column being an optional string
+type row struct {
+	ID                          *string `parquet:"id,optional"`
+	BIC                         *string `parquet:"bic,optional"`
+	IBAN                        *string `parquet:"iban,optional"`
+	JointAccount                *string `parquet:"joint_account,optional"`
+	AlternativeBankAccountNames *string `parquet:"alternative_bank_account_names,optional"`
+}
+
+func TestReadExportWithParquetGo(t *testing.T) {
+
+	Convey("Given an organisation with 5 accounts", t, func() {
+		api := fakeapi.NewServer().Build()
+		defer api.Close()
+
+		client := accounts.NewClient().HTTPClient(http.Client{Timeout: 5 * time.Second}).URL(api.URL()).Build()
+		organisationID := uuid.New().String()
+		var ids []string
+		for i := 0; i < 5; i++ {
+			attributes := accounts.NewAccount().Country("GB").IBAN("GB28NWBK40030212764204").JointAccount(i%2 == 0)
+			if i == 0 {
+				attributes = attributes.BIC("NWBKGB42").AlternativeBankAccountNames([]string{"Jane", "Janet"})
+			}
+			id := uuid.New().String()
+			_, err := client.Create(accounts.NewAccountData().ID(id).OrganisationID(organisationID).Type("accounts").Attributes(attributes.Build()).Build())
+			So(err, ShouldBeNil)
+			ids = append(ids, id)
+		}
+
+		exporter := accounts.NewExporter().
+			Client(client).
+			OrganisationID(organisationID).
+			Format(accounts.Parquet).
+			Fields([]string{"id", "bic", "iban", "joint_account", "alternative_bank_account_names"}).
+			Mask([]string{}).
+			PageSize(3).
+			Build()
+
+		Convey("When I export its accounts to Parquet", func() {
+			var output bytes.Buffer
+			result, err := exporter.Export(&output, nil)
+			So(err, ShouldBeNil)
+			So(result.Rows, ShouldEqual, 5)
+
+			Convey("Then parquet-go opens the file with one row group per page, the columns in their stable order", func() {
+				file, err := parquet.OpenFile(bytes.NewReader(output.Bytes()), int64(output.Len()))
+				So(err, ShouldBeNil)
+				So(file.NumRows(), ShouldEqual, 5)
+				So(len(file.RowGroups()), ShouldEqual, 2)
+
+				var names []string
+				for _, field := range file.Schema().Fields() {
+					names = append(names, field.Name())
+				}
+				So(names, ShouldResemble, []string{"id", "bic", "iban", "alternative_bank_account_names", "joint_account"})
+			})
+
+			Convey("And parquet-go reads back every row, missing values as nulls", func() {
+				rows, err := parquet.Read[row](bytes.NewReader(output.Bytes()), int64(output.Len()))
+				So(err, ShouldBeNil)
+				So(len(rows), ShouldEqual, 5)
+
+				var read []string
+				for _, r := range rows {
+					So(r.ID, ShouldNotBeNil)
+					read = append(read, *r.ID)
+				}
+				So(read, ShouldResemble, ids)
+
+				So(*rows[0].BIC, ShouldEqual, "NWBKGB42")
+				So(*rows[0].AlternativeBankAccountNames, ShouldEqual, "Jane;Janet")
+				So(*rows[0].JointAccount, ShouldEqual, "true")
+				So(rows[1].BIC, ShouldBeNil)
+				So(rows[1].AlternativeBankAccountNames, ShouldBeNil)
+				So(*rows[1].JointAccount, ShouldEqual, "false")
+				So(*rows[4].IBAN, ShouldEqual, "GB28NWBK40030212764204")
+			})
+		})
+	})
+}
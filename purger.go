@@ -0,0 +1,218 @@
+package accounts
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// DeleteSummary holds the outcome of deleting many accounts, by account ID
+//
+// Accounts are skipped when they no longer exist. When running dry, the accounts that would have
+// been deleted are listed in WouldDelete instead of being deleted
+type DeleteSummary struct {
+	Deleted     []string
+	WouldDelete []string
+	Skipped     []string
+	Failed      map[string]error
+}
+
+// Purger deletes many accounts concurrently
+type Purger interface {
+	// DeleteMany deletes the given accounts, starting with the version they hold
+	DeleteMany(accounts []AccountData) DeleteSummary
+	// PurgeOrganisation lists every account of an organisation and deletes them
+	PurgeOrganisation(organisationID string) (DeleteSummary, error)
+}
+
+type purger struct {
+	client   ContextClient
+	workers  int
+	retries  int
+	pageSize int
+	dryRun   bool
+}
+
+// PurgerBuilder is used to create a Purger
+type PurgerBuilder interface {
+	Client(ContextClient) PurgerBuilder
+	Workers(int) PurgerBuilder
+	Retries(int) PurgerBuilder
+	PageSize(int) PurgerBuilder
+	DryRun(bool) PurgerBuilder
+	Build() Purger
+}
+
+type purgerBuilder struct {
+	client   ContextClient
+	workers  int
+	retries  int
+	pageSize int
+	dryRun   bool
+}
+
+func (pb *purgerBuilder) Client(value ContextClient) PurgerBuilder {
+	pb.client = value
+	return pb
+}
+
+// Workers is the number of accounts deleted concurrently, 1 when not set
+func (pb *purgerBuilder) Workers(value int) PurgerBuilder {
+	pb.workers = value
+	return pb
+}
+
+// Retries is the number of times an account is fetched again for its current version after a
+// version conflict, 3 when not set
+func (pb *purgerBuilder) Retries(value int) PurgerBuilder {
+	pb.retries = value
+	return pb
+}
+
+// PageSize is the number of accounts listed per page by PurgeOrganisation, 100 when not set
+func (pb *purgerBuilder) PageSize(value int) PurgerBuilder {
+	pb.pageSize = value
+	return pb
+}
+
+// DryRun lists the accounts that would be deleted in WouldDelete without deleting them
+func (pb *purgerBuilder) DryRun(value bool) PurgerBuilder {
+	pb.dryRun = value
+	return pb
+}
+
+func (pb *purgerBuilder) Build() Purger {
+	p := &purger{
+		client:   pb.client,
+		workers:  pb.workers,
+		retries:  pb.retries,
+		pageSize: pb.pageSize,
+		dryRun:   pb.dryRun,
+	}
+	if p.workers < 1 {
+		p.workers = 1
+	}
+	if p.retries < 1 {
+		p.retries = 3
+	}
+	if p.pageSize < 1 {
+		p.pageSize = 100
+	}
+
+	return p
+}
+
+// NewPurger is used to create a PurgerBuilder
+func NewPurger() PurgerBuilder {
+	return &purgerBuilder{}
+}
+
+func (p purger) PurgeOrganisation(organisationID string) (DeleteSummary, error) {
+	var all []AccountData
+	_, err := eachPage(p.client, &Filter{OrganisationID: &organisationID}, p.pageSize, func(data []AccountData) error {
+		all = append(all, data...)
+		return nil
+	})
+	if err != nil {
+		return DeleteSummary{Failed: map[string]error{}}, err
+	}
+
+	return p.DeleteMany(all), nil
+}
+
+// deleteOutcome is the outcome of deleting a single account
+type deleteOutcome int
+
+const (
+	deleteSucceeded deleteOutcome = iota
+	deleteWouldSucceed
+	deleteSkipped
+	deleteFailed
+)
+
+func (p purger) DeleteMany(accounts []AccountData) DeleteSummary {
+	summary := DeleteSummary{Failed: make(map[string]error)}
+	var mutex sync.Mutex
+
+	jobs := make(chan AccountData)
+	var wg sync.WaitGroup
+	for w := 0; w < p.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ad := range jobs {
+				outcome, err := p.delete(ad)
+
+				mutex.Lock()
+				switch outcome {
+				case deleteSucceeded:
+					summary.Deleted = append(summary.Deleted, ad.ID)
+				case deleteWouldSucceed:
+					summary.WouldDelete = append(summary.WouldDelete, ad.ID)
+				case deleteSkipped:
+					summary.Skipped = append(summary.Skipped, ad.ID)
+				case deleteFailed:
+					summary.Failed[ad.ID] = err
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	for _, ad := range accounts {
+		jobs <- ad
+	}
+	close(jobs)
+	wg.Wait()
+
+	sort.Strings(summary.Deleted)
+	sort.Strings(summary.WouldDelete)
+	sort.Strings(summary.Skipped)
+	return summary
+}
+
+// delete deletes an account, fetching its current version again whenever the API reports a version conflict
+func (p purger) delete(ad AccountData) (deleteOutcome, error) {
+	id, err := uuid.Parse(ad.ID)
+	if err != nil {
+		return deleteFailed, &ValidationError{Field: "ID", Message: fmt.Sprintf("Invalid ID [%s]", ad.ID)}
+	}
+	if p.dryRun {
+		return deleteWouldSucceed, nil
+	}
+
+	version := 0
+	if ad.Version != nil {
+		version = *ad.Version
+	}
+
+	for attempt := 0; ; attempt++ {
+		_, err := p.client.Delete(id, version)
+		if err == nil {
+			return deleteSucceeded, nil
+		}
+
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return deleteSkipped, nil
+		}
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusConflict || attempt == p.retries {
+			return deleteFailed, err
+		}
+
+		current, err := p.client.Fetch(id)
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return deleteSkipped, nil
+		}
+		if err != nil {
+			return deleteFailed, err
+		}
+		if current.AccountData.Version != nil {
+			version = *current.AccountData.Version
+		}
+	}
+}
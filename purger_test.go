@@ -0,0 +1,162 @@
+package accounts
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// purgeServer keeps accounts in memory, lists them all with version 0 as if they were listed
+// before being modified, and rejects deletes of a stale version with a conflict
+type purgeServer struct {
+	mutex    sync.Mutex
+	accounts map[string]AccountData
+	order    []string
+}
+
+func (ps *purgeServer) add(version int) AccountData {
+	ad := NewAccountData().ID(uuid.New().String()).OrganisationID(OrganisationID).Type(Type).Version(version).Attributes(NewAccount().Country("GB").Build()).Build()
+	ps.accounts[ad.ID] = ad
+	ps.order = append(ps.order, ad.ID)
+
+	listed := ad
+	listed.Version = new(int)
+	return listed
+}
+
+func (ps *purgeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+
+	id := strings.TrimPrefix(r.URL.Path, path+"/")
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == path:
+		var data []AccountData
+		for _, id := range ps.order {
+			if ad, ok := ps.accounts[id]; ok {
+				ad.Version = new(int)
+				data = append(data, ad)
+			}
+		}
+		json.NewEncoder(w).Encode(List{AccountData: &data})
+	case r.Method == http.MethodGet:
+		ad, ok := ps.accounts[id]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `{"error_message": "record %s does not exist"}`, id)
+			return
+		}
+		json.NewEncoder(w).Encode(Single{AccountData: ad})
+	case r.Method == http.MethodDelete:
+		ad, ok := ps.accounts[id]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `{"error_message": "record %s does not exist"}`, id)
+			return
+		}
+		if version, _ := strconv.Atoi(r.URL.Query().Get("version")); version != *ad.Version {
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprint(w, `{"error_message": "invalid version"}`)
+			return
+		}
+		delete(ps.accounts, id)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestDeleteManyAccountsWithStaleVersions(t *testing.T) {
+
+	Convey("Given accounts, one of which changed version since it was listed, and one already deleted", t, func() {
+		store := &purgeServer{accounts: make(map[string]AccountData)}
+		current := store.add(0)
+		stale := store.add(2)
+		gone := store.add(0)
+		delete(store.accounts, gone.ID)
+
+		server := httptest.NewServer(store)
+		defer server.Close()
+
+		Purger := NewPurger().Client(NewClient().HTTPClient(HTTPClient).URL(server.URL).Build()).Workers(3).Build()
+
+		Convey("When I delete them all", func() {
+			summary := Purger.DeleteMany([]AccountData{current, stale, gone})
+
+			Convey("Then the stale account is deleted with its current version", func() {
+				So(len(summary.Deleted), ShouldEqual, 2)
+				So(summary.Deleted, ShouldContain, current.ID)
+				So(summary.Deleted, ShouldContain, stale.ID)
+				So(len(store.accounts), ShouldEqual, 0)
+			})
+
+			Convey("And the account that no longer exists is skipped", func() {
+				So(summary.Skipped, ShouldResemble, []string{gone.ID})
+				So(len(summary.Failed), ShouldEqual, 0)
+			})
+
+		})
+
+	})
+
+}
+
+func TestPurgeOrganisation(t *testing.T) {
+
+	Convey("Given an organisation with 5 accounts, some of which have been modified", t, func() {
+		store := &purgeServer{accounts: make(map[string]AccountData)}
+		for i := 0; i < 5; i++ {
+			store.add(i % 2)
+		}
+
+		server := httptest.NewServer(store)
+		defer server.Close()
+
+		Convey("When I purge it in dry run mode", func() {
+			Purger := NewPurger().Client(NewClient().HTTPClient(HTTPClient).URL(server.URL).Build()).DryRun(true).Build()
+			summary, err := Purger.PurgeOrganisation(OrganisationID)
+
+			Convey("Then every account would be deleted and none is", func() {
+				So(err, ShouldBeNil)
+				So(len(summary.WouldDelete), ShouldEqual, 5)
+				So(len(summary.Skipped), ShouldEqual, 0)
+				So(len(summary.Deleted), ShouldEqual, 0)
+				So(len(store.accounts), ShouldEqual, 5)
+			})
+		})
+
+		Convey("When I purge it", func() {
+			Purger := NewPurger().Client(NewClient().HTTPClient(HTTPClient).URL(server.URL).Build()).Workers(2).Build()
+			summary, err := Purger.PurgeOrganisation(OrganisationID)
+
+			Convey("Then every account is deleted", func() {
+				So(err, ShouldBeNil)
+				So(len(summary.Deleted), ShouldEqual, 5)
+				So(len(store.accounts), ShouldEqual, 0)
+			})
+		})
+
+	})
+
+}
+
+func TestPurgeOrganisationFailure(t *testing.T) {
+
+	Convey("When I purge an organisation on a non-existent server", t, func() {
+		Purger := NewPurger().Client(NewClient().HTTPClient(HTTPClient).URL("http://unknown:9999").Build()).Build()
+		_, err := Purger.PurgeOrganisation(OrganisationID)
+
+		Convey("Then an appropriate error is propagated to the caller", func() {
+			So(err.Error(), ShouldEqual, "An error has occured while listing accounts")
+		})
+
+	})
+
+}
@@ -0,0 +1,74 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+)
+
+// VerifyRegistration returns a PreCreateHook checking that the bank ID and BIC of an account are registered
+// for its organisation, returning a ValidationError on the BankID or BIC field when they are not
+//
+// The bank ID must be registered for the country of the account, with the same bank ID code when the
+// account has one. Either client may be nil to leave that check out
+func VerifyRegistration(bankIDs BankIDsClient, bics BICsClient) PreCreateHook {
+	return func(ctx context.Context, request AccountData) error {
+		filter := &Filter{OrganisationID: &request.OrganisationID}
+		attributes := request.Attributes
+
+		if bankIDs != nil && attributes.BankID != nil {
+			registered, err := anyRegistered(ctx, func(ctx context.Context, page *Page) ([]BankIDData, *string, error) {
+				resp, err := bankIDs.ListWithContext(ctx, page, filter)
+				if err != nil || resp.BankIDData == nil {
+					return nil, nil, err
+				}
+				return *resp.BankIDData, resp.Links.Next, nil
+			}, func(data BankIDData) bool {
+				return data.Attributes.BankID == *attributes.BankID &&
+					data.Attributes.Country == attributes.Country &&
+					(attributes.BankIDCode == nil || data.Attributes.BankIDCode == *attributes.BankIDCode)
+			})
+			if err != nil {
+				return err
+			}
+			if !registered {
+				return &ValidationError{Field: "BankID", Message: fmt.Sprintf("Unregistered BankID [%s]", *attributes.BankID)}
+			}
+		}
+
+		if bics != nil && attributes.BIC != nil {
+			registered, err := anyRegistered(ctx, func(ctx context.Context, page *Page) ([]BICData, *string, error) {
+				resp, err := bics.ListWithContext(ctx, page, filter)
+				if err != nil || resp.BICData == nil {
+					return nil, nil, err
+				}
+				return *resp.BICData, resp.Links.Next, nil
+			}, func(data BICData) bool {
+				return data.Attributes.BIC == *attributes.BIC
+			})
+			if err != nil {
+				return err
+			}
+			if !registered {
+				return &ValidationError{Field: "BIC", Message: fmt.Sprintf("Unregistered BIC [%s]", *attributes.BIC)}
+			}
+		}
+
+		return nil
+	}
+}
+
+// anyRegistered lists a resource page by page until one of its items matches, or Links.Next is empty
+func anyRegistered[T any](ctx context.Context, list func(ctx context.Context, page *Page) ([]T, *string, error), match func(T) bool) (bool, error) {
+	registered := false
+	_, err := eachListedPage(ctx, 100, list, func(data []T) error {
+		for _, item := range data {
+			if match(item) {
+				registered = true
+				return errStopPaging
+			}
+		}
+		return nil
+	})
+
+	return registered, err
+}
@@ -0,0 +1,178 @@
+package accounts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBankIDsAndBICs(t *testing.T) {
+
+	Convey("Given an API holding the bank IDs and BICs of an organisation", t, func() {
+		var method, target, query string
+		var bankIDRequest dataRequest[BankIDData]
+		var bicRequest dataRequest[BICData]
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method, target, query = r.Method, r.URL.Path, r.URL.RawQuery
+			switch {
+			case r.Method == "POST" && r.URL.Path == bankIDsPath:
+				json.NewDecoder(r.Body).Decode(&bankIDRequest)
+				json.NewEncoder(w).Encode(BankIDSingle{BankIDData: bankIDRequest.Data})
+			case r.Method == "POST" && r.URL.Path == bicsPath:
+				json.NewDecoder(r.Body).Decode(&bicRequest)
+				json.NewEncoder(w).Encode(BICSingle{BICData: bicRequest.Data})
+			case r.Method == "DELETE":
+				w.WriteHeader(http.StatusNoContent)
+			case r.URL.Path == bankIDsPath:
+				fmt.Fprintf(w, `{"data":[{"id":"%s","organisation_id":"%s","type":"bankids","attributes":{"country":"GB","bank_id":"400300","bank_id_code":"GBDSC"}}],"links":{"self":""}}`, uuid.New(), OrganisationID)
+			case r.URL.Path == bicsPath:
+				fmt.Fprintf(w, `{"data":[{"id":"%s","organisation_id":"%s","type":"bics","attributes":{"bic":"NWBKGB22"}}],"links":{"self":""}}`, uuid.New(), OrganisationID)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"error_message":"record does not exist"}`)
+			}
+		}))
+		defer server.Close()
+
+		BankIDsService := NewBankIDsClient().HTTPClient(HTTPClient).URL(server.URL).Build()
+		BICsService := NewBICsClient().HTTPClient(HTTPClient).URL(server.URL).Build()
+
+		Convey("When I create a bank ID", func() {
+			resp, err := BankIDsService.Create(NewBankIDData().ID(uuid.New().String()).OrganisationID(OrganisationID).
+				Attributes(NewBankID().Country("GB").BankID("400300").BankIDCode("GBDSC").Build()).Build())
+
+			Convey("Then it is posted to the bank IDs with its type", func() {
+				So(err, ShouldBeNil)
+				So(method, ShouldEqual, "POST")
+				So(bankIDRequest.Data.Type, ShouldEqual, bankIDsType)
+				So(resp.BankIDData.Attributes.BankID, ShouldEqual, "400300")
+			})
+		})
+
+		Convey("When I create a bank ID without a bank ID code", func() {
+			_, err := BankIDsService.Create(NewBankIDData().Attributes(NewBankID().Country("GB").BankID("400300").Build()).Build())
+
+			Convey("Then it is rejected before reaching the API", func() {
+				var validationErr *ValidationError
+				So(errors.As(err, &validationErr), ShouldBeTrue)
+				So(validationErr.Field, ShouldEqual, "BankIDCode")
+				So(method, ShouldEqual, "")
+			})
+		})
+
+		Convey("When I create a BIC", func() {
+			resp, err := BICsService.Create(NewBICData().ID(uuid.New().String()).OrganisationID(OrganisationID).Attributes(NewBIC().BIC("NWBKGB22").Build()).Build())
+
+			Convey("Then it is posted to the BICs with its type", func() {
+				So(err, ShouldBeNil)
+				So(target, ShouldEqual, bicsPath)
+				So(bicRequest.Data.Type, ShouldEqual, bicsType)
+				So(resp.BICData.Attributes.BIC, ShouldEqual, "NWBKGB22")
+			})
+		})
+
+		Convey("When I create an invalid BIC", func() {
+			_, err := BICsService.Create(NewBICData().Attributes(NewBIC().BIC("NWBK").Build()).Build())
+
+			Convey("Then it is rejected before reaching the API", func() {
+				var validationErr *ValidationError
+				So(errors.As(err, &validationErr), ShouldBeTrue)
+				So(validationErr.Field, ShouldEqual, "BIC")
+				So(method, ShouldEqual, "")
+			})
+		})
+
+		Convey("When I list the bank IDs and BICs of the organisation", func() {
+			filter := &Filter{OrganisationID: &OrganisationID}
+			bankIDs, err := BankIDsService.List(&Page{Number: 0, Size: 10}, filter)
+			So(err, ShouldBeNil)
+			So(query, ShouldEqual, "page[number]=0&page[size]=10&filter[organisation_id]="+OrganisationID)
+			bics, err := BICsService.List(nil, filter)
+			So(err, ShouldBeNil)
+
+			Convey("Then they are returned", func() {
+				So((*bankIDs.BankIDData)[0].Attributes.BankIDCode, ShouldEqual, "GBDSC")
+				So((*bics.BICData)[0].Attributes.BIC, ShouldEqual, "NWBKGB22")
+			})
+		})
+
+		Convey("When I fetch a BIC that does not exist", func() {
+			_, err := BICsService.Fetch(uuid.New())
+
+			Convey("Then the API error is returned", func() {
+				var apiErr *APIError
+				So(errors.As(err, &apiErr), ShouldBeTrue)
+				So(apiErr.StatusCode, ShouldEqual, http.StatusNotFound)
+			})
+		})
+
+		Convey("When I delete a bank ID", func() {
+			deleted, err := BankIDsService.Delete(uuid.New(), 2)
+
+			Convey("Then its version is sent", func() {
+				So(err, ShouldBeNil)
+				So(deleted, ShouldBeTrue)
+				So(query, ShouldEqual, "version=2")
+			})
+		})
+
+		Convey("Given a client verifying the registration of accounts before creating them", func() {
+			AccountsService := NewClient().HTTPClient(HTTPClient).URL(server.URL).PreCreate(VerifyRegistration(BankIDsService, BICsService)).Build()
+			create := func(attributes Account) error {
+				method, target = "", ""
+				_, err := AccountsService.Create(NewAccountData().ID(uuid.New().String()).OrganisationID(OrganisationID).Type(Type).Attributes(attributes).Build())
+				return err
+			}
+
+			Convey("When the bank ID and BIC of the account are registered", func() {
+				err := create(NewAccount().Country("GB").BankID("400300").BankIDCode("GBDSC").BIC("NWBKGB22").Build())
+
+				Convey("Then the account is sent to the API", func() {
+					var apiErr *APIError
+					So(errors.As(err, &apiErr), ShouldBeTrue)
+					So(target, ShouldEqual, path)
+				})
+			})
+
+			Convey("When the bank ID of the account is not registered for its country", func() {
+				err := create(NewAccount().Country("FR").BankID("400300").Build())
+
+				Convey("Then a validation error on the bank ID is returned without creating the account", func() {
+					var validationErr *ValidationError
+					So(errors.As(err, &validationErr), ShouldBeTrue)
+					So(validationErr.Field, ShouldEqual, "BankID")
+					So(validationErr.Message, ShouldEqual, "Unregistered BankID [400300]")
+					So(target, ShouldEqual, bankIDsPath)
+				})
+			})
+
+			Convey("When the BIC of the account is not registered", func() {
+				err := create(NewAccount().Country("GB").BIC("BARCGB22").Build())
+
+				Convey("Then a validation error on the BIC is returned", func() {
+					var validationErr *ValidationError
+					So(errors.As(err, &validationErr), ShouldBeTrue)
+					So(validationErr.Field, ShouldEqual, "BIC")
+					So(target, ShouldEqual, bicsPath)
+				})
+			})
+
+			Convey("When the account has neither a bank ID nor a BIC", func() {
+				err := create(NewAccount().Country("GB").Build())
+
+				Convey("Then nothing is checked", func() {
+					var apiErr *APIError
+					So(errors.As(err, &apiErr), ShouldBeTrue)
+					So(target, ShouldEqual, path)
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,230 @@
+package accounts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ResourceIdentifier identifies a related resource by its type and ID
+type ResourceIdentifier struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+// Relationship holds the identifiers of the resources an account is related to
+//
+// The API may return the relationship data either as a single object or as an array,
+// so both shapes are accepted and the original shape is kept when encoding
+type Relationship struct {
+	Data   []ResourceIdentifier
+	single bool
+
+	// Extra holds the members of the relationship other than its data, e.g. links or meta
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes relationship data given either as an object or as an array
+func (r *Relationship) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	extra, err := unknownFields(data, map[string]bool{"data": true})
+	if err != nil {
+		return err
+	}
+	defer func() { r.Extra = extra }()
+
+	trimmed := bytes.TrimSpace(raw.Data)
+	switch {
+	case len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")):
+		*r = Relationship{single: true}
+	case trimmed[0] == '{':
+		var identifier ResourceIdentifier
+		if err := json.Unmarshal(trimmed, &identifier); err != nil {
+			return err
+		}
+		*r = Relationship{Data: []ResourceIdentifier{identifier}, single: true}
+	default:
+		var identifiers []ResourceIdentifier
+		if err := json.Unmarshal(trimmed, &identifiers); err != nil {
+			return err
+		}
+		*r = Relationship{Data: identifiers}
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes relationship data in the shape it was received in, re-emitting the members kept in Extra
+func (r Relationship) MarshalJSON() ([]byte, error) {
+	encoded, err := r.marshalData()
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeFields(encoded, r.Extra)
+}
+
+func (r Relationship) marshalData() ([]byte, error) {
+	if r.single {
+		if len(r.Data) == 0 {
+			return []byte(`{"data":null}`), nil
+		}
+		return json.Marshal(struct {
+			Data ResourceIdentifier `json:"data"`
+		}{Data: r.Data[0]})
+	}
+
+	data := r.Data
+	if data == nil {
+		data = []ResourceIdentifier{}
+	}
+	return json.Marshal(struct {
+		Data []ResourceIdentifier `json:"data"`
+	}{Data: data})
+}
+
+// Relationships holds the relationships of an account
+type Relationships struct {
+	MasterAccount *Relationship `json:"master_account,omitempty"`
+	AccountEvents *Relationship `json:"account_events,omitempty"`
+
+	// Extra holds the relationships that this library does not know about
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the relationships, keeping those this library does not know about in Extra
+func (r *Relationships) UnmarshalJSON(data []byte) error {
+	type plain Relationships
+	return decodeJSON(data, (*plain)(r))
+}
+
+// MarshalJSON encodes the relationships, re-emitting those kept in Extra
+func (r Relationships) MarshalJSON() ([]byte, error) {
+	type plain Relationships
+	encoded, err := json.Marshal(plain(r))
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeFields(encoded, r.Extra)
+}
+
+// Resource is a generic JSON:API resource returned in the included array of a response
+type Resource struct {
+	ID             string                  `json:"id"`
+	Type           string                  `json:"type"`
+	OrganisationID string                  `json:"organisation_id,omitempty"`
+	CreatedOn      *time.Time              `json:"created_on,omitempty"`
+	ModifiedOn     *time.Time              `json:"modified_on,omitempty"`
+	Version        *int                    `json:"version,omitempty"`
+	Attributes     json.RawMessage         `json:"attributes,omitempty"`
+	Relationships  map[string]Relationship `json:"relationships,omitempty"`
+
+	// Extra holds the members of the resource this library does not know about, e.g. links or meta
+	Extra map[string]json.RawMessage `json:"-"`
+
+	// raw is the resource as received, so that it can be decoded into a type with more fields
+	raw json.RawMessage
+}
+
+// UnmarshalJSON decodes the resource, parsing created_on and modified_on tolerantly and keeping it as received
+func (r *Resource) UnmarshalJSON(data []byte) error {
+	type plain Resource
+	var decoded plain
+	if err := decodeJSON(data, &decoded); err != nil {
+		return err
+	}
+
+	*r = Resource(decoded)
+	r.raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// DecodeAttributes decodes the attributes of the resource into value
+func (r Resource) DecodeAttributes(value interface{}) error {
+	if len(r.Attributes) == 0 {
+		return fmt.Errorf("Resource %s [%s] has no attributes", r.Type, r.ID)
+	}
+	if err := json.Unmarshal(r.Attributes, value); err != nil {
+		return fmt.Errorf("An error has occured while decoding attributes of %s [%s]", r.Type, r.ID)
+	}
+
+	return nil
+}
+
+// Included holds the resources returned alongside the primary data of a response
+type Included []Resource
+
+// Find returns the included resource with the given type and ID
+func (in Included) Find(resourceType string, id string) (Resource, bool) {
+	for _, resource := range in {
+		if resource.Type == resourceType && resource.ID == id {
+			return resource, true
+		}
+	}
+
+	return Resource{}, false
+}
+
+// Resolve returns the included resources a relationship points to
+//
+// Identifiers that have no matching included resource are skipped
+func (in Included) Resolve(relationship *Relationship) []Resource {
+	if relationship == nil {
+		return nil
+	}
+
+	var resources []Resource
+	for _, identifier := range relationship.Data {
+		if resource, ok := in.Find(identifier.Type, identifier.ID); ok {
+			resources = append(resources, resource)
+		}
+	}
+
+	return resources
+}
+
+// Account returns the included account with the given ID
+func (in Included) Account(id string) (AccountData, bool) {
+	resource, ok := in.Find(accountsType, id)
+	if !ok {
+		return AccountData{}, false
+	}
+
+	raw := resource.raw
+	if len(raw) == 0 {
+		var err error
+		if raw, err = json.Marshal(resource); err != nil {
+			return AccountData{}, false
+		}
+	}
+
+	var result AccountData
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return AccountData{}, false
+	}
+
+	return result, true
+}
+
+// MasterAccount returns the included master account of the primary account, if any
+func (s Single) MasterAccount() (AccountData, bool) {
+	if s.AccountData.Relationships == nil || s.AccountData.Relationships.MasterAccount == nil {
+		return AccountData{}, false
+	}
+
+	for _, identifier := range s.AccountData.Relationships.MasterAccount.Data {
+		if account, ok := s.Included.Account(identifier.ID); ok {
+			return account, true
+		}
+	}
+
+	return AccountData{}, false
+}
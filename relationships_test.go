@@ -0,0 +1,148 @@
+package accounts
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const accountWithRelationships = `{
+	"data": {
+		"id": "%[1]s",
+		"organisation_id": "%[3]s",
+		"type": "accounts",
+		"attributes": {"country": "GB"},
+		"relationships": {
+			"master_account": {"data": [{"id": "%[2]s", "type": "accounts"}]},
+			"account_events": {"data": [{"id": "%[4]s", "type": "account_events"}]}
+		}
+	},
+	"included": [
+		{"id": "%[2]s", "organisation_id": "%[3]s", "type": "accounts", "created_on": "2021-03-01T10:00:00Z", "modified_on": "2021-03-02T10:00:00Z", "attributes": {"country": "GB", "bank_id": "400300", "status": "confirmed"}},
+		{"id": "%[4]s", "type": "account_events", "attributes": {"event_type": "created"}}
+	],
+	"links": {"self": "/v1/organisation/accounts/%[1]s"}
+}`
+
+func TestFetchAccountWithRelationships(t *testing.T) {
+
+	Convey("Given the API returns an account with a master account and events", t, func() {
+		ID := uuid.New()
+		MasterID := uuid.New()
+		EventID := uuid.New()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, accountWithRelationships, ID, MasterID, OrganisationID, EventID)
+		}))
+		defer server.Close()
+
+		AccountsService := NewClient().HTTPClient(HTTPClient).URL(server.URL).Build()
+
+		Convey("When I fetch the account by ID", func() {
+
+			resp, err := AccountsService.Fetch(ID)
+
+			Convey("Then the relationships are decoded", func() {
+				So(err, ShouldBeNil)
+				So(resp.AccountData.Relationships, ShouldNotBeNil)
+				So(resp.AccountData.Relationships.MasterAccount.Data, ShouldResemble, []ResourceIdentifier{{ID: MasterID.String(), Type: "accounts"}})
+				So(len(resp.Included), ShouldEqual, 2)
+			})
+
+			Convey("And the master account can be resolved from the included resources", func() {
+				master, ok := resp.MasterAccount()
+				So(ok, ShouldBeTrue)
+				So(master.ID, ShouldEqual, MasterID.String())
+				So(*master.Attributes.BankID, ShouldEqual, "400300")
+			})
+
+			Convey("And the master account keeps its timestamps and unknown attributes", func() {
+				master, _ := resp.MasterAccount()
+				So(master.CreatedOn, ShouldNotBeNil)
+				So(master.CreatedOn.Equal(time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)), ShouldBeTrue)
+				So(master.ModifiedOn, ShouldNotBeNil)
+				So(string(master.Attributes.Extra["status"]), ShouldEqual, `"confirmed"`)
+			})
+
+			Convey("And the account events can be resolved from the included resources", func() {
+				events := resp.Included.Resolve(resp.AccountData.Relationships.AccountEvents)
+				So(len(events), ShouldEqual, 1)
+
+				var attributes struct {
+					EventType string `json:"event_type"`
+				}
+				So(events[0].DecodeAttributes(&attributes), ShouldBeNil)
+				So(attributes.EventType, ShouldEqual, "created")
+			})
+
+		})
+
+	})
+
+}
+
+func TestRelationshipWithSingleResourceData(t *testing.T) {
+
+	Convey("When I decode a relationship given as a single object", t, func() {
+		var relationship Relationship
+		err := relationship.UnmarshalJSON([]byte(`{"data": {"id": "1", "type": "accounts"}}`))
+
+		Convey("Then it holds the one identifier", func() {
+			So(err, ShouldBeNil)
+			So(relationship.Data, ShouldResemble, []ResourceIdentifier{{ID: "1", Type: "accounts"}})
+		})
+
+		Convey("And it is encoded back as a single object", func() {
+			encoded, _ := relationship.MarshalJSON()
+			So(string(encoded), ShouldEqual, `{"data":{"id":"1","type":"accounts"}}`)
+		})
+
+	})
+
+}
+
+func TestFetchAccountWithZonelessIncludedTimestamps(t *testing.T) {
+
+	Convey("Given the API includes a master account whose timestamps have no timezone", t, func() {
+		ID := uuid.New()
+		MasterID := uuid.New()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body := fmt.Sprintf(accountWithRelationships, ID, MasterID, OrganisationID, uuid.New())
+			fmt.Fprint(w, strings.Replace(body, `"created_on": "2021-03-01T10:00:00Z"`, `"created_on": "2021-03-01T10:00:00.123"`, 1))
+		}))
+		defer server.Close()
+
+		AccountsService := NewClient().HTTPClient(HTTPClient).URL(server.URL).Build()
+
+		Convey("When I fetch the account by ID", func() {
+
+			resp, err := AccountsService.Fetch(ID)
+
+			Convey("Then the included resource is decoded with its timestamp in UTC", func() {
+				So(err, ShouldBeNil)
+				master, ok := resp.Included.Find("accounts", MasterID.String())
+				So(ok, ShouldBeTrue)
+				So(master.CreatedOn, ShouldNotBeNil)
+				So(master.CreatedOn.Equal(time.Date(2021, 3, 1, 10, 0, 0, 123000000, time.UTC)), ShouldBeTrue)
+				So(master.ModifiedOn.Equal(time.Date(2021, 3, 2, 10, 0, 0, 0, time.UTC)), ShouldBeTrue)
+			})
+
+			Convey("And the master account resolved from it keeps the timestamp", func() {
+				master, ok := resp.MasterAccount()
+				So(ok, ShouldBeTrue)
+				So(master.CreatedOn.Equal(time.Date(2021, 3, 1, 10, 0, 0, 123000000, time.UTC)), ShouldBeTrue)
+			})
+
+		})
+
+	})
+
+}
@@ -0,0 +1,398 @@
+package accounts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Sender sends a request to the API and returns its response, as http.Client.Do does
+type Sender func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps the sending of every request to the API, e.g. to set headers, log or measure them
+//
+// Each attempt of a retried request goes through the middleware
+type Middleware func(next Sender) Sender
+
+// transport is what the clients of every resource share: how requests reach the API and how its
+// responses are read
+type transport struct {
+	url            string
+	httpClient     http.Client
+	strictDecoding bool
+	maxBodySize    int64
+	retry          RetryPolicy
+	send           Sender
+	flights        *flightGroup
+}
+
+// transportOptions holds the options every client builder takes, whatever its resource
+//
+// It is embedded by the builders, its setters returning self, the builder embedding it, to keep chaining
+type transportOptions[B any] struct {
+	self           B
+	url            string
+	httpClient     http.Client
+	strictDecoding bool
+	maxBodySize    int64
+	retry          RetryPolicy
+	middleware     []Middleware
+}
+
+func (o *transportOptions[B]) URL(value string) B {
+	o.url = value
+	return o.self
+}
+
+func (o *transportOptions[B]) HTTPClient(value http.Client) B {
+	o.httpClient = value
+	return o.self
+}
+
+// StrictDecoding makes the client fail on response fields it does not know about
+//
+// It is meant for contract testing against the API; by default unknown fields are kept in Extra
+func (o *transportOptions[B]) StrictDecoding(value bool) B {
+	o.strictDecoding = value
+	return o.self
+}
+
+// MaxBodySize is the largest response body the client reads, in bytes, 10 MiB when not set
+func (o *transportOptions[B]) MaxBodySize(value int64) B {
+	o.maxBodySize = value
+	return o.self
+}
+
+// Retry makes the client retry requests that failed for a transient reason, none being retried by default
+func (o *transportOptions[B]) Retry(value RetryPolicy) B {
+	o.retry = value
+	return o.self
+}
+
+// Middleware wraps the sending of every request, the first middleware given being the outermost
+func (o *transportOptions[B]) Middleware(values ...Middleware) B {
+	o.middleware = append(o.middleware, values...)
+	return o.self
+}
+
+func (o transportOptions[B]) transport() *transport {
+	t := &transport{
+		url:            o.url,
+		httpClient:     o.httpClient,
+		strictDecoding: o.strictDecoding,
+		maxBodySize:    o.maxBodySize,
+		retry:          o.retry,
+		flights:        newFlightGroup(),
+	}
+	if t.maxBodySize < 1 {
+		t.maxBodySize = defaultMaxBodySize
+	}
+
+	t.send = t.httpClient.Do
+	for i := len(o.middleware) - 1; i >= 0; i-- {
+		t.send = o.middleware[i](t.send)
+	}
+
+	return t
+}
+
+// do sends a request through the middleware, retrying it as the retry policy allows
+func (t *transport) do(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := t.send(req)
+		if attempt >= t.retry.Attempts || !retryable(req.Method, resp, err) || req.Context().Err() != nil {
+			return resp, err
+		}
+		if req.Body != nil && req.GetBody == nil {
+			return resp, err
+		}
+
+		wait := t.retry.wait(attempt, resp)
+		if resp != nil {
+			drainAndClose(resp.Body)
+		}
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// queryParam is a parameter of the query of a list request, kept in order so that equal lists get equal URLs
+type queryParam struct {
+	name  string
+	value string
+}
+
+// buildQueryURL appends the page and the params to the URL of a resource
+func buildQueryURL(resourceURL string, page *Page, params []queryParam) string {
+
+	var query []string
+	if page != nil {
+		query = append(query, fmt.Sprintf("page[number]=%s", strconv.Itoa(page.Number)))
+		if page.Size > 0 {
+			query = append(query, fmt.Sprintf("page[size]=%s", strconv.Itoa(page.Size)))
+		}
+	}
+	for _, param := range params {
+		query = append(query, fmt.Sprintf("%s=%s", param.name, url.QueryEscape(param.value)))
+	}
+
+	if len(query) == 0 {
+		return resourceURL
+	}
+	return resourceURL + "?" + strings.Join(query, "&")
+}
+
+// resource gives the operations of a JSON:API resource of the Form3 API, S being the payload of a
+// single resource and L the payload of a list of them
+//
+// A client for a new resource declares its payloads and is built on a resource, getting the paging,
+// error, retry and middleware behaviour of every other client
+type resource[S any, L any] struct {
+	transport *transport
+	path      string
+	// name and plural name the resource in error messages, e.g. account and accounts
+	name   string
+	plural string
+}
+
+func newResource[S any, L any](t *transport, path string, name string, plural string) resource[S, L] {
+	return resource[S, L]{transport: t, path: path, name: name, plural: plural}
+}
+
+// create posts body, the data of a new resource wrapped in a data member
+func (r resource[S, L]) create(ctx context.Context, body interface{}) (S, error) {
+	return r.send(ctx, "POST", fmt.Sprintf("%s%s", r.transport.url, r.path), body, "create", "creating")
+}
+
+// update patches the resource with body, the changed data wrapped in a data member
+func (r resource[S, L]) update(ctx context.Context, id string, body interface{}) (S, error) {
+	return r.send(ctx, "PATCH", fmt.Sprintf("%s%s/%s", r.transport.url, r.path, id), body, "update", "updating")
+}
+
+func (r resource[S, L]) send(ctx context.Context, method string, target string, body interface{}, operation string, doing string) (S, error) {
+	var result S
+
+	req, err := encodeJSON(body)
+	if err != nil {
+		return result, &RequestError{Message: "An error has occured while encoding request", Err: err}
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(req))
+	if err != nil {
+		return result, &RequestError{Message: fmt.Sprintf("An error has occured while constructing %s request", operation), Err: err}
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.transport.do(httpReq)
+	if err != nil {
+		return result, &RequestError{Message: fmt.Sprintf("An error has occured while %s %s", doing, r.name), Err: err}
+	}
+	defer drainAndClose(resp.Body)
+
+	if err := r.transport.decodeErrorResponse(resp); err != nil {
+		return result, err
+	}
+	if err := r.transport.decodeResponse(resp, &result); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// fetch fetches a resource unless its entity tag still matches etag, returning the entity tag of the
+// resource and whether it was not modified
+//
+// Concurrent fetches of the same resource share a single request, and so the same result
+func (r resource[S, L]) fetch(ctx context.Context, id string, etag string) (S, string, bool, error) {
+	type fetched struct {
+		result      S
+		etag        string
+		notModified bool
+	}
+	var result S
+
+	target := fmt.Sprintf("%s%s/%s", r.transport.url, r.path, id)
+	value, err := r.transport.flights.do(ctx, fmt.Sprintf("fetch %s %s", target, etag), func(ctx context.Context) (interface{}, error) {
+		result, etag, notModified, err := r.fetchOnce(ctx, target, etag)
+		return fetched{result: result, etag: etag, notModified: notModified}, err
+	})
+	if err == ctx.Err() && err != nil {
+		return result, "", false, &RequestError{Message: fmt.Sprintf("An error has occured while fetching %s", r.name), Err: err}
+	}
+	if err != nil {
+		return result, "", false, err
+	}
+
+	f := value.(fetched)
+	return f.result, f.etag, f.notModified, nil
+}
+
+func (r resource[S, L]) fetchOnce(ctx context.Context, target string, etag string) (S, string, bool, error) {
+	var result S
+
+	req, err := http.NewRequestWithContext(ctx, "GET", target, nil)
+	if err != nil {
+		return result, "", false, &RequestError{Message: "An error has occured while constructing fetch request", Err: err}
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := r.transport.do(req)
+	if err != nil {
+		return result, "", false, &RequestError{Message: fmt.Sprintf("An error has occured while fetching %s", r.name), Err: err}
+	}
+	defer drainAndClose(resp.Body)
+
+	if etag != "" && resp.StatusCode == http.StatusNotModified {
+		return result, etag, true, nil
+	}
+	if err := r.transport.decodeErrorResponse(resp); err != nil {
+		return result, "", false, err
+	}
+	if err := r.transport.decodeResponse(resp, &result); err != nil {
+		return result, "", false, err
+	}
+
+	return result, resp.Header.Get("ETag"), false, nil
+}
+
+// list lists the resources of a page matching the params
+//
+// Concurrent lists of the same page with the same params share a single request, and so the same result
+func (r resource[S, L]) list(ctx context.Context, page *Page, params []queryParam) (L, error) {
+	return r.listAt(ctx, buildQueryURL(r.transport.url+r.path, page, params))
+}
+
+// listLink lists the page a link of a list points to, e.g. its last link, the link being relative to the API
+// unless absolute
+func (r resource[S, L]) listLink(ctx context.Context, link string) (L, error) {
+	if strings.HasPrefix(link, "/") {
+		link = r.transport.url + link
+	}
+	return r.listAt(ctx, link)
+}
+
+func (r resource[S, L]) listAt(ctx context.Context, listURL string) (L, error) {
+	var result L
+
+	value, err := r.transport.flights.do(ctx, "list "+listURL, func(ctx context.Context) (interface{}, error) {
+		return r.listOnce(ctx, listURL)
+	})
+	if err == ctx.Err() && err != nil {
+		return result, &RequestError{Message: fmt.Sprintf("An error has occured while listing %s", r.plural), Err: err}
+	}
+	if err != nil {
+		return result, err
+	}
+
+	return value.(L), nil
+}
+
+func (r resource[S, L]) listOnce(ctx context.Context, listURL string) (L, error) {
+	var result L
+
+	req, err := http.NewRequestWithContext(ctx, "GET", listURL, nil)
+	if err != nil {
+		return result, &RequestError{Message: "An error has occured while constructing list request", Err: err}
+	}
+
+	resp, err := r.transport.do(req)
+	if err != nil {
+		return result, &RequestError{Message: fmt.Sprintf("An error has occured while listing %s", r.plural), Err: err}
+	}
+	defer drainAndClose(resp.Body)
+
+	if err := r.transport.decodeErrorResponse(resp); err != nil {
+		return result, err
+	}
+	if err := r.transport.decodeResponse(resp, &result); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// delete deletes the given version of a resource
+func (r resource[S, L]) delete(ctx context.Context, id string, version int) error {
+
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s%s/%s?version=%s", r.transport.url, r.path, id, strconv.Itoa(version)), new(bytes.Buffer))
+	if err != nil {
+		return &RequestError{Message: "An error has occured while constructing delete request", Err: err}
+	}
+
+	resp, err := r.transport.do(req)
+	if err != nil {
+		return &RequestError{Message: fmt.Sprintf("An error has occured while deleting %s", r.name), Err: err}
+	}
+	defer drainAndClose(resp.Body)
+
+	return r.transport.decodeErrorResponse(resp)
+}
+
+func (t *transport) decodeResponse(resp *http.Response, result interface{}) error {
+	body, err := t.readBody(resp)
+	if err != nil {
+		return err
+	}
+	if !isJSON(resp.Header.Get("Content-Type")) {
+		return &ResponseError{Message: fmt.Sprintf("Unexpected content type [%s] in response", resp.Header.Get("Content-Type")), Body: snippet(body)}
+	}
+
+	if err := decodeJSON(body, result); err != nil {
+		return &ResponseError{Message: "An error has occured while decoding response", Err: err, Body: snippet(body)}
+	}
+
+	// unknown fields are kept in the Extra maps of the response, which strict decoding requires to be empty
+	if t.strictDecoding {
+		if fields := extraFields(reflect.ValueOf(result), ""); len(fields) > 0 {
+			return &ResponseError{Message: fmt.Sprintf("Unknown fields [%s] in response", strings.Join(fields, " ")), Body: snippet(body)}
+		}
+	}
+
+	return nil
+}
+
+// decodeErrorResponse returns the error the API answered with, any 2xx status being a success
+//
+// Bodies that are not JSON, such as the HTML pages of proxies, give an APIError describing the status
+func (t *transport) decodeErrorResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	body, err := t.readBody(resp)
+	if err != nil {
+		return err
+	}
+
+	unexpected := &APIError{StatusCode: resp.StatusCode, Message: fmt.Sprintf("Unexpected response [%s]", resp.Status), Body: snippet(body)}
+	if len(bytes.TrimSpace(body)) == 0 || !isJSON(resp.Header.Get("Content-Type")) {
+		return unexpected
+	}
+
+	var result ErrorResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return &ResponseError{Message: "An error has occured while decoding error response", Err: err, Body: snippet(body)}
+	}
+	if result.ErrorMessage == "" {
+		return unexpected
+	}
+
+	return &APIError{StatusCode: resp.StatusCode, Message: result.ErrorMessage, Body: snippet(body)}
+}
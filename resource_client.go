@@ -0,0 +1,111 @@
+package accounts
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/google/uuid"
+)
+
+// dataRequest is the request payload of a resource, D being its data
+type dataRequest[D any] struct {
+	Data D `json:"data"`
+}
+
+// resourceClient gives the operations of a resource and their WithContext variants, D being the data of a
+// single resource, S the payload of a single resource and L the payload of a list of them
+//
+// The client of a resource embeds it, getting Create, Fetch, List, Update and Delete; the data sent by
+// Create and Update go through prepare, which validates them and may set defaults of their own, the data
+// of Update being partial
+type resourceClient[D any, S any, L any] struct {
+	resource resource[S, L]
+	// kind is the type of the resource, which the type of the data sent defaults to
+	kind    string
+	prepare func(data *D, partial bool) error
+}
+
+func newResourceClient[D any, S any, L any](r resource[S, L], kind string, prepare func(data *D, partial bool) error) resourceClient[D, S, L] {
+	return resourceClient[D, S, L]{resource: r, kind: kind, prepare: prepare}
+}
+
+// Create a resource
+//
+// The request is pre-validated to avoid unnecessary Bad Request; its type defaults to the type of the resource
+func (c resourceClient[D, S, L]) Create(request D) (S, error) {
+	return c.CreateWithContext(context.Background(), request)
+}
+
+// CreateWithContext creates a resource until the context is done
+func (c resourceClient[D, S, L]) CreateWithContext(ctx context.Context, request D) (S, error) {
+	if err := c.prepare(&request, false); err != nil {
+		var result S
+		return result, err
+	}
+	setDefault(&request, "Type", c.kind)
+
+	return c.resource.create(ctx, dataRequest[D]{Data: request})
+}
+
+// Fetch a resource
+func (c resourceClient[D, S, L]) Fetch(id uuid.UUID) (S, error) {
+	return c.FetchWithContext(context.Background(), id)
+}
+
+// FetchWithContext fetches a resource until the context is done
+func (c resourceClient[D, S, L]) FetchWithContext(ctx context.Context, id uuid.UUID) (S, error) {
+	result, _, _, err := c.resource.fetch(ctx, id.String(), "")
+	return result, err
+}
+
+// List resources, those of an organisation when filtered by organisation ID
+func (c resourceClient[D, S, L]) List(page *Page, filter *Filter) (L, error) {
+	return c.ListWithContext(context.Background(), page, filter)
+}
+
+// ListWithContext lists resources until the context is done
+func (c resourceClient[D, S, L]) ListWithContext(ctx context.Context, page *Page, filter *Filter) (L, error) {
+	return c.resource.list(ctx, page, filterParams(filter))
+}
+
+// Update a resource
+//
+// The version of the request must be the current version of the resource. The ID and type of the request
+// default to those of the resource
+func (c resourceClient[D, S, L]) Update(id uuid.UUID, request D) (S, error) {
+	return c.UpdateWithContext(context.Background(), id, request)
+}
+
+// UpdateWithContext updates a resource until the context is done
+func (c resourceClient[D, S, L]) UpdateWithContext(ctx context.Context, id uuid.UUID, request D) (S, error) {
+	if err := c.prepare(&request, true); err != nil {
+		var result S
+		return result, err
+	}
+	setDefault(&request, "ID", id.String())
+	setDefault(&request, "Type", c.kind)
+
+	return c.resource.update(ctx, id.String(), dataRequest[D]{Data: request})
+}
+
+// Delete a resource
+func (c resourceClient[D, S, L]) Delete(id uuid.UUID, version int) (bool, error) {
+	return c.DeleteWithContext(context.Background(), id, version)
+}
+
+// DeleteWithContext deletes a resource until the context is done
+func (c resourceClient[D, S, L]) DeleteWithContext(ctx context.Context, id uuid.UUID, version int) (bool, error) {
+	if err := c.resource.delete(ctx, id.String(), version); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// setDefault sets the string field of data named name to value when it is empty
+func setDefault(data interface{}, name string, value string) {
+	field := reflect.ValueOf(data).Elem().FieldByName(name)
+	if field.IsValid() && field.Kind() == reflect.String && field.String() == "" {
+		field.SetString(value)
+	}
+}
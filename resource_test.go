@@ -0,0 +1,244 @@
+package accounts
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/razvanmuscalu/form3-accounts-client/internal/patch"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// flakyServer answers the first failures requests with status, then succeeds with body
+func flakyServer(failures int32, status int, retryAfter string, body string) (*httptest.Server, *int32) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(body))
+	}))
+	return server, &requests
+}
+
+func TestUpdate(t *testing.T) {
+
+	Convey("Given an API accepting updates", t, func() {
+		id := uuid.New()
+		var method, target string
+		var request accountDataRequest
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method, target = r.Method, r.URL.Path
+			json.NewDecoder(r.Body).Decode(&request)
+			w.Write([]byte(`{"data":{"id":"` + id.String() + `","type":"accounts","version":3,"attributes":{"country":"GB","bank_account_name":"Sam Holder"}}}`))
+		}))
+		defer server.Close()
+
+		AccountsService := NewClient().HTTPClient(HTTPClient).URL(server.URL).Build()
+
+		Convey("When I update the name of an account", func() {
+			resp, err := AccountsService.Update(id, NewAccountData().Version(2).Attributes(NewAccount().BankAccountName("Sam Holder").Build()).Build())
+
+			Convey("Then the change is patched with the ID, type and version of the account", func() {
+				So(err, ShouldBeNil)
+				So(method, ShouldEqual, "PATCH")
+				So(target, ShouldEqual, path+"/"+id.String())
+				So(request.AccountData.ID, ShouldEqual, id.String())
+				So(request.AccountData.Type, ShouldEqual, accountsType)
+				So(*request.AccountData.Version, ShouldEqual, 2)
+			})
+
+			Convey("And the updated account is returned", func() {
+				So(*resp.AccountData.Version, ShouldEqual, 3)
+				So(*resp.AccountData.Attributes.BankAccountName, ShouldEqual, "Sam Holder")
+			})
+		})
+
+		Convey("When I update an account with an invalid attribute", func() {
+			_, err := AccountsService.Update(id, NewAccountData().Version(2).Attributes(NewAccount().BIC("invalid").Build()).Build())
+
+			Convey("Then it is rejected before reaching the API", func() {
+				So(err, ShouldBeError, "Invalid BIC [invalid]")
+				So(method, ShouldEqual, "")
+			})
+		})
+	})
+}
+
+func TestPatchAccount(t *testing.T) {
+
+	Convey("Given the attributes of an account", t, func() {
+		current := NewAccount().Country("GB").BankID("400300").BankAccountName("Sam Holder").Build()
+
+		Convey("When they are patched without a country", func() {
+			attributes, err := patch.Attributes(NewAccount().BankAccountName("Samantha Holder").JointAccount(true).Build(), "country")
+			So(err, ShouldBeNil)
+			So(string(attributes), ShouldNotContainSubstring, "country")
+			patched, err := patch.Merge(current, attributes)
+
+			Convey("Then only the attributes set are changed", func() {
+				So(err, ShouldBeNil)
+				So(patched.Country, ShouldEqual, "GB")
+				So(*patched.BankID, ShouldEqual, "400300")
+				So(*patched.BankAccountName, ShouldEqual, "Samantha Holder")
+				So(*patched.JointAccount, ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestRetry(t *testing.T) {
+
+	Convey("Given an API that is unavailable for two requests", t, func() {
+		server, requests := flakyServer(2, http.StatusServiceUnavailable, "0", `{"data":{"id":"1","attributes":{"country":"GB"}}}`)
+		defer server.Close()
+
+		Convey("When I fetch an account with retries", func() {
+			resp, err := NewClient().HTTPClient(HTTPClient).URL(server.URL).
+				Retry(RetryPolicy{Attempts: 3, Backoff: time.Millisecond}).Build().Fetch(uuid.New())
+
+			Convey("Then it succeeds on the third attempt", func() {
+				So(err, ShouldBeNil)
+				So(resp.AccountData.ID, ShouldEqual, "1")
+				So(atomic.LoadInt32(requests), ShouldEqual, 3)
+			})
+		})
+
+		Convey("When I fetch an account with too few attempts", func() {
+			_, err := NewClient().HTTPClient(HTTPClient).URL(server.URL).
+				Retry(RetryPolicy{Attempts: 2, Backoff: time.Millisecond}).Build().Fetch(uuid.New())
+
+			Convey("Then the last error is returned", func() {
+				var apiErr *APIError
+				So(errors.As(err, &apiErr), ShouldBeTrue)
+				So(apiErr.StatusCode, ShouldEqual, http.StatusServiceUnavailable)
+				So(atomic.LoadInt32(requests), ShouldEqual, 2)
+			})
+		})
+
+		Convey("When I fetch an account without retries", func() {
+			_, err := NewClient().HTTPClient(HTTPClient).URL(server.URL).Build().Fetch(uuid.New())
+
+			Convey("Then a single request is sent", func() {
+				So(err, ShouldNotBeNil)
+				So(atomic.LoadInt32(requests), ShouldEqual, 1)
+			})
+		})
+	})
+
+	Convey("Given an API that is rate limited for one request", t, func() {
+		var bodies []string
+		var mutex sync.Mutex
+		var requests int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			raw, _ := ioutil.ReadAll(r.Body)
+			mutex.Lock()
+			bodies = append(bodies, string(raw))
+			mutex.Unlock()
+			if atomic.AddInt32(&requests, 1) == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"data":{"id":"1","attributes":{"country":"GB"}}}`))
+		}))
+		defer server.Close()
+
+		Convey("When I create an account with retries", func() {
+			started := time.Now()
+			_, err := NewClient().HTTPClient(HTTPClient).URL(server.URL).
+				Retry(RetryPolicy{Attempts: 2, Backoff: time.Millisecond}).Build().
+				Create(NewAccountData().ID(uuid.New().String()).Attributes(NewAccount().Country("GB").Build()).Build())
+
+			Convey("Then the same body is sent again after the time asked by the API", func() {
+				So(err, ShouldBeNil)
+				So(time.Since(started), ShouldBeGreaterThanOrEqualTo, time.Second)
+				So(len(bodies), ShouldEqual, 2)
+				So(bodies[1], ShouldEqual, bodies[0])
+			})
+		})
+	})
+
+	Convey("Given an API behind a failing gateway", t, func() {
+		server, requests := flakyServer(1, http.StatusBadGateway, "", `{"data":{"id":"1","attributes":{"country":"GB"}}}`)
+		defer server.Close()
+
+		Convey("When I create an account with retries", func() {
+			_, err := NewClient().HTTPClient(HTTPClient).URL(server.URL).
+				Retry(RetryPolicy{Attempts: 3, Backoff: time.Millisecond}).Build().
+				Create(NewAccountData().ID(uuid.New().String()).Attributes(NewAccount().Country("GB").Build()).Build())
+
+			Convey("Then it is not retried as it may have been processed", func() {
+				So(err, ShouldNotBeNil)
+				So(atomic.LoadInt32(requests), ShouldEqual, 1)
+			})
+		})
+	})
+}
+
+func TestRetryPolicyWait(t *testing.T) {
+
+	Convey("Given a retry policy", t, func() {
+		policy := RetryPolicy{Attempts: 10, Backoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+		Convey("Then the backoff doubles up to the maximum", func() {
+			So(policy.wait(1, nil), ShouldEqual, 100*time.Millisecond)
+			So(policy.wait(2, nil), ShouldEqual, 200*time.Millisecond)
+			So(policy.wait(4, nil), ShouldEqual, 800*time.Millisecond)
+			So(policy.wait(5, nil), ShouldEqual, time.Second)
+		})
+
+		Convey("Then a Retry-After header takes precedence, within the maximum", func() {
+			resp := &http.Response{Header: http.Header{"Retry-After": []string{"0"}}}
+			So(policy.wait(3, resp), ShouldEqual, 0)
+
+			resp.Header.Set("Retry-After", "120")
+			So(policy.wait(1, resp), ShouldEqual, time.Second)
+		})
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+
+	Convey("Given an API that is unavailable for one request", t, func() {
+		server, _ := flakyServer(1, http.StatusServiceUnavailable, "0", `{"data":{"id":"1","attributes":{"country":"GB"}}}`)
+		defer server.Close()
+
+		var calls []string
+		named := func(name string) Middleware {
+			return func(next Sender) Sender {
+				return func(req *http.Request) (*http.Response, error) {
+					calls = append(calls, name)
+					req.Header.Set("X-Middleware", name)
+					return next(req)
+				}
+			}
+		}
+
+		Convey("When I fetch an account through middleware with retries", func() {
+			_, err := NewClient().HTTPClient(HTTPClient).URL(server.URL).
+				Retry(RetryPolicy{Attempts: 2, Backoff: time.Millisecond}).
+				Middleware(named("outer"), named("inner")).Build().Fetch(uuid.New())
+
+			Convey("Then every attempt goes through the middleware, the first given being the outermost", func() {
+				So(err, ShouldBeNil)
+				So(calls, ShouldResemble, []string{"outer", "inner", "outer", "inner"})
+			})
+		})
+	})
+}
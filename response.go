@@ -1,15 +1,25 @@
 package accounts
 
+import "encoding/json"
+
 // Single is the response payload when fetching an individual account
 type Single struct {
-	AccountData accountData `json:"data"`
+	AccountData AccountData `json:"data"`
+	Included    Included    `json:"included,omitempty"`
 	Links       Links       `json:"links"`
+
+	// Extra holds the members of the response that this library does not know about
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // List is the response payload when requesting a list of accounts
 type List struct {
-	AccountData *[]accountData `json:"data,omitempty"`
+	AccountData *[]AccountData `json:"data,omitempty"`
+	Included    Included       `json:"included,omitempty"`
 	Links       Links          `json:"links"`
+
+	// Extra holds the members of the response that this library does not know about
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // Links holds pointers to the first, last and self objects returned in the response
@@ -19,6 +29,27 @@ type Links struct {
 	Next  *string `json:"next,omitempty"`
 	Prev  *string `json:"prev,omitempty"`
 	Self  string  `json:"self"`
+
+	// Extra holds the links that this library does not know about
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the response, keeping the members this library does not know about in Extra
+func (s *Single) UnmarshalJSON(data []byte) error {
+	type plain Single
+	return decodeJSON(data, (*plain)(s))
+}
+
+// UnmarshalJSON decodes the response, keeping the members this library does not know about in Extra
+func (l *List) UnmarshalJSON(data []byte) error {
+	type plain List
+	return decodeJSON(data, (*plain)(l))
+}
+
+// UnmarshalJSON decodes the links, keeping those this library does not know about in Extra
+func (l *Links) UnmarshalJSON(data []byte) error {
+	type plain Links
+	return decodeJSON(data, (*plain)(l))
 }
 
 // ErrorResponse represents a failed Form3 Accounts API response
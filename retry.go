@@ -0,0 +1,104 @@
+package accounts
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy tells how requests that failed for a transient reason are retried
+//
+// Requests answered with 429 Too Many Requests or 503 Service Unavailable were not processed and are
+// retried whatever their method; connection failures, 502 Bad Gateway and 504 Gateway Timeout are only
+// retried for fetches, lists and deletes, as the API may have processed them
+type RetryPolicy struct {
+	// Attempts is the number of times a request is sent at most, no retry being made below 2
+	Attempts int
+	// Backoff is the wait before the first retry, doubled for each of the next ones, 100ms when not set
+	Backoff time.Duration
+	// MaxBackoff caps the wait between retries, including the one asked by a Retry-After header, 30s when not set
+	MaxBackoff time.Duration
+}
+
+const (
+	defaultBackoff    = 100 * time.Millisecond
+	defaultMaxBackoff = 30 * time.Second
+)
+
+// wait returns how long to wait before the given retry, starting at 1, preferring the Retry-After of resp
+func (rp RetryPolicy) wait(retry int, resp *http.Response) time.Duration {
+	backoff, maxBackoff := rp.Backoff, rp.MaxBackoff
+	if backoff <= 0 {
+		backoff = defaultBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+
+	wait := backoff
+	for i := 1; i < retry && wait < maxBackoff; i++ {
+		wait *= 2
+	}
+	if resp != nil {
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			wait = retryAfter
+		}
+	}
+	if wait > maxBackoff {
+		wait = maxBackoff
+	}
+
+	return wait
+}
+
+// retryable tells whether a request with the given method that got resp or err may be sent again
+func retryable(method string, resp *http.Response, err error) bool {
+	idempotent := method == "GET" || method == "DELETE"
+	if err != nil {
+		return idempotent
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent
+	}
+	return false
+}
+
+// parseRetryAfter reads a Retry-After header, given either in seconds or as an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	return 0, false
+}
+
+// sleep waits for d unless the context is done first
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,232 @@
+package accounts
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+)
+
+// ScanResult is a page of accounts returned by a Scanner, or the error that stopped the scan
+type ScanResult struct {
+	Page int
+	List List
+	Err  error
+}
+
+// Scanner lists every page of accounts, fetching pages concurrently
+type Scanner interface {
+	// Scan sends the pages of accounts matching filter in page order on the returned channel,
+	// which is closed once the last page has been sent or after the first error
+	//
+	// When the context is cancelled the outstanding requests are aborted and the channel is
+	// closed, possibly without an error being sent
+	Scan(ctx context.Context, filter *Filter) <-chan ScanResult
+}
+
+type scanner struct {
+	client      ContextClient
+	pageSize    int
+	parallelism int
+}
+
+// ScannerBuilder is used to create a Scanner
+type ScannerBuilder interface {
+	Client(ContextClient) ScannerBuilder
+	PageSize(int) ScannerBuilder
+	Parallelism(int) ScannerBuilder
+	Build() Scanner
+}
+
+type scannerBuilder struct {
+	client      ContextClient
+	pageSize    int
+	parallelism int
+}
+
+func (sb *scannerBuilder) Client(value ContextClient) ScannerBuilder {
+	sb.client = value
+	return sb
+}
+
+// PageSize is the number of accounts requested per page, 100 when not set
+func (sb *scannerBuilder) PageSize(value int) ScannerBuilder {
+	sb.pageSize = value
+	return sb
+}
+
+// Parallelism bounds both the pages fetched concurrently and the pages held waiting for
+// the ones before them to be consumed, 4 when not set
+func (sb *scannerBuilder) Parallelism(value int) ScannerBuilder {
+	sb.parallelism = value
+	return sb
+}
+
+func (sb *scannerBuilder) Build() Scanner {
+	s := &scanner{
+		client:      sb.client,
+		pageSize:    sb.pageSize,
+		parallelism: sb.parallelism,
+	}
+	if s.pageSize < 1 {
+		s.pageSize = 100
+	}
+	if s.parallelism < 1 {
+		s.parallelism = 4
+	}
+
+	return s
+}
+
+// NewScanner is used to create a ScannerBuilder
+func NewScanner() ScannerBuilder {
+	return &scannerBuilder{}
+}
+
+// linkLister lists the page a link of a list points to, as the Client of this package does
+type linkLister interface {
+	listLink(ctx context.Context, link string) (List, error)
+}
+
+// errLinkNotSupported is returned when following a link through a client that cannot follow links
+var errLinkNotSupported = errors.New("links cannot be followed by the client")
+
+// linkedPage reads the page number of a link, which is only known when the link gives it as a number
+func linkedPage(link *string) (int, bool) {
+	if link == nil {
+		return 0, false
+	}
+
+	parsed, err := url.Parse(*link)
+	if err != nil {
+		return 0, false
+	}
+	number, err := strconv.Atoi(parsed.Query().Get("page[number]"))
+	if err != nil {
+		return 0, false
+	}
+
+	return number, true
+}
+
+// lastPage returns the number of the last page linked by a list
+//
+// The API names the last page, e.g. page[number]=last, rather than numbering it: the last page is then
+// fetched once and its number read from its self link
+func (s scanner) lastPage(ctx context.Context, links Links) (int, bool) {
+	if number, ok := linkedPage(links.Last); ok || links.Last == nil {
+		return number, ok
+	}
+
+	lister, ok := s.client.(linkLister)
+	if !ok {
+		return 0, false
+	}
+	last, err := lister.listLink(ctx, *links.Last)
+	if err != nil {
+		return 0, false
+	}
+
+	return linkedPage(&last.Links.Self)
+}
+
+func hasNext(list List) bool {
+	return list.AccountData != nil && len(*list.AccountData) > 0 && list.Links.Next != nil && *list.Links.Next != ""
+}
+
+func (s scanner) Scan(ctx context.Context, filter *Filter) <-chan ScanResult {
+	results := make(chan ScanResult, s.parallelism)
+	go s.scan(ctx, filter, results)
+	return results
+}
+
+func (s scanner) scan(ctx context.Context, filter *Filter, results chan<- ScanResult) {
+	defer close(results)
+
+	// requests run with their own context, cancelled on the first error, while results are
+	// sent for as long as the caller's context is not done
+	requestCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	send := func(result ScanResult) bool {
+		select {
+		case results <- result:
+			return result.Err == nil
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	first, err := s.client.ListWithContext(requestCtx, &Page{Number: 0, Size: s.pageSize}, filter)
+	if !send(ScanResult{Page: 0, List: first, Err: err}) || !hasNext(first) {
+		return
+	}
+
+	last, ok := s.lastPage(requestCtx, first.Links)
+	if !ok {
+		s.scanSequentially(requestCtx, filter, send)
+		return
+	}
+
+	type fetched struct {
+		number int
+		list   List
+		err    error
+	}
+	pages := make(chan fetched)
+
+	// a token is taken before fetching a page and given back once the page has been sent,
+	// so that at most parallelism pages are in flight or waiting for the ones before them
+	tokens := make(chan struct{}, s.parallelism)
+	go func() {
+		for number := 1; number <= last; number++ {
+			select {
+			case tokens <- struct{}{}:
+			case <-requestCtx.Done():
+				return
+			}
+
+			go func(number int) {
+				list, err := s.client.ListWithContext(requestCtx, &Page{Number: number, Size: s.pageSize}, filter)
+				select {
+				case pages <- fetched{number: number, list: list, err: err}:
+				case <-requestCtx.Done():
+				}
+			}(number)
+		}
+	}()
+
+	waiting := make(map[int]fetched)
+	for next := 1; next <= last; {
+		select {
+		case page := <-pages:
+			if page.err != nil {
+				cancel()
+				send(ScanResult{Page: page.number, Err: page.err})
+				return
+			}
+			waiting[page.number] = page
+
+			for page, ok := waiting[next]; ok; page, ok = waiting[next] {
+				delete(waiting, next)
+				if !send(ScanResult{Page: next, List: page.list}) {
+					return
+				}
+				<-tokens
+				next++
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// scanSequentially follows the next links one page at a time, for when the last page cannot be found up front
+func (s scanner) scanSequentially(ctx context.Context, filter *Filter, send func(ScanResult) bool) {
+	for number := 1; ; number++ {
+		list, err := s.client.ListWithContext(ctx, &Page{Number: number, Size: s.pageSize}, filter)
+		if !send(ScanResult{Page: number, List: list, Err: err}) || !hasNext(list) {
+			return
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package accounts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// pagedServer serves pages of one account each, with the given last link, after a random delay
+// so that concurrent requests complete out of order; it fails on the failing page if not negative
+//
+// It serves the last page for page[number]=last, counting those requests, and numbers the self links
+// of its pages unless unnumbered
+type pagedServer struct {
+	pages        int
+	last         string
+	failing      int
+	unnumbered   bool
+	mutex        sync.Mutex
+	inFlight     int
+	maximum      int
+	lastRequests int
+}
+
+func (ps *pagedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ps.mutex.Lock()
+	ps.inFlight++
+	if ps.inFlight > ps.maximum {
+		ps.maximum = ps.inFlight
+	}
+	ps.mutex.Unlock()
+	defer func() {
+		ps.mutex.Lock()
+		ps.inFlight--
+		ps.mutex.Unlock()
+	}()
+
+	time.Sleep(time.Duration(rand.Intn(10)) * time.Millisecond)
+
+	number, _ := strconv.Atoi(r.URL.Query().Get("page[number]"))
+	if r.URL.Query().Get("page[number]") == "last" {
+		number = ps.pages - 1
+		ps.mutex.Lock()
+		ps.lastRequests++
+		ps.mutex.Unlock()
+	}
+	if number == ps.failing {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{"error_message": "internal error"}`)
+		return
+	}
+
+	data := []AccountData{NewAccountData().ID(strconv.Itoa(number)).Attributes(NewAccount().Country("GB").Build()).Build()}
+	list := List{AccountData: &data}
+	list.Links.Last = &ps.last
+	list.Links.Self = fmt.Sprintf("%s?page[number]=%d", path, number)
+	if ps.unnumbered {
+		list.Links.Self = r.URL.RequestURI()
+	}
+	if number < ps.pages-1 {
+		next := fmt.Sprintf("%s?page[number]=%d", path, number+1)
+		list.Links.Next = &next
+	}
+	json.NewEncoder(w).Encode(list)
+}
+
+func scanAll(results <-chan ScanResult) ([]string, error) {
+	var ids []string
+	for result := range results {
+		if result.Err != nil {
+			return ids, result.Err
+		}
+		for _, ad := range *result.List.AccountData {
+			ids = append(ids, ad.ID)
+		}
+	}
+	return ids, nil
+}
+
+func TestScanPagesInParallel(t *testing.T) {
+
+	Convey("Given 20 pages of accounts whose last page is linked", t, func() {
+		pages := &pagedServer{pages: 20, last: path + "?page[number]=19&page[size]=1", failing: -1}
+		server := httptest.NewServer(pages)
+		defer server.Close()
+
+		Scanner := NewScanner().Client(NewClient().HTTPClient(HTTPClient).URL(server.URL).Build()).PageSize(1).Parallelism(4).Build()
+
+		Convey("When I scan them with a parallelism of 4", func() {
+			ids, err := scanAll(Scanner.Scan(context.Background(), nil))
+
+			Convey("Then every page is returned in order", func() {
+				So(err, ShouldBeNil)
+				So(len(ids), ShouldEqual, 20)
+				for i, id := range ids {
+					So(id, ShouldEqual, strconv.Itoa(i))
+				}
+			})
+
+			Convey("And no more than 4 pages were fetched at once", func() {
+				So(pages.maximum, ShouldBeLessThanOrEqualTo, 4)
+			})
+		})
+
+	})
+
+}
+
+func TestScanPagesInParallelWhenLastPageIsNamed(t *testing.T) {
+
+	Convey("Given 20 pages of accounts whose last link names the last page, as the API does", t, func() {
+		pages := &pagedServer{pages: 20, last: path + "?page[number]=last&page[size]=1", failing: -1}
+		server := httptest.NewServer(pages)
+		defer server.Close()
+
+		Scanner := NewScanner().Client(NewClient().HTTPClient(HTTPClient).URL(server.URL).Build()).PageSize(1).Parallelism(4).Build()
+
+		Convey("When I scan them with a parallelism of 4", func() {
+			ids, err := scanAll(Scanner.Scan(context.Background(), nil))
+
+			Convey("Then every page is returned in order", func() {
+				So(err, ShouldBeNil)
+				So(len(ids), ShouldEqual, 20)
+				for i, id := range ids {
+					So(id, ShouldEqual, strconv.Itoa(i))
+				}
+			})
+
+			Convey("And the last page was fetched once to find its number", func() {
+				So(pages.lastRequests, ShouldEqual, 1)
+			})
+
+			Convey("And the pages were fetched in parallel, no more than 4 at once", func() {
+				So(pages.maximum, ShouldBeGreaterThan, 1)
+				So(pages.maximum, ShouldBeLessThanOrEqualTo, 4)
+			})
+		})
+
+	})
+
+}
+
+func TestScanPagesSequentiallyWhenLastPageIsUnknown(t *testing.T) {
+
+	Convey("Given 5 pages of accounts whose last page does not give its number", t, func() {
+		server := httptest.NewServer(&pagedServer{pages: 5, last: path + "?page[number]=last", failing: -1, unnumbered: true})
+		defer server.Close()
+
+		Scanner := NewScanner().Client(NewClient().HTTPClient(HTTPClient).URL(server.URL).Build()).PageSize(1).Build()
+
+		Convey("When I scan them", func() {
+			ids, err := scanAll(Scanner.Scan(context.Background(), nil))
+
+			Convey("Then the next links are followed until the last page", func() {
+				So(err, ShouldBeNil)
+				So(ids, ShouldResemble, []string{"0", "1", "2", "3", "4"})
+			})
+		})
+
+	})
+
+}
+
+func TestScanFailure(t *testing.T) {
+
+	Convey("Given 20 pages of accounts, one of which cannot be listed", t, func() {
+		server := httptest.NewServer(&pagedServer{pages: 20, last: path + "?page[number]=19", failing: 7})
+		defer server.Close()
+
+		Scanner := NewScanner().Client(NewClient().HTTPClient(HTTPClient).URL(server.URL).Build()).PageSize(1).Parallelism(3).Build()
+
+		Convey("When I scan them", func() {
+			ids, err := scanAll(Scanner.Scan(context.Background(), nil))
+
+			Convey("Then the scan stops with the error", func() {
+				So(err.Error(), ShouldEqual, "internal error")
+				So(len(ids), ShouldBeLessThanOrEqualTo, 7)
+			})
+		})
+
+	})
+
+}
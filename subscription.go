@@ -0,0 +1,195 @@
+package accounts
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const subscriptionsType = "subscriptions"
+
+// the transports notifications are sent over
+const (
+	CallbackTransportHTTP  = "http"
+	CallbackTransportQueue = "queue"
+)
+
+// the events of a record notifications are sent for
+const (
+	EventCreated = "created"
+	EventUpdated = "updated"
+	EventDeleted = "deleted"
+)
+
+// UserDefinedData is a key and value sent along with every notification of a subscription
+type UserDefinedData struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type Subscription struct {
+	CallbackURI       string            `json:"callback_uri,omitempty"`
+	CallbackTransport string            `json:"callback_transport,omitempty"`
+	RecordType        string            `json:"record_type,omitempty"`
+	EventType         string            `json:"event_type,omitempty"`
+	Deactivated       *bool             `json:"deactivated,omitempty"`
+	UserDefinedData   []UserDefinedData `json:"user_defined_data,omitempty"`
+
+	// Extra holds the attributes returned by the API that this library does not know about
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// SubscriptionBuilder returns a builder for Subscription struct
+type SubscriptionBuilder interface {
+	CallbackURI(string) SubscriptionBuilder
+	CallbackTransport(string) SubscriptionBuilder
+	RecordType(string) SubscriptionBuilder
+	EventType(string) SubscriptionBuilder
+	Deactivated(bool) SubscriptionBuilder
+	UserDefinedData(key string, value string) SubscriptionBuilder
+	Build() Subscription
+}
+
+type subscriptionBuilder struct {
+	callbackURI       string
+	callbackTransport string
+	recordType        string
+	eventType         string
+	deactivated       *bool
+	userDefinedData   []UserDefinedData
+}
+
+func (sb *subscriptionBuilder) CallbackURI(value string) SubscriptionBuilder {
+	sb.callbackURI = value
+	return sb
+}
+
+func (sb *subscriptionBuilder) CallbackTransport(value string) SubscriptionBuilder {
+	sb.callbackTransport = value
+	return sb
+}
+
+func (sb *subscriptionBuilder) RecordType(value string) SubscriptionBuilder {
+	sb.recordType = value
+	return sb
+}
+
+func (sb *subscriptionBuilder) EventType(value string) SubscriptionBuilder {
+	sb.eventType = value
+	return sb
+}
+
+func (sb *subscriptionBuilder) Deactivated(value bool) SubscriptionBuilder {
+	sb.deactivated = &value
+	return sb
+}
+
+// UserDefinedData adds a key and value to the data sent along with every notification
+func (sb *subscriptionBuilder) UserDefinedData(key string, value string) SubscriptionBuilder {
+	sb.userDefinedData = append(sb.userDefinedData, UserDefinedData{Key: key, Value: value})
+	return sb
+}
+
+func (sb *subscriptionBuilder) Build() Subscription {
+	return Subscription{
+		CallbackURI:       sb.callbackURI,
+		CallbackTransport: sb.callbackTransport,
+		RecordType:        sb.recordType,
+		EventType:         sb.eventType,
+		Deactivated:       sb.deactivated,
+		UserDefinedData:   sb.userDefinedData,
+	}
+}
+
+// NewSubscription is used to create a SubscriptionBuilder
+func NewSubscription() SubscriptionBuilder {
+	return &subscriptionBuilder{}
+}
+
+type SubscriptionData struct {
+	ID             string       `json:"id"`
+	OrganisationID string       `json:"organisation_id"`
+	Type           string       `json:"type"`
+	CreatedOn      *time.Time   `json:"created_on,omitempty"`
+	ModifiedOn     *time.Time   `json:"modified_on,omitempty"`
+	Version        *int         `json:"version,omitempty"`
+	Attributes     Subscription `json:"attributes"`
+
+	// Extra holds the fields returned by the API that this library does not know about
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// SubscriptionDataBuilder returns a builder for SubscriptionData struct
+type SubscriptionDataBuilder interface {
+	ID(string) SubscriptionDataBuilder
+	OrganisationID(string) SubscriptionDataBuilder
+	Type(string) SubscriptionDataBuilder
+	Version(int) SubscriptionDataBuilder
+	Attributes(Subscription) SubscriptionDataBuilder
+	Build() SubscriptionData
+}
+
+type subscriptionDataBuilder struct {
+	id                   string
+	organisationID       string
+	subscriptionDataType string
+	version              *int
+	attributes           Subscription
+}
+
+func (sb *subscriptionDataBuilder) ID(value string) SubscriptionDataBuilder {
+	sb.id = value
+	return sb
+}
+
+func (sb *subscriptionDataBuilder) OrganisationID(value string) SubscriptionDataBuilder {
+	sb.organisationID = value
+	return sb
+}
+
+func (sb *subscriptionDataBuilder) Type(value string) SubscriptionDataBuilder {
+	sb.subscriptionDataType = value
+	return sb
+}
+
+func (sb *subscriptionDataBuilder) Version(value int) SubscriptionDataBuilder {
+	sb.version = &value
+	return sb
+}
+
+func (sb *subscriptionDataBuilder) Attributes(value Subscription) SubscriptionDataBuilder {
+	sb.attributes = value
+	return sb
+}
+
+func (sb *subscriptionDataBuilder) Build() SubscriptionData {
+	return SubscriptionData{
+		ID:             sb.id,
+		OrganisationID: sb.organisationID,
+		Type:           sb.subscriptionDataType,
+		Version:        sb.version,
+		Attributes:     sb.attributes,
+	}
+}
+
+// NewSubscriptionData is used to create a SubscriptionDataBuilder
+func NewSubscriptionData() SubscriptionDataBuilder {
+	return &subscriptionDataBuilder{}
+}
+
+// SubscriptionSingle is the response payload when fetching an individual subscription
+type SubscriptionSingle struct {
+	SubscriptionData SubscriptionData `json:"data"`
+	Links            Links            `json:"links"`
+
+	// Extra holds the members of the response that this library does not know about
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// SubscriptionList is the response payload when requesting a list of subscriptions
+type SubscriptionList struct {
+	SubscriptionData *[]SubscriptionData `json:"data,omitempty"`
+	Links            Links               `json:"links"`
+
+	// Extra holds the members of the response that this library does not know about
+	Extra map[string]json.RawMessage `json:"-"`
+}
@@ -0,0 +1,127 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const subscriptionsPath = "/v1/notification/subscriptions"
+
+// SubscriptionsClient is the client interface to access the notification subscriptions of the Form3 API
+//
+// The WithContext variants abort the request to the API once the context is done
+type SubscriptionsClient interface {
+	Create(request SubscriptionData) (SubscriptionSingle, error)
+	Fetch(id uuid.UUID) (SubscriptionSingle, error)
+	List(page *Page) (SubscriptionList, error)
+	Update(id uuid.UUID, request SubscriptionData) (SubscriptionSingle, error)
+	Delete(id uuid.UUID, version int) (bool, error)
+	CreateWithContext(ctx context.Context, request SubscriptionData) (SubscriptionSingle, error)
+	FetchWithContext(ctx context.Context, id uuid.UUID) (SubscriptionSingle, error)
+	ListWithContext(ctx context.Context, page *Page) (SubscriptionList, error)
+	UpdateWithContext(ctx context.Context, id uuid.UUID, request SubscriptionData) (SubscriptionSingle, error)
+	DeleteWithContext(ctx context.Context, id uuid.UUID, version int) (bool, error)
+}
+
+type subscriptionsClient struct {
+	resourceClient[SubscriptionData, SubscriptionSingle, SubscriptionList]
+}
+
+// SubscriptionsClientBuilder is used to create a SubscriptionsClient
+type SubscriptionsClientBuilder interface {
+	URL(string) SubscriptionsClientBuilder
+	HTTPClient(http.Client) SubscriptionsClientBuilder
+	StrictDecoding(bool) SubscriptionsClientBuilder
+	MaxBodySize(int64) SubscriptionsClientBuilder
+	Retry(RetryPolicy) SubscriptionsClientBuilder
+	Middleware(...Middleware) SubscriptionsClientBuilder
+	Build() SubscriptionsClient
+}
+
+type subscriptionsClientBuilder struct {
+	transportOptions[SubscriptionsClientBuilder]
+}
+
+func (sb *subscriptionsClientBuilder) Build() SubscriptionsClient {
+	subscriptions := newResource[SubscriptionSingle, SubscriptionList](sb.transport(), subscriptionsPath, "subscription", "subscriptions")
+	return &subscriptionsClient{
+		resourceClient: newResourceClient(subscriptions, subscriptionsType, func(data *SubscriptionData, partial bool) error {
+			return validateSubscription(data.Attributes, partial)
+		}),
+	}
+}
+
+// NewSubscriptionsClient is used to create a SubscriptionsClientBuilder
+func NewSubscriptionsClient() SubscriptionsClientBuilder {
+	sb := &subscriptionsClientBuilder{}
+	sb.self = sb
+	return sb
+}
+
+// List subscriptions
+func (c subscriptionsClient) List(page *Page) (SubscriptionList, error) {
+	return c.ListWithContext(context.Background(), page)
+}
+
+// ListWithContext lists subscriptions until the context is done
+func (c subscriptionsClient) ListWithContext(ctx context.Context, page *Page) (SubscriptionList, error) {
+	return c.resource.list(ctx, page, nil)
+}
+
+// validateSubscription validates the attributes of a subscription, those of an update being partial and
+// so allowed to leave out any of them
+func validateSubscription(subscription Subscription, partial bool) error {
+	transport := subscription.CallbackTransport
+	if (!partial || transport != "") && transport != CallbackTransportHTTP && transport != CallbackTransportQueue {
+		return &ValidationError{Field: "CallbackTransport", Message: fmt.Sprintf("Invalid CallbackTransport [%s]", transport)}
+	}
+
+	uri := subscription.CallbackURI
+	if !partial || uri != "" {
+		if !validCallbackURI(uri, transport) {
+			return &ValidationError{Field: "CallbackURI", Message: fmt.Sprintf("Invalid CallbackURI [%s]", uri)}
+		}
+	}
+
+	if !partial && subscription.RecordType == "" {
+		return &ValidationError{Field: "RecordType", Message: fmt.Sprintf("Invalid RecordType [%s]", subscription.RecordType)}
+	}
+	if !partial && subscription.EventType == "" {
+		return &ValidationError{Field: "EventType", Message: fmt.Sprintf("Invalid EventType [%s]", subscription.EventType)}
+	}
+
+	for _, data := range subscription.UserDefinedData {
+		if data.Key == "" {
+			return &ValidationError{Field: "UserDefinedData", Message: fmt.Sprintf("Invalid UserDefinedData %v", subscription.UserDefinedData)}
+		}
+	}
+
+	return nil
+}
+
+// validCallbackURI tells whether uri can be called back over transport: an absolute http or https URL
+// without credentials or fragment for http, any name without whitespace for queues
+//
+// The URI of an update that does not change the transport is checked as a URL when it looks like one
+func validCallbackURI(uri string, transport string) bool {
+	if uri == "" || strings.ContainsAny(uri, " \t\r\n") {
+		return false
+	}
+	if transport == CallbackTransportQueue {
+		return true
+	}
+	if transport == "" && !strings.HasPrefix(uri, "http://") && !strings.HasPrefix(uri, "https://") {
+		return true
+	}
+
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return false
+	}
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != "" && parsed.User == nil && parsed.Fragment == ""
+}
@@ -0,0 +1,109 @@
+package accounts
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// timestampLayouts are the formats the Form3 API has been seen to emit for timestamps, e.g. created_on
+//
+// Layouts without a timezone are interpreted as UTC
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+}
+
+func parseTimestamp(value string) (time.Time, error) {
+	for _, layout := range timestampLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("Invalid timestamp [%s]", value)
+}
+
+func parseOptionalTimestamp(value *string) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	parsed, err := parseTimestamp(*value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parsed, nil
+}
+
+// UnmarshalJSON decodes account data, parsing created_on and modified_on tolerantly
+// and keeping the fields this library does not know about in Extra
+func (ad *AccountData) UnmarshalJSON(data []byte) error {
+	type plain AccountData
+	return decodeJSON(data, (*plain)(ad))
+}
+
+// CreatedBetween returns the accounts on the page created at or after from and before to
+func (l List) CreatedBetween(from time.Time, to time.Time) []AccountData {
+	return l.between(from, to, func(ad AccountData) *time.Time { return ad.CreatedOn })
+}
+
+// ModifiedBetween returns the accounts on the page modified at or after from and before to
+func (l List) ModifiedBetween(from time.Time, to time.Time) []AccountData {
+	return l.between(from, to, func(ad AccountData) *time.Time { return ad.ModifiedOn })
+}
+
+// SortedByCreatedOn returns the accounts on the page ordered by creation time, oldest first
+//
+// Accounts without a creation time are placed last
+func (l List) SortedByCreatedOn() []AccountData {
+	return l.sorted(func(ad AccountData) *time.Time { return ad.CreatedOn })
+}
+
+// SortedByModifiedOn returns the accounts on the page ordered by modification time, oldest first
+//
+// Accounts without a modification time are placed last
+func (l List) SortedByModifiedOn() []AccountData {
+	return l.sorted(func(ad AccountData) *time.Time { return ad.ModifiedOn })
+}
+
+func (l List) between(from time.Time, to time.Time, timestamp func(AccountData) *time.Time) []AccountData {
+	var result []AccountData
+	if l.AccountData == nil {
+		return result
+	}
+
+	for _, ad := range *l.AccountData {
+		value := timestamp(ad)
+		if value != nil && !value.Before(from) && value.Before(to) {
+			result = append(result, ad)
+		}
+	}
+
+	return result
+}
+
+func (l List) sorted(timestamp func(AccountData) *time.Time) []AccountData {
+	var result []AccountData
+	if l.AccountData == nil {
+		return result
+	}
+
+	result = append(result, *l.AccountData...)
+	sort.SliceStable(result, func(i, j int) bool {
+		left, right := timestamp(result[i]), timestamp(result[j])
+		if left == nil {
+			return false
+		}
+		if right == nil {
+			return true
+		}
+		return left.Before(*right)
+	})
+
+	return result
+}
@@ -0,0 +1,142 @@
+package accounts
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const accountsWithTimestamps = `{
+	"data": [
+		{"id": "1", "organisation_id": "%[1]s", "type": "accounts", "created_on": "2020-03-01T10:00:00.123456Z", "modified_on": "2020-03-05T10:00:00Z", "attributes": {"country": "GB"}},
+		{"id": "2", "organisation_id": "%[1]s", "type": "accounts", "created_on": "2020-02-01T10:00:00.5", "modified_on": "2020-03-02T12:00:00+02:00", "attributes": {"country": "GB"}},
+		{"id": "3", "organisation_id": "%[1]s", "type": "accounts", "attributes": {"country": "GB"}}
+	],
+	"links": {"self": "/v1/organisation/accounts"}
+}`
+
+func TestListAccountsWithTimestamps(t *testing.T) {
+
+	Convey("Given the API returns accounts with timestamps in different formats", t, func() {
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, accountsWithTimestamps, OrganisationID)
+		}))
+		defer server.Close()
+
+		AccountsService := NewClient().HTTPClient(HTTPClient).URL(server.URL).Build()
+
+		Convey("When I list the accounts", func() {
+
+			resp, err := AccountsService.List(nil, nil)
+
+			Convey("Then the timestamps are parsed", func() {
+				So(err, ShouldBeNil)
+				accountDataArr := *resp.AccountData
+				So(*accountDataArr[0].CreatedOn, ShouldEqual, time.Date(2020, 3, 1, 10, 0, 0, 123456000, time.UTC))
+				So(*accountDataArr[1].CreatedOn, ShouldEqual, time.Date(2020, 2, 1, 10, 0, 0, 500000000, time.UTC))
+				So(accountDataArr[1].ModifiedOn.Equal(time.Date(2020, 3, 2, 10, 0, 0, 0, time.UTC)), ShouldBeTrue)
+				So(accountDataArr[2].CreatedOn, ShouldBeNil)
+			})
+
+			Convey("And the accounts can be sorted by creation time", func() {
+				sorted := resp.SortedByCreatedOn()
+				So(sorted[0].ID, ShouldEqual, "2")
+				So(sorted[1].ID, ShouldEqual, "1")
+				So(sorted[2].ID, ShouldEqual, "3")
+			})
+
+			Convey("And the accounts can be filtered by modification time", func() {
+				modified := resp.ModifiedBetween(time.Date(2020, 3, 3, 0, 0, 0, 0, time.UTC), time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC))
+				So(len(modified), ShouldEqual, 1)
+				So(modified[0].ID, ShouldEqual, "1")
+			})
+
+		})
+
+	})
+
+}
+
+func TestFetchAccountWithInvalidTimestamp(t *testing.T) {
+
+	Convey("Given the API returns an account with an unparseable timestamp", t, func() {
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"data": {"id": "1", "type": "accounts", "created_on": "yesterday", "attributes": {"country": "GB"}}, "links": {"self": ""}}`)
+		}))
+		defer server.Close()
+
+		AccountsService := NewClient().HTTPClient(HTTPClient).URL(server.URL).Build()
+
+		Convey("When I fetch the account", func() {
+
+			_, err := AccountsService.Fetch(uuid.New())
+
+			Convey("Then an appropriate error is propagated to the caller", func() {
+				So(err.Error(), ShouldEqual, "An error has occured while decoding response")
+			})
+
+		})
+
+	})
+
+}
+
+func TestFetchResourcesWithZonelessTimestamps(t *testing.T) {
+
+	Convey("Given the API returns resources whose timestamps have no timezone", t, func() {
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, "/v1/audit") {
+				fmt.Fprint(w, `{"data": [{"id": "1", "type": "audit_entries", "attributes": {"action_time": "2021-03-01T10:00:00.5", "record_type": "accounts", "record_id": "1"}}], "links": {"self": ""}}`)
+				return
+			}
+			fmt.Fprint(w, `{"data": {"id": "1", "type": "resources", "created_on": "2021-03-01T10:00:00.5", "modified_on": "2021-03-02 10:00:00", "attributes": {}}, "links": {"self": ""}}`)
+		}))
+		defer server.Close()
+
+		created := time.Date(2021, 3, 1, 10, 0, 0, 500000000, time.UTC)
+		modified := time.Date(2021, 3, 2, 10, 0, 0, 0, time.UTC)
+
+		Convey("When I fetch an organisation, a subscription, a bank ID, a BIC and an account routing", func() {
+			organisation, organisationErr := NewOrganisationsClient().HTTPClient(HTTPClient).URL(server.URL).Build().Fetch(uuid.New())
+			subscription, subscriptionErr := NewSubscriptionsClient().HTTPClient(HTTPClient).URL(server.URL).Build().Fetch(uuid.New())
+			bankID, bankIDErr := NewBankIDsClient().HTTPClient(HTTPClient).URL(server.URL).Build().Fetch(uuid.New())
+			bic, bicErr := NewBICsClient().HTTPClient(HTTPClient).URL(server.URL).Build().Fetch(uuid.New())
+			routing, routingErr := NewAccountRoutingsClient().HTTPClient(HTTPClient).URL(server.URL).Build().Fetch(uuid.New())
+
+			Convey("Then their timestamps are parsed as UTC", func() {
+				So(organisationErr, ShouldBeNil)
+				So(*organisation.OrganisationData.CreatedOn, ShouldEqual, created)
+				So(*organisation.OrganisationData.ModifiedOn, ShouldEqual, modified)
+				So(subscriptionErr, ShouldBeNil)
+				So(*subscription.SubscriptionData.CreatedOn, ShouldEqual, created)
+				So(bankIDErr, ShouldBeNil)
+				So(*bankID.BankIDData.CreatedOn, ShouldEqual, created)
+				So(bicErr, ShouldBeNil)
+				So(*bic.BICData.CreatedOn, ShouldEqual, created)
+				So(routingErr, ShouldBeNil)
+				So(*routing.AccountRoutingData.ModifiedOn, ShouldEqual, modified)
+			})
+		})
+
+		Convey("When I list the audit entries of an account", func() {
+			entries, err := NewAuditsClient().HTTPClient(HTTPClient).URL(server.URL).Build().List("accounts", "1", nil)
+
+			Convey("Then their action times are parsed as UTC", func() {
+				So(err, ShouldBeNil)
+				So(*(*entries.AuditEntryData)[0].Attributes.ActionTime, ShouldEqual, created)
+			})
+		})
+
+	})
+
+}
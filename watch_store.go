@@ -0,0 +1,153 @@
+package accounts
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// WatchState is what a Watcher remembers of an account to tell whether it was modified
+type WatchState struct {
+	Version    *int       `json:"version,omitempty"`
+	ModifiedOn *time.Time `json:"modified_on,omitempty"`
+}
+
+func stateOf(ad AccountData) WatchState {
+	return WatchState{Version: ad.Version, ModifiedOn: ad.ModifiedOn}
+}
+
+// changed tells whether the account moved from ws to current, going by its version, then by its
+// modification time when both are known
+func (ws WatchState) changed(current WatchState) bool {
+	if (ws.Version == nil) != (current.Version == nil) || (ws.Version != nil && *ws.Version != *current.Version) {
+		return true
+	}
+	if ws.ModifiedOn != nil && current.ModifiedOn != nil && !ws.ModifiedOn.Equal(*current.ModifiedOn) {
+		return true
+	}
+	return false
+}
+
+// WatchStore keeps the state of the last poll of the watches, keyed by filter
+//
+// Implementations must be safe for concurrent use
+type WatchStore interface {
+	// Load returns the state saved for key, and whether there was one
+	Load(key string) (map[string]WatchState, bool, error)
+	// Save replaces the state saved for key
+	Save(key string, state map[string]WatchState) error
+}
+
+type memoryWatchStore struct {
+	mutex  sync.Mutex
+	states map[string]map[string]WatchState
+}
+
+// NewMemoryWatchStore returns a WatchStore keeping the states in memory, for as long as the process runs
+func NewMemoryWatchStore() WatchStore {
+	return &memoryWatchStore{states: make(map[string]map[string]WatchState)}
+}
+
+func (ms *memoryWatchStore) Load(key string) (map[string]WatchState, bool, error) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	state, ok := ms.states[key]
+	return state, ok, nil
+}
+
+func (ms *memoryWatchStore) Save(key string, state map[string]WatchState) error {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	ms.states[key] = state
+	return nil
+}
+
+type fileWatchStore struct {
+	mutex sync.Mutex
+	path  string
+}
+
+// NewFileWatchStore returns a WatchStore keeping the states in a JSON file, so that watches resume after a restart
+//
+// The file is replaced atomically on every save
+func NewFileWatchStore(path string) WatchStore {
+	return &fileWatchStore{path: path}
+}
+
+func (fs *fileWatchStore) read() (map[string]map[string]WatchState, error) {
+	states := make(map[string]map[string]WatchState)
+
+	content, err := os.ReadFile(fs.path)
+	if os.IsNotExist(err) {
+		return states, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("An error has occured while reading watch state [%s]: %w", fs.path, err)
+	}
+	if err := json.Unmarshal(content, &states); err != nil {
+		return nil, fmt.Errorf("An error has occured while decoding watch state [%s]: %w", fs.path, err)
+	}
+
+	return states, nil
+}
+
+func (fs *fileWatchStore) Load(key string) (map[string]WatchState, bool, error) {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	states, err := fs.read()
+	if err != nil {
+		return nil, false, err
+	}
+	state, ok := states[key]
+	return state, ok, nil
+}
+
+func (fs *fileWatchStore) Save(key string, state map[string]WatchState) error {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	states, err := fs.read()
+	if err != nil {
+		return err
+	}
+	states[key] = state
+
+	content, err := json.Marshal(states)
+	if err != nil {
+		return fmt.Errorf("An error has occured while encoding watch state: %w", err)
+	}
+	temp, err := os.CreateTemp(filepath.Dir(fs.path), filepath.Base(fs.path)+".*")
+	if err != nil {
+		return fmt.Errorf("An error has occured while writing watch state [%s]: %w", fs.path, err)
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(content); err != nil {
+		temp.Close()
+		return fmt.Errorf("An error has occured while writing watch state [%s]: %w", fs.path, err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("An error has occured while writing watch state [%s]: %w", fs.path, err)
+	}
+	if err := os.Rename(temp.Name(), fs.path); err != nil {
+		return fmt.Errorf("An error has occured while writing watch state [%s]: %w", fs.path, err)
+	}
+
+	return nil
+}
+
+func sortedKeys(state map[string]WatchState) []string {
+	keys := make([]string, 0, len(state))
+	for key := range state {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,213 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// WatchEventType tells what happened to an account between two polls of a Watcher
+type WatchEventType string
+
+// the types of events sent by a Watcher
+const (
+	Added    WatchEventType = "Added"
+	Modified WatchEventType = "Modified"
+	Deleted  WatchEventType = "Deleted"
+)
+
+// WatchEvent is a change to an account seen by a Watcher, or the error that made a poll fail
+//
+// The account of a Deleted event only holds the ID, version and modification time last seen
+type WatchEvent struct {
+	Type    WatchEventType
+	Account AccountData
+	Err     error
+}
+
+// Watcher detects the changes to accounts by polling the API
+type Watcher interface {
+	// Watch lists the accounts matching filter every interval and sends the changes since the previous
+	// poll on the returned channel, which is closed once the context is done
+	//
+	// The state of the previous poll is loaded from the store of the watcher, so that a watch resumed
+	// after a restart only sends what changed meanwhile; the first watch of a filter sees every account
+	// as Added. A poll that fails is sent as an event holding the error and tried again at the next interval;
+	// an interval that is not positive is sent as a validation error, the channel being closed right after
+	Watch(ctx context.Context, filter *Filter, interval time.Duration) <-chan WatchEvent
+}
+
+type watcher struct {
+	client   ContextClient
+	pageSize int
+	store    WatchStore
+}
+
+// WatcherBuilder is used to create a Watcher
+type WatcherBuilder interface {
+	Client(ContextClient) WatcherBuilder
+	PageSize(int) WatcherBuilder
+	Store(WatchStore) WatcherBuilder
+	Build() Watcher
+}
+
+type watcherBuilder struct {
+	client   ContextClient
+	pageSize int
+	store    WatchStore
+}
+
+func (wb *watcherBuilder) Client(value ContextClient) WatcherBuilder {
+	wb.client = value
+	return wb
+}
+
+// PageSize is the number of accounts requested per page, 100 when not set
+func (wb *watcherBuilder) PageSize(value int) WatcherBuilder {
+	wb.pageSize = value
+	return wb
+}
+
+// Store keeps the state of the last poll of every filter watched, in memory when not set
+func (wb *watcherBuilder) Store(value WatchStore) WatcherBuilder {
+	wb.store = value
+	return wb
+}
+
+func (wb *watcherBuilder) Build() Watcher {
+	w := &watcher{
+		client:   wb.client,
+		pageSize: wb.pageSize,
+		store:    wb.store,
+	}
+	if w.pageSize < 1 {
+		w.pageSize = 100
+	}
+	if w.store == nil {
+		w.store = NewMemoryWatchStore()
+	}
+
+	return w
+}
+
+// NewWatcher is used to create a WatcherBuilder
+func NewWatcher() WatcherBuilder {
+	return &watcherBuilder{}
+}
+
+// watchKey identifies the state of the watches of filter in a store
+func watchKey(filter *Filter) string {
+	if filter == nil || filter.OrganisationID == nil {
+		return "*"
+	}
+	return "organisation_id=" + *filter.OrganisationID
+}
+
+func (w watcher) Watch(ctx context.Context, filter *Filter, interval time.Duration) <-chan WatchEvent {
+	events := make(chan WatchEvent)
+	go w.watch(ctx, filter, interval, events)
+	return events
+}
+
+func (w watcher) watch(ctx context.Context, filter *Filter, interval time.Duration, events chan<- WatchEvent) {
+	defer close(events)
+
+	send := func(event WatchEvent) bool {
+		select {
+		case events <- event:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	if interval <= 0 {
+		send(WatchEvent{Err: &ValidationError{Field: "Interval", Message: fmt.Sprintf("Invalid Interval [%s]", interval)}})
+		return
+	}
+
+	key := watchKey(filter)
+	state, _, err := w.store.Load(key)
+	if err != nil && !send(WatchEvent{Err: err}) {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		current, err := w.poll(ctx, filter)
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			if !send(WatchEvent{Err: err}) {
+				return
+			}
+		default:
+			changes := diffStates(state, current)
+			for _, event := range changes {
+				if !send(event) {
+					return
+				}
+			}
+			state = snapshot(current)
+			if len(changes) > 0 {
+				if err := w.store.Save(key, state); err != nil && !send(WatchEvent{Err: err}) {
+					return
+				}
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// poll lists every account matching filter, in the order of the API
+func (w watcher) poll(ctx context.Context, filter *Filter) ([]AccountData, error) {
+	var current []AccountData
+	_, err := eachPageWithContext(ctx, w.client, filter, w.pageSize, func(data []AccountData) error {
+		current = append(current, data...)
+		return nil
+	})
+
+	return current, err
+}
+
+// diffStates returns the events turning state into current: accounts Added and Modified in the order of
+// current, then Deleted ones
+func diffStates(state map[string]WatchState, current []AccountData) []WatchEvent {
+	var events []WatchEvent
+	seen := make(map[string]bool, len(current))
+	for _, ad := range current {
+		seen[ad.ID] = true
+		previous, ok := state[ad.ID]
+		switch {
+		case !ok:
+			events = append(events, WatchEvent{Type: Added, Account: ad})
+		case previous.changed(stateOf(ad)):
+			events = append(events, WatchEvent{Type: Modified, Account: ad})
+		}
+	}
+
+	for _, id := range sortedKeys(state) {
+		if !seen[id] {
+			previous := state[id]
+			events = append(events, WatchEvent{Type: Deleted, Account: AccountData{ID: id, Version: previous.Version, ModifiedOn: previous.ModifiedOn}})
+		}
+	}
+
+	return events
+}
+
+func snapshot(current []AccountData) map[string]WatchState {
+	state := make(map[string]WatchState, len(current))
+	for _, ad := range current {
+		state[ad.ID] = stateOf(ad)
+	}
+	return state
+}
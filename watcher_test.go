@@ -0,0 +1,252 @@
+package accounts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// watchServer lists the accounts it holds by pages, linking to the next page until the last one
+type watchServer struct {
+	mutex    sync.Mutex
+	accounts []AccountData
+	failing  bool
+}
+
+func (ws *watchServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	if ws.failing {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	number, _ := strconv.Atoi(r.URL.Query().Get("page[number]"))
+	size, _ := strconv.Atoi(r.URL.Query().Get("page[size]"))
+	data := []AccountData{}
+	var links Links
+	if start := number * size; start < len(ws.accounts) {
+		end := start + size
+		if end > len(ws.accounts) {
+			end = len(ws.accounts)
+		}
+		data = append(data, ws.accounts[start:end]...)
+		if end < len(ws.accounts) {
+			next := "next"
+			links.Next = &next
+		}
+	}
+	json.NewEncoder(w).Encode(List{AccountData: &data, Links: links})
+}
+
+func (ws *watchServer) add() AccountData {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	ad := NewAccountData().ID(uuid.New().String()).OrganisationID(OrganisationID).Type(Type).Version(0).Attributes(NewAccount().Country("GB").Build()).Build()
+	ws.accounts = append(ws.accounts, ad)
+	return ad
+}
+
+func (ws *watchServer) modify(i int) {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	version := *ws.accounts[i].Version + 1
+	ws.accounts[i].Version = &version
+}
+
+func (ws *watchServer) remove(i int) {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	ws.accounts = append(ws.accounts[:i], ws.accounts[i+1:]...)
+}
+
+func (ws *watchServer) fail(failing bool) {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	ws.failing = failing
+}
+
+// nextEvents receives n events, failing the test when they do not come in time
+func nextEvents(events <-chan WatchEvent, n int) []WatchEvent {
+	var received []WatchEvent
+	for len(received) < n {
+		select {
+		case event := <-events:
+			received = append(received, event)
+		case <-time.After(2 * time.Second):
+			So(len(received), ShouldEqual, n)
+			return received
+		}
+	}
+	return received
+}
+
+func TestWatchAccounts(t *testing.T) {
+
+	Convey("Given an API holding three accounts", t, func() {
+		server := &watchServer{}
+		api := httptest.NewServer(server)
+		defer api.Close()
+
+		first, second, third := server.add(), server.add(), server.add()
+		client := NewClient().HTTPClient(HTTPClient).URL(api.URL).Build()
+		store := NewMemoryWatchStore()
+		watcher := NewWatcher().Client(client).PageSize(2).Store(store).Build()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		Convey("When I start watching them", func() {
+			events := watcher.Watch(ctx, nil, 20*time.Millisecond)
+			added := nextEvents(events, 3)
+
+			Convey("Then every account is Added, across pages", func() {
+				So(added[0].Type, ShouldEqual, Added)
+				So(added[0].Account.ID, ShouldEqual, first.ID)
+				So(added[2].Account.ID, ShouldEqual, third.ID)
+			})
+
+			Convey("And the changes made meanwhile are sent at the next polls", func() {
+				server.modify(1)
+				changed := nextEvents(events, 1)
+				So(changed[0].Type, ShouldEqual, Modified)
+				So(changed[0].Account.ID, ShouldEqual, second.ID)
+				So(*changed[0].Account.Version, ShouldEqual, 1)
+
+				server.remove(0)
+				fourth := server.add()
+				changed = nextEvents(events, 2)
+				So(changed[0].Type, ShouldEqual, Added)
+				So(changed[0].Account.ID, ShouldEqual, fourth.ID)
+				So(changed[1].Type, ShouldEqual, Deleted)
+				So(changed[1].Account.ID, ShouldEqual, first.ID)
+			})
+
+			Convey("And a poll that fails is sent as an error without losing the state", func() {
+				server.fail(true)
+				failed := nextEvents(events, 1)
+				So(failed[0].Err, ShouldNotBeNil)
+
+				server.fail(false)
+				server.modify(2)
+				for {
+					event := nextEvents(events, 1)[0]
+					if event.Err == nil {
+						So(event.Type, ShouldEqual, Modified)
+						So(event.Account.ID, ShouldEqual, third.ID)
+						break
+					}
+				}
+			})
+
+			Convey("And the channel is closed once the context is done", func() {
+				cancel()
+				for range events {
+				}
+			})
+		})
+
+		Convey("When a watch is resumed from a saved state", func() {
+			watchOnce := func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				events := watcher.Watch(ctx, nil, time.Hour)
+				nextEvents(events, 3)
+				cancel()
+				for range events {
+				}
+			}
+			watchOnce()
+
+			server.modify(0)
+			events := watcher.Watch(ctx, nil, time.Hour)
+			resumed := nextEvents(events, 1)
+
+			Convey("Then only what changed since is sent", func() {
+				So(resumed[0].Type, ShouldEqual, Modified)
+				So(resumed[0].Account.ID, ShouldEqual, first.ID)
+			})
+		})
+
+		Convey("When I watch them with an interval that is not positive", func() {
+			events := watcher.Watch(ctx, nil, 0)
+			failed := nextEvents(events, 1)
+
+			Convey("Then a validation error is sent and the channel is closed", func() {
+				var validationErr *ValidationError
+				So(errors.As(failed[0].Err, &validationErr), ShouldBeTrue)
+				So(validationErr.Field, ShouldEqual, "Interval")
+				_, open := <-events
+				So(open, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestWatchStateChanges(t *testing.T) {
+
+	Convey("Given the state of an account", t, func() {
+		version := 1
+		modifiedOn := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+		state := WatchState{Version: &version, ModifiedOn: &modifiedOn}
+
+		Convey("Then a new version or modification time is a change", func() {
+			newVersion := 2
+			later := modifiedOn.Add(time.Second)
+			So(state.changed(WatchState{Version: &version, ModifiedOn: &modifiedOn}), ShouldBeFalse)
+			So(state.changed(WatchState{Version: &newVersion, ModifiedOn: &modifiedOn}), ShouldBeTrue)
+			So(state.changed(WatchState{Version: &version, ModifiedOn: &later}), ShouldBeTrue)
+			So(state.changed(WatchState{Version: &version}), ShouldBeFalse)
+		})
+	})
+}
+
+func TestFileWatchStore(t *testing.T) {
+
+	Convey("Given a file watch store", t, func() {
+		path := filepath.Join(os.TempDir(), uuid.New().String()+".json")
+		defer os.Remove(path)
+		store := NewFileWatchStore(path)
+
+		Convey("When nothing was saved", func() {
+			_, ok, err := store.Load("*")
+
+			Convey("Then there is no state", func() {
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("When states are saved for two filters", func() {
+			version := 3
+			So(store.Save("*", map[string]WatchState{"a": {Version: &version}}), ShouldBeNil)
+			So(store.Save("organisation_id=o", map[string]WatchState{"b": {}}), ShouldBeNil)
+
+			Convey("Then another store on the same file loads them", func() {
+				state, ok, err := NewFileWatchStore(path).Load("*")
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+				So(*state["a"].Version, ShouldEqual, 3)
+
+				state, _, _ = NewFileWatchStore(path).Load("organisation_id=o")
+				So(len(state), ShouldEqual, 1)
+			})
+		})
+	})
+}
@@ -0,0 +1,103 @@
+package webhook
+
+import (
+	"container/list"
+	"sync"
+)
+
+// State is what a Deduplicator knows of a notification
+type State int
+
+const (
+	// Unseen notifications are neither being handled nor handled
+	Unseen State = iota
+	// InFlight notifications are being handled, their outcome not known yet
+	InFlight
+	// Handled notifications were handled successfully
+	Handled
+)
+
+// Deduplicator remembers the notifications being handled or handled, so that redeliveries are skipped
+//
+// Implementations must be safe for concurrent use; a shared one, e.g. backed by a database, lets several
+// instances of a service skip each other's notifications
+type Deduplicator interface {
+	// Add remembers a notification as in flight when it is Unseen, returning what was known of it before
+	Add(id string) State
+	// Done remembers a notification in flight as Handled
+	Done(id string)
+	// Remove forgets a notification whose handling failed, so that its redelivery is handled
+	Remove(id string)
+}
+
+type memoryEntry struct {
+	id      string
+	handled bool
+}
+
+type memoryDeduplicator struct {
+	mutex    sync.Mutex
+	capacity int
+	seen     map[string]*list.Element
+	order    *list.List
+}
+
+// NewMemoryDeduplicator returns an in-memory Deduplicator forgetting the oldest notification beyond capacity
+func NewMemoryDeduplicator(capacity int) Deduplicator {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	return &memoryDeduplicator{
+		capacity: capacity,
+		seen:     make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (md *memoryDeduplicator) Add(id string) State {
+	md.mutex.Lock()
+	defer md.mutex.Unlock()
+
+	if element, ok := md.seen[id]; ok {
+		if element.Value.(*memoryEntry).handled {
+			return Handled
+		}
+		return InFlight
+	}
+
+	md.push(&memoryEntry{id: id})
+
+	return Unseen
+}
+
+func (md *memoryDeduplicator) Done(id string) {
+	md.mutex.Lock()
+	defer md.mutex.Unlock()
+
+	if element, ok := md.seen[id]; ok {
+		element.Value.(*memoryEntry).handled = true
+		return
+	}
+
+	// forgotten while in flight, beyond capacity
+	md.push(&memoryEntry{id: id, handled: true})
+}
+
+func (md *memoryDeduplicator) Remove(id string) {
+	md.mutex.Lock()
+	defer md.mutex.Unlock()
+
+	if element, ok := md.seen[id]; ok {
+		md.order.Remove(element)
+		delete(md.seen, id)
+	}
+}
+
+func (md *memoryDeduplicator) push(entry *memoryEntry) {
+	if md.order.Len() == md.capacity {
+		oldest := md.order.Front()
+		delete(md.seen, md.order.Remove(oldest).(*memoryEntry).id)
+	}
+	md.seen[entry.id] = md.order.PushBack(entry)
+}
@@ -0,0 +1,99 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	accounts "github.com/razvanmuscalu/form3-accounts-client"
+)
+
+// NewAccountNotification returns a sample notification of an event on an account, with a random ID
+func NewAccountNotification(eventType string, account accounts.AccountData) (Notification, error) {
+	data, err := json.Marshal(account)
+	if err != nil {
+		return Notification{}, err
+	}
+
+	version := 0
+	if account.Version != nil {
+		version = *account.Version
+	}
+
+	return Notification{
+		ID:             uuid.New().String(),
+		OrganisationID: account.OrganisationID,
+		EventType:      eventType,
+		RecordType:     "accounts",
+		ResourceType:   "accounts",
+		Version:        version,
+		Data:           data,
+	}, nil
+}
+
+// DeliveryOption changes how Deliver posts a notification
+type DeliveryOption func(d *delivery)
+
+type delivery struct {
+	signatureHeader string
+	timestampHeader string
+	timestamp       time.Time
+}
+
+// WithSignatureHeader signs the delivery in the given header instead of DefaultSignatureHeader, for handlers
+// built with another SignatureHeader
+func WithSignatureHeader(name string) DeliveryOption {
+	return func(d *delivery) {
+		d.signatureHeader = name
+	}
+}
+
+// WithTimestampHeader gives the time the delivery is signed at in the given header instead of
+// DefaultTimestampHeader, for handlers built with another TimestampHeader
+func WithTimestampHeader(name string) DeliveryOption {
+	return func(d *delivery) {
+		d.timestampHeader = name
+	}
+}
+
+// WithTimestamp signs the delivery at the given time instead of now, e.g. to check stale deliveries are rejected
+func WithTimestamp(timestamp time.Time) DeliveryOption {
+	return func(d *delivery) {
+		d.timestamp = timestamp
+	}
+}
+
+// Deliver posts a notification to handler signed with secret now, in DefaultSignatureHeader and
+// DefaultTimestampHeader as Form3 does, and returns the response of the handler
+//
+// It is meant for the tests of services receiving notifications
+func Deliver(handler http.Handler, secret []byte, notification Notification, options ...DeliveryOption) (*http.Response, error) {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return nil, err
+	}
+
+	d := delivery{
+		signatureHeader: DefaultSignatureHeader,
+		timestampHeader: DefaultTimestampHeader,
+		timestamp:       time.Now(),
+	}
+	for _, option := range options {
+		option(&d)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(d.timestampHeader, strconv.FormatInt(d.timestamp.Unix(), 10))
+	req.Header.Set(d.signatureHeader, Sign(secret, d.timestamp, body))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	return recorder.Result(), nil
+}
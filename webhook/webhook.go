@@ -0,0 +1,256 @@
+// Package webhook receives the notifications Form3 delivers for the subscriptions of a service, verifying
+// their signature and dispatching the accounts they carry to typed callbacks
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	accounts "github.com/razvanmuscalu/form3-accounts-client"
+)
+
+// DefaultSignatureHeader is the header holding the signature of a delivery when the handler is not told otherwise
+const DefaultSignatureHeader = "X-Form3-Signature"
+
+// DefaultTimestampHeader is the header holding the time a delivery was signed at, in Unix seconds, when the
+// handler is not told otherwise
+const DefaultTimestampHeader = "X-Form3-Timestamp"
+
+// DefaultTolerance is how far from now the timestamp of a delivery may be when the handler is not told otherwise
+const DefaultTolerance = 5 * time.Minute
+
+// maxBodySize is the largest delivery the handler reads, in bytes
+const maxBodySize = 1 << 20
+
+// Notification is a delivery of Form3 for an event on a record, its data being the record as it is after the event
+type Notification struct {
+	ID             string          `json:"id"`
+	OrganisationID string          `json:"organisation_id"`
+	EventType      string          `json:"event_type"`
+	RecordType     string          `json:"record_type"`
+	ResourceType   string          `json:"resource_type"`
+	Version        int             `json:"version"`
+	Data           json.RawMessage `json:"data"`
+}
+
+// AccountHandler handles the account of a notification; an error makes the delivery fail so that it is redelivered
+type AccountHandler func(ctx context.Context, notification Notification, account accounts.AccountData) error
+
+// ErrNoSecret is returned when building a handler without the secret deliveries are signed with
+var ErrNoSecret = errors.New("A secret is required to verify signatures")
+
+type handler struct {
+	secret          []byte
+	signatureHeader string
+	timestampHeader string
+	tolerance       time.Duration
+	deduplicator    Deduplicator
+	callbacks       map[string]AccountHandler
+}
+
+// HandlerBuilder is used to create the http.Handler receiving notifications
+type HandlerBuilder interface {
+	Secret([]byte) HandlerBuilder
+	SignatureHeader(string) HandlerBuilder
+	TimestampHeader(string) HandlerBuilder
+	Tolerance(time.Duration) HandlerBuilder
+	Deduplicator(Deduplicator) HandlerBuilder
+	OnAccountCreated(AccountHandler) HandlerBuilder
+	OnAccountUpdated(AccountHandler) HandlerBuilder
+	OnAccountDeleted(AccountHandler) HandlerBuilder
+	Build() (http.Handler, error)
+}
+
+type handlerBuilder struct {
+	secret          []byte
+	signatureHeader string
+	timestampHeader string
+	tolerance       time.Duration
+	deduplicator    Deduplicator
+	callbacks       map[string]AccountHandler
+}
+
+// Secret is the key deliveries are signed with, as an HMAC-SHA256 of their timestamp and body
+func (hb *handlerBuilder) Secret(value []byte) HandlerBuilder {
+	hb.secret = value
+	return hb
+}
+
+// SignatureHeader is the header holding the signature, DefaultSignatureHeader when not set
+func (hb *handlerBuilder) SignatureHeader(value string) HandlerBuilder {
+	hb.signatureHeader = value
+	return hb
+}
+
+// TimestampHeader is the header holding the time the delivery was signed at, DefaultTimestampHeader when not set
+func (hb *handlerBuilder) TimestampHeader(value string) HandlerBuilder {
+	hb.timestampHeader = value
+	return hb
+}
+
+// Tolerance is how far from now the timestamp of a delivery may be, so that a captured delivery cannot be
+// replayed later, DefaultTolerance when not set
+func (hb *handlerBuilder) Tolerance(value time.Duration) HandlerBuilder {
+	hb.tolerance = value
+	return hb
+}
+
+// Deduplicator remembers the notifications handled, an in-memory one of 10000 notifications when not set
+func (hb *handlerBuilder) Deduplicator(value Deduplicator) HandlerBuilder {
+	hb.deduplicator = value
+	return hb
+}
+
+func (hb *handlerBuilder) OnAccountCreated(value AccountHandler) HandlerBuilder {
+	hb.callbacks[accounts.EventCreated] = value
+	return hb
+}
+
+func (hb *handlerBuilder) OnAccountUpdated(value AccountHandler) HandlerBuilder {
+	hb.callbacks[accounts.EventUpdated] = value
+	return hb
+}
+
+func (hb *handlerBuilder) OnAccountDeleted(value AccountHandler) HandlerBuilder {
+	hb.callbacks[accounts.EventDeleted] = value
+	return hb
+}
+
+func (hb *handlerBuilder) Build() (http.Handler, error) {
+	if len(hb.secret) == 0 {
+		return nil, ErrNoSecret
+	}
+
+	h := &handler{
+		secret:          hb.secret,
+		signatureHeader: hb.signatureHeader,
+		timestampHeader: hb.timestampHeader,
+		tolerance:       hb.tolerance,
+		deduplicator:    hb.deduplicator,
+		callbacks:       hb.callbacks,
+	}
+	if h.signatureHeader == "" {
+		h.signatureHeader = DefaultSignatureHeader
+	}
+	if h.timestampHeader == "" {
+		h.timestampHeader = DefaultTimestampHeader
+	}
+	if h.tolerance <= 0 {
+		h.tolerance = DefaultTolerance
+	}
+	if h.deduplicator == nil {
+		h.deduplicator = NewMemoryDeduplicator(10000)
+	}
+
+	return h, nil
+}
+
+// NewHandler is used to create a HandlerBuilder
+func NewHandler() HandlerBuilder {
+	return &handlerBuilder{callbacks: make(map[string]AccountHandler)}
+}
+
+// ServeHTTP handles a delivery, answering 204 once it is handled, or was already, so that it is not redelivered
+//
+// Deliveries for other records or events than those with a callback are acknowledged without being handled;
+// those whose callback fails are answered 500 and handled again when redelivered. A redelivery arriving while
+// the notification is still being handled is answered 409, with a Retry-After, as its outcome is not known yet
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+	if err != nil {
+		http.Error(w, "An error has occured while reading notification", http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxBodySize {
+		http.Error(w, "Notification too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if !Verify(h.secret, r.Header.Get(h.timestampHeader), body, r.Header.Get(h.signatureHeader), h.tolerance) {
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var notification Notification
+	if err := json.Unmarshal(body, &notification); err != nil || notification.ID == "" {
+		http.Error(w, "Invalid notification", http.StatusBadRequest)
+		return
+	}
+
+	callback, ok := h.callbacks[notification.EventType]
+	if !ok || notification.RecordType != "accounts" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	var account accounts.AccountData
+	if err := json.Unmarshal(notification.Data, &account); err != nil {
+		http.Error(w, "Invalid account in notification", http.StatusBadRequest)
+		return
+	}
+
+	switch h.deduplicator.Add(notification.ID) {
+	case Handled:
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case InFlight:
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, "Notification is being handled", http.StatusConflict)
+		return
+	}
+	if err := callback(r.Context(), notification, account); err != nil {
+		h.deduplicator.Remove(notification.ID)
+		http.Error(w, "An error has occured while handling notification", http.StatusInternalServerError)
+		return
+	}
+	h.deduplicator.Done(notification.ID)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Sign returns the signature of a delivery body signed at timestamp, the hex encoded HMAC-SHA256 of the
+// timestamp in Unix seconds, a dot and the body
+func Sign(secret []byte, timestamp time.Time, body []byte) string {
+	return hex.EncodeToString(signatureOf(secret, strconv.FormatInt(timestamp.Unix(), 10), body))
+}
+
+// Verify tells whether signature, with or without a sha256= prefix, is the signature of body signed at
+// timestamp, given in Unix seconds, and whether timestamp is within tolerance of now
+func Verify(secret []byte, timestamp string, body []byte, signature string, tolerance time.Duration) bool {
+	seconds, err := strconv.ParseInt(strings.TrimSpace(timestamp), 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := time.Since(time.Unix(seconds, 0)); age > tolerance || age < -tolerance {
+		return false
+	}
+
+	decoded, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(signature), "sha256="))
+	if err != nil || len(decoded) == 0 {
+		return false
+	}
+
+	return hmac.Equal(decoded, signatureOf(secret, strings.TrimSpace(timestamp), body))
+}
+
+func signatureOf(secret []byte, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
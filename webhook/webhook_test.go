@@ -0,0 +1,321 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	accounts "github.com/razvanmuscalu/form3-accounts-client"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var secret = []byte("webhook secret")
+
+func newAccount() accounts.AccountData {
+	return accounts.NewAccountData().
+		ID(uuid.New().String()).
+		OrganisationID(uuid.New().String()).
+		Type("accounts").
+		Version(1).
+		Attributes(accounts.NewAccount().Country("GB").BankAccountName("Sam Holder").Build()).
+		Build()
+}
+
+func TestHandler(t *testing.T) {
+
+	Convey("Given a handler with callbacks for created and deleted accounts", t, func() {
+		var created, deleted []accounts.AccountData
+		var failure error
+		handler, err := NewHandler().
+			Secret(secret).
+			OnAccountCreated(func(ctx context.Context, n Notification, account accounts.AccountData) error {
+				created = append(created, account)
+				return failure
+			}).
+			OnAccountDeleted(func(ctx context.Context, n Notification, account accounts.AccountData) error {
+				deleted = append(deleted, account)
+				return nil
+			}).
+			Build()
+		So(err, ShouldBeNil)
+
+		account := newAccount()
+
+		Convey("When an account creation is delivered", func() {
+			notification, _ := NewAccountNotification(accounts.EventCreated, account)
+			resp, _ := Deliver(handler, secret, notification)
+
+			Convey("Then the account is decoded and dispatched", func() {
+				So(resp.StatusCode, ShouldEqual, http.StatusNoContent)
+				So(len(created), ShouldEqual, 1)
+				So(created[0].ID, ShouldEqual, account.ID)
+				So(*created[0].Attributes.BankAccountName, ShouldEqual, "Sam Holder")
+				So(len(deleted), ShouldEqual, 0)
+			})
+
+			Convey("And its redelivery is acknowledged without being dispatched again", func() {
+				resp, _ := Deliver(handler, secret, notification)
+				So(resp.StatusCode, ShouldEqual, http.StatusNoContent)
+				So(len(created), ShouldEqual, 1)
+			})
+		})
+
+		Convey("When the callback fails", func() {
+			failure = errors.New("database unavailable")
+			notification, _ := NewAccountNotification(accounts.EventCreated, account)
+			resp, _ := Deliver(handler, secret, notification)
+
+			Convey("Then the delivery fails and its redelivery is dispatched again", func() {
+				So(resp.StatusCode, ShouldEqual, http.StatusInternalServerError)
+
+				failure = nil
+				resp, _ := Deliver(handler, secret, notification)
+				So(resp.StatusCode, ShouldEqual, http.StatusNoContent)
+				So(len(created), ShouldEqual, 2)
+			})
+		})
+
+		Convey("When an event without a callback is delivered", func() {
+			notification, _ := NewAccountNotification(accounts.EventUpdated, account)
+			resp, _ := Deliver(handler, secret, notification)
+
+			Convey("Then it is acknowledged and ignored", func() {
+				So(resp.StatusCode, ShouldEqual, http.StatusNoContent)
+				So(len(created)+len(deleted), ShouldEqual, 0)
+			})
+		})
+
+		Convey("When a delivery is signed with another secret", func() {
+			notification, _ := NewAccountNotification(accounts.EventDeleted, account)
+			resp, _ := Deliver(handler, []byte("another secret"), notification)
+
+			Convey("Then it is rejected", func() {
+				So(resp.StatusCode, ShouldEqual, http.StatusUnauthorized)
+				So(len(deleted), ShouldEqual, 0)
+			})
+		})
+
+		Convey("When a delivery is not signed", func() {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(`{"id":"1"}`))))
+
+			Convey("Then it is rejected", func() {
+				So(recorder.Code, ShouldEqual, http.StatusUnauthorized)
+			})
+		})
+
+		Convey("When a signed delivery is not a notification", func() {
+			body := []byte(`{"id":`)
+			now := time.Now()
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+			req.Header.Set(DefaultTimestampHeader, strconv.FormatInt(now.Unix(), 10))
+			req.Header.Set(DefaultSignatureHeader, "sha256="+Sign(secret, now, body))
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			Convey("Then it is a bad request", func() {
+				So(recorder.Code, ShouldEqual, http.StatusBadRequest)
+			})
+		})
+	})
+
+	Convey("Given a handler whose callback is still running", t, func() {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		var calls int
+		handler, err := NewHandler().
+			Secret(secret).
+			OnAccountCreated(func(ctx context.Context, n Notification, account accounts.AccountData) error {
+				calls++
+				close(started)
+				<-release
+				return nil
+			}).
+			Build()
+		So(err, ShouldBeNil)
+
+		notification, _ := NewAccountNotification(accounts.EventCreated, newAccount())
+		first := make(chan *http.Response)
+		go func() {
+			resp, _ := Deliver(handler, secret, notification)
+			first <- resp
+		}()
+		<-started
+
+		Convey("When the notification is redelivered meanwhile", func() {
+			resp, _ := Deliver(handler, secret, notification)
+			close(release)
+
+			Convey("Then the redelivery is answered with a retryable conflict rather than acknowledged", func() {
+				So(resp.StatusCode, ShouldEqual, http.StatusConflict)
+				So(resp.Header.Get("Retry-After"), ShouldEqual, "1")
+				So((<-first).StatusCode, ShouldEqual, http.StatusNoContent)
+			})
+
+			Convey("And once handled its redelivery is acknowledged without being dispatched again", func() {
+				So((<-first).StatusCode, ShouldEqual, http.StatusNoContent)
+				resp, _ := Deliver(handler, secret, notification)
+				So(resp.StatusCode, ShouldEqual, http.StatusNoContent)
+				So(calls, ShouldEqual, 1)
+			})
+		})
+	})
+
+	Convey("Given a handler with a callback for created accounts", t, func() {
+		var created []accounts.AccountData
+		builder := NewHandler().
+			Secret(secret).
+			OnAccountCreated(func(ctx context.Context, n Notification, account accounts.AccountData) error {
+				created = append(created, account)
+				return nil
+			})
+		handler, err := builder.Build()
+		So(err, ShouldBeNil)
+
+		notification, _ := NewAccountNotification(accounts.EventCreated, newAccount())
+
+		Convey("When a delivery was signed longer ago than the tolerance", func() {
+			resp, _ := Deliver(handler, secret, notification, WithTimestamp(time.Now().Add(-DefaultTolerance-time.Minute)))
+
+			Convey("Then it is rejected as a replay", func() {
+				So(resp.StatusCode, ShouldEqual, http.StatusUnauthorized)
+				So(len(created), ShouldEqual, 0)
+			})
+		})
+
+		Convey("When a delivery is signed further in the future than the tolerance", func() {
+			resp, _ := Deliver(handler, secret, notification, WithTimestamp(time.Now().Add(DefaultTolerance+time.Minute)))
+
+			Convey("Then it is rejected", func() {
+				So(resp.StatusCode, ShouldEqual, http.StatusUnauthorized)
+			})
+		})
+
+		Convey("When the timestamp of a delivery is changed after signing", func() {
+			body := []byte(`{"id":"1","event_type":"created","record_type":"accounts","data":{}}`)
+			signedAt := time.Now().Add(-DefaultTolerance - time.Minute)
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+			req.Header.Set(DefaultTimestampHeader, strconv.FormatInt(time.Now().Unix(), 10))
+			req.Header.Set(DefaultSignatureHeader, Sign(secret, signedAt, body))
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			Convey("Then it is rejected", func() {
+				So(recorder.Code, ShouldEqual, http.StatusUnauthorized)
+				So(len(created), ShouldEqual, 0)
+			})
+		})
+
+		Convey("When a delivery has no timestamp", func() {
+			body := []byte(`{"id":"1","event_type":"created","record_type":"accounts","data":{}}`)
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+			req.Header.Set(DefaultSignatureHeader, Sign(secret, time.Now(), body))
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			Convey("Then it is rejected", func() {
+				So(recorder.Code, ShouldEqual, http.StatusUnauthorized)
+			})
+		})
+
+		Convey("When the tolerance is widened and an older delivery is made", func() {
+			handler, _ := builder.Tolerance(time.Hour).Build()
+			resp, _ := Deliver(handler, secret, notification, WithTimestamp(time.Now().Add(-DefaultTolerance-time.Minute)))
+
+			Convey("Then it is dispatched", func() {
+				So(resp.StatusCode, ShouldEqual, http.StatusNoContent)
+				So(len(created), ShouldEqual, 1)
+			})
+		})
+	})
+
+	Convey("Given a handler reading the signature and timestamp from other headers", t, func() {
+		var created []accounts.AccountData
+		handler, err := NewHandler().
+			Secret(secret).
+			SignatureHeader("X-Signature").
+			TimestampHeader("X-Timestamp").
+			OnAccountCreated(func(ctx context.Context, n Notification, account accounts.AccountData) error {
+				created = append(created, account)
+				return nil
+			}).
+			Build()
+		So(err, ShouldBeNil)
+
+		notification, _ := NewAccountNotification(accounts.EventCreated, newAccount())
+
+		Convey("When a delivery is signed in that header", func() {
+			resp, _ := Deliver(handler, secret, notification, WithSignatureHeader("X-Signature"), WithTimestampHeader("X-Timestamp"))
+
+			Convey("Then it is dispatched", func() {
+				So(resp.StatusCode, ShouldEqual, http.StatusNoContent)
+				So(len(created), ShouldEqual, 1)
+			})
+		})
+
+		Convey("When a delivery is signed in the default headers", func() {
+			resp, _ := Deliver(handler, secret, notification)
+
+			Convey("Then it is rejected", func() {
+				So(resp.StatusCode, ShouldEqual, http.StatusUnauthorized)
+				So(len(created), ShouldEqual, 0)
+			})
+		})
+	})
+
+	Convey("When a handler is built without a secret", t, func() {
+		_, err := NewHandler().Build()
+
+		Convey("Then it fails", func() {
+			So(err, ShouldEqual, ErrNoSecret)
+		})
+	})
+}
+
+func TestMemoryDeduplicator(t *testing.T) {
+
+	Convey("Given a deduplicator of two notifications", t, func() {
+		deduplicator := NewMemoryDeduplicator(2)
+
+		Convey("When three notifications are added", func() {
+			So(deduplicator.Add("1"), ShouldEqual, Unseen)
+			So(deduplicator.Add("1"), ShouldEqual, InFlight)
+			So(deduplicator.Add("2"), ShouldEqual, Unseen)
+			So(deduplicator.Add("3"), ShouldEqual, Unseen)
+
+			Convey("Then the oldest is forgotten", func() {
+				So(deduplicator.Add("1"), ShouldEqual, Unseen)
+				So(deduplicator.Add("3"), ShouldEqual, InFlight)
+			})
+		})
+
+		Convey("When a notification is added and done", func() {
+			So(deduplicator.Add("1"), ShouldEqual, Unseen)
+			deduplicator.Done("1")
+
+			Convey("Then it is handled", func() {
+				So(deduplicator.Add("1"), ShouldEqual, Handled)
+			})
+		})
+
+		Convey("When a notification is removed and added again", func() {
+			So(deduplicator.Add("1"), ShouldEqual, Unseen)
+			deduplicator.Remove("1")
+			So(deduplicator.Add("1"), ShouldEqual, Unseen)
+			So(deduplicator.Add("2"), ShouldEqual, Unseen)
+
+			Convey("Then it only takes one place", func() {
+				So(deduplicator.Add("1"), ShouldEqual, InFlight)
+				So(deduplicator.Add("2"), ShouldEqual, InFlight)
+			})
+		})
+	})
+}